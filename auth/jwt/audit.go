@@ -0,0 +1,15 @@
+package jwt
+
+import (
+	"context"
+
+	"github.com/shemic/dever/orm"
+)
+
+// RegisterAuditActor 将审计日志的操作人设为当前生效 scheme 的用户标识（claimKeys，默认 uid、sub），应在启动时调用一次。
+func RegisterAuditActor() {
+	orm.RegisterAuditActor(func(ctx context.Context) string {
+		actor, _ := ActiveString(ctx)
+		return actor
+	})
+}
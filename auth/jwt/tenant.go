@@ -0,0 +1,44 @@
+package jwt
+
+import (
+	"github.com/shemic/dever/middleware"
+	"github.com/shemic/dever/orm"
+	"github.com/shemic/dever/server"
+)
+
+// TenantOptions 配置 Tenant 中间件。
+type TenantOptions struct {
+	Scheme         string   // 读取的认证方案，默认使用当前生效的方案
+	Claims         []string // 租户声明键，默认 tenant_id、tenant
+	Allow          func(*server.Context) bool
+	AllowMissing   func(*server.Context) bool
+	OnUnauthorized func(*server.Context, string) error
+	PublicPaths    []string
+}
+
+// Tenant 从已校验的 JWT 声明中读取租户并写入 orm.WithTenant，需注册在 UseConfigured/Require 之后。
+// 缺少租户声明时按未认证处理，除非 AllowMissing 返回 true。
+func Tenant(options TenantOptions) middleware.ContextFunc {
+	keys := normalizeClaimKeys(options.Claims)
+	if len(keys) == 0 {
+		keys = []string{"tenant_id", "tenant"}
+	}
+	return func(ctx any) error {
+		c, ok := ctx.(*server.Context)
+		if !ok || c == nil {
+			return nil
+		}
+		if shouldBypass(c, options.Allow) || isPublicPath(c.Path(), options.PublicPaths) {
+			return nil
+		}
+		tenant, ok := String(c.Context(), options.Scheme, keys...)
+		if !ok {
+			if options.AllowMissing != nil && options.AllowMissing(c) {
+				return nil
+			}
+			return unauthorized(c, Options{OnUnauthorized: options.OnUnauthorized}, "缺少租户信息")
+		}
+		c.SetContext(orm.WithTenant(c.Context(), tenant))
+		return nil
+	}
+}
@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"github.com/shemic/dever/orm"
+)
+
+// RunDataDump 将指定数据库中 data/table 记录的表导出为 dir 下的 <table>.jsonl，tables 为空时导出全部。
+func RunDataDump(projectRoot, target, dir string, tables []string) error {
+	closeDB, err := openMigrationDatabase(projectRoot, target)
+	if err != nil {
+		return err
+	}
+	defer closeDB()
+
+	results, err := orm.DumpData(context.Background(), target, resolveDataDir(projectRoot, dir), orm.DataOptions{Tables: tables})
+	printDataResult("导出", results)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("数据库 %s 已导出 %d 张表\n", target, len(results))
+	return nil
+}
+
+// RunDataLoad 将 dir 下的 <table>.jsonl 按外键依赖顺序导入指定数据库，默认先清空要导入的表，appendRows 为 true 时保留已有数据。
+func RunDataLoad(projectRoot, target, dir string, tables []string, batchSize int, appendRows bool) error {
+	closeDB, err := openMigrationDatabase(projectRoot, target)
+	if err != nil {
+		return err
+	}
+	defer closeDB()
+
+	results, err := orm.LoadData(context.Background(), target, resolveDataDir(projectRoot, dir), orm.DataOptions{
+		Tables:    tables,
+		BatchSize: batchSize,
+		Append:    appendRows,
+	})
+	if err != nil {
+		return err
+	}
+	printDataResult("导入", results)
+	fmt.Printf("数据库 %s 已导入 %d 张表\n", target, len(results))
+	return nil
+}
+
+func resolveDataDir(projectRoot, dir string) string {
+	if filepath.IsAbs(dir) {
+		return dir
+	}
+	return filepath.Join(projectRoot, dir)
+}
+
+func printDataResult(verb string, results []orm.DataTable) {
+	for _, result := range results {
+		fmt.Printf("  - %s %s %d 行（%s）\n", verb, result.Table, result.Rows, result.File)
+	}
+}
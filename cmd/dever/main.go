@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"path/filepath"
 	"strings"
 
 	devercmd "github.com/shemic/dever/cmd"
@@ -49,6 +50,10 @@ func main() {
 		runComponent(os.Args[2:])
 	case "migrate":
 		runMigrate(os.Args[2:])
+	case "data":
+		runData(os.Args[2:])
+	case "schema":
+		runSchema(os.Args[2:])
 	case "install":
 		runInstall(os.Args[2:])
 	case "update":
@@ -78,8 +83,16 @@ Usage:
     dever routes [--project-root=.]               # 仅生成路由
     dever service [--project-root=.]              # 仅生成 service 注册
     dever model [--project-root=.]                # 仅生成 model 注册
+    dever model import <database> [--tables=a,b] [--module=name] [--force] # 从已有数据库反向生成模型与 data/table 记录
     dever component [--project-root=.]            # 仅生成 component 注册
     dever migrate [--project-root=.] <database>   # 应用 data/table 中记录的表结构到目标数据库
+    dever migrate --plan [--out=path] [--fail-on-destructive] <database> # 只输出待执行的结构变更，破坏性变更可让 CI 失败
+    dever migrate make <database> [name]          # 根据 data/table 与数据库差异生成 up/down 迁移文件
+    dever migrate status|up|down|redo <database> [--steps=N] # 查看/执行/回滚版本化迁移
+    dever migrate diff <database> [--json] [--fail-on-drift] # 只读对比 data/table 与数据库现状，报告结构漂移
+    dever data dump <database> [--tables=a,b] [--out=dump] # 按 data/table 记录的表结构把表数据导出为 JSONL
+    dever data load <database> [--tables=a,b] [--in=dump] [--batch=500] [--append] # 按依赖顺序导入 JSONL，默认先清空目标表
+    dever schema doc [--project-root=.] [--out=docs/schema] # 按模块/包生成表结构 Markdown 文档与 Mermaid/Graphviz ER 图
     dever install [--project-root=.] [--bin-dir=] [--skip-skills] # 安装启动脚本，并默认同步 AI skill
     dever update [--project-root=.] [--bin-dir=] [--ref=main] [--skip-framework] # 从 GitHub 更新 dever 命令和当前项目框架依赖，默认追 main
     dever push [--project-root=.] [--message=edit|-m edit] # git status/add/commit/push，并按 dever.json.version 推 tag
@@ -127,9 +140,40 @@ func runService(args []string) {
 func runModel(args []string) {
 	fs := flag.NewFlagSet("model", flag.ExitOnError)
 	projectRoot := fs.String("project-root", ".", "项目根目录（默认当前目录）")
+	tables := fs.String("tables", "", "import 只导入的表，逗号分隔（默认全部）")
+	module := fs.String("module", "", "import 生成到 module/<module>/model（默认与数据库名相同）")
+	force := fs.Bool("force", false, "import 覆盖已存在的模型文件")
 	if err := fs.Parse(args); err != nil {
 		log.Fatalf("model 参数解析失败: %v", err)
 	}
+	if fs.Arg(0) == "import" {
+		if err := fs.Parse(fs.Args()[1:]); err != nil {
+			log.Fatalf("model 参数解析失败: %v", err)
+		}
+		rest := fs.Args()
+		if len(rest) < 1 || strings.TrimSpace(rest[0]) == "" {
+			log.Fatal("model import 需要指定数据库名称，例如：dever model import default --tables=user,role")
+		}
+		target := strings.TrimSpace(rest[0])
+		// 允许把参数写在数据库名称之后，例如 dever model import default --tables=user
+		if err := fs.Parse(rest[1:]); err != nil {
+			log.Fatalf("model 参数解析失败: %v", err)
+		}
+		root := resolveProjectRoot(*projectRoot)
+		if err := os.Chdir(root); err != nil {
+			log.Fatalf("切换到项目目录失败: %v", err)
+		}
+		var names []string
+		for _, name := range strings.Split(*tables, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				names = append(names, name)
+			}
+		}
+		if err := devercmd.ImportModels(root, target, *module, names, *force); err != nil {
+			log.Fatalf("model 导入失败: %v", err)
+		}
+		return
+	}
 	root := resolveProjectRoot(*projectRoot)
 	if err := devercmd.GenerateModels(root); err != nil {
 		log.Fatalf("model 生成失败: %v", err)
@@ -151,22 +195,134 @@ func runComponent(args []string) {
 func runMigrate(args []string) {
 	fs := flag.NewFlagSet("migrate", flag.ExitOnError)
 	projectRoot := fs.String("project-root", ".", "项目根目录（默认当前目录）")
+	steps := fs.Int("steps", 0, "up 最多执行的迁移数（0 为全部），down/redo 回滚的迁移数（0 为 1）")
+	plan := fs.Bool("plan", false, "只计算并输出待执行的结构变更，不执行")
+	output := fs.String("out", "", "--plan 的输出文件或目录（默认打印到标准输出）")
+	failOnDestructive := fs.Bool("fail-on-destructive", false, "--plan 存在删表重建、删除列、收窄列类型等破坏性变更时以非零状态退出")
+	jsonOutput := fs.Bool("json", false, "diff 以 JSON 输出差异")
+	failOnDrift := fs.Bool("fail-on-drift", false, "diff 发现结构差异时以非零状态退出")
 	if err := fs.Parse(args); err != nil {
 		log.Fatalf("migrate 参数解析失败: %v", err)
 	}
-	if fs.NArg() < 1 {
-		log.Fatal("migrate 需要指定目标数据库名称，例如：dever migrate default")
+	rest := fs.Args()
+	action := ""
+	if len(rest) > 0 {
+		switch rest[0] {
+		case "make", "status", "up", "down", "redo", "diff":
+			action = rest[0]
+			rest = rest[1:]
+		}
+	}
+	if len(rest) < 1 {
+		log.Fatal("migrate 需要指定目标数据库名称，例如：dever migrate default 或 dever migrate up default")
 	}
-	target := strings.TrimSpace(fs.Arg(0))
+	target := strings.TrimSpace(rest[0])
 	if target == "" {
 		log.Fatal("数据库名称不能为空")
 	}
+	// 允许把参数写在数据库名称之后，例如 dever migrate down default --steps=2
+	if err := fs.Parse(rest[1:]); err != nil {
+		log.Fatalf("migrate 参数解析失败: %v", err)
+	}
+	name := strings.TrimSpace(fs.Arg(0))
 
 	root := resolveProjectRoot(*projectRoot)
 	if err := os.Chdir(root); err != nil {
 		log.Fatalf("切换到项目目录失败: %v", err)
 	}
-	if err := devercmd.RunMigrations(root, target); err != nil {
+	if *plan {
+		if action != "" {
+			log.Fatal("--plan 不能与 make/status/up/down/redo/diff 同时使用")
+		}
+		if err := devercmd.RunMigrationPlan(root, target, *output, *failOnDestructive); err != nil {
+			log.Fatalf("迁移计划检查失败: %v", err)
+		}
+		return
+	}
+	if *failOnDestructive {
+		log.Fatal("--fail-on-destructive 需要与 --plan 一起使用")
+	}
+	if *failOnDrift && action != "diff" {
+		log.Fatal("--fail-on-drift 需要与 diff 一起使用")
+	}
+	if action == "diff" {
+		if err := devercmd.RunMigrationDiff(root, target, *jsonOutput, *failOnDrift); err != nil {
+			log.Fatalf("结构差异检测失败: %v", err)
+		}
+		return
+	}
+	if action == "" {
+		if err := devercmd.RunMigrations(root, target); err != nil {
+			log.Fatalf("数据库迁移失败: %v", err)
+		}
+		return
+	}
+	if err := devercmd.RunMigrationCommand(root, action, target, *steps, name); err != nil {
 		log.Fatalf("数据库迁移失败: %v", err)
 	}
 }
+
+func runData(args []string) {
+	fs := flag.NewFlagSet("data", flag.ExitOnError)
+	projectRoot := fs.String("project-root", ".", "项目根目录（默认当前目录）")
+	tables := fs.String("tables", "", "只处理的表，逗号分隔（默认全部）")
+	output := fs.String("out", "dump", "dump 的输出目录")
+	input := fs.String("in", "dump", "load 读取的目录")
+	batch := fs.Int("batch", 500, "load 每批插入的行数")
+	appendRows := fs.Bool("append", false, "load 保留目标表已有数据，不先清空")
+	if err := fs.Parse(args); err != nil {
+		log.Fatalf("data 参数解析失败: %v", err)
+	}
+	rest := fs.Args()
+	if len(rest) < 2 || (rest[0] != "dump" && rest[0] != "load") || strings.TrimSpace(rest[1]) == "" {
+		log.Fatal("data 需要指定 dump/load 与数据库名称，例如：dever data dump default --out=dump")
+	}
+	action, target := rest[0], strings.TrimSpace(rest[1])
+	// 允许把参数写在数据库名称之后，例如 dever data load default --in=dump
+	if err := fs.Parse(rest[2:]); err != nil {
+		log.Fatalf("data 参数解析失败: %v", err)
+	}
+	var names []string
+	for _, name := range strings.Split(*tables, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+
+	root := resolveProjectRoot(*projectRoot)
+	if err := os.Chdir(root); err != nil {
+		log.Fatalf("切换到项目目录失败: %v", err)
+	}
+	if action == "dump" {
+		if err := devercmd.RunDataDump(root, target, *output, names); err != nil {
+			log.Fatalf("数据导出失败: %v", err)
+		}
+		return
+	}
+	if err := devercmd.RunDataLoad(root, target, *input, names, *batch, *appendRows); err != nil {
+		log.Fatalf("数据导入失败: %v", err)
+	}
+}
+
+func runSchema(args []string) {
+	fs := flag.NewFlagSet("schema", flag.ExitOnError)
+	projectRoot := fs.String("project-root", ".", "项目根目录（默认当前目录）")
+	output := fs.String("out", filepath.Join("docs", "schema"), "文档输出目录")
+	if err := fs.Parse(args); err != nil {
+		log.Fatalf("schema 参数解析失败: %v", err)
+	}
+	if fs.Arg(0) != "doc" {
+		log.Fatal("schema 需要指定子命令，例如：dever schema doc --out=docs/schema")
+	}
+	// 允许把参数写在子命令之后，例如 dever schema doc --out=docs/schema
+	if err := fs.Parse(fs.Args()[1:]); err != nil {
+		log.Fatalf("schema 参数解析失败: %v", err)
+	}
+	root := resolveProjectRoot(*projectRoot)
+	if err := os.Chdir(root); err != nil {
+		log.Fatalf("切换到项目目录失败: %v", err)
+	}
+	if err := devercmd.RunSchemaDoc(root, *output); err != nil {
+		log.Fatalf("结构文档生成失败: %v", err)
+	}
+}
@@ -2,7 +2,9 @@ package cmd
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"os"
 	"path/filepath"
 	"strings"
 	"time"
@@ -13,10 +15,243 @@ import (
 
 // RunMigrations 读取 data/table 下记录的表结构，应用到指定数据库。
 func RunMigrations(projectRoot, target string) error {
+	closeDB, err := openMigrationDatabase(projectRoot, target)
+	if err != nil {
+		return err
+	}
+	defer closeDB()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	statements, err := orm.ApplyRecordedSchemas(ctx, target)
+	if err != nil {
+		return fmt.Errorf("应用表结构失败: %w", err)
+	}
+
+	if len(statements) == 0 {
+		fmt.Printf("数据库 %s 已经是最新结构\n", target)
+		return nil
+	}
+
+	fmt.Printf("数据库 %s 已应用 %d 条结构变更：\n", target, len(statements))
+	for _, stmt := range statements {
+		fmt.Printf("  - %s\n", stmt)
+	}
+	return nil
+}
+
+// RunMigrationPlan 计算 data/table 与指定数据库的结构差异但不执行；output 为空时打印到标准输出，
+// 为目录时写入 <database>.<driver>.plan.sql。failOnDestructive 为 true 且存在破坏性变更时返回错误。
+func RunMigrationPlan(projectRoot, target, output string, failOnDestructive bool) error {
+	closeDB, err := openMigrationDatabase(projectRoot, target)
+	if err != nil {
+		return err
+	}
+	defer closeDB()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	plan, err := orm.PlanSchemas(ctx, target)
+	if err != nil {
+		return fmt.Errorf("计算结构变更失败: %w", err)
+	}
+	destructive := plan.Destructive()
+	if len(plan.Changes) == 0 {
+		fmt.Printf("数据库 %s 已经是最新结构\n", target)
+		return nil
+	}
+
+	if output == "" {
+		fmt.Print(plan.SQL())
+	} else {
+		if info, err := os.Stat(output); err == nil && info.IsDir() {
+			output = filepath.Join(output, fmt.Sprintf("%s.%s.plan.sql", plan.Database, plan.Driver))
+		}
+		if err := os.MkdirAll(filepath.Dir(output), 0o755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(output, []byte(plan.SQL()), 0o644); err != nil {
+			return fmt.Errorf("写入迁移计划失败: %w", err)
+		}
+		fmt.Printf("数据库 %s 共 %d 项结构变更（破坏性 %d 项），已写入 %s\n", target, len(plan.Changes), len(destructive), output)
+	}
+
+	if failOnDestructive && len(destructive) > 0 {
+		names := make([]string, 0, len(destructive))
+		for _, change := range destructive {
+			names = append(names, change.Kind+" "+change.Table)
+		}
+		return fmt.Errorf("存在 %d 项破坏性变更：%s", len(destructive), strings.Join(names, "、"))
+	}
+	return nil
+}
+
+// RunMigrationDiff 只读对比 data/table 与指定数据库的结构差异；jsonOutput 为 true 时输出 JSON，便于监控采集；
+// failOnDrift 为 true 时存在差异会返回 error，命令以非零状态退出。
+func RunMigrationDiff(projectRoot, target string, jsonOutput, failOnDrift bool) error {
+	closeDB, err := openMigrationDatabase(projectRoot, target)
+	if err != nil {
+		return err
+	}
+	defer closeDB()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	drift, err := orm.DetectDrift(ctx, target)
+	if err != nil {
+		return fmt.Errorf("检测结构差异失败: %w", err)
+	}
+	if jsonOutput {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(drift); err != nil {
+			return err
+		}
+		return driftError(target, drift, failOnDrift)
+	}
+	if !drift.HasDrift() {
+		fmt.Printf("数据库 %s 与 data/table 一致\n", target)
+		return nil
+	}
+	fmt.Printf("数据库 %s 与 data/table 存在差异：\n", target)
+	for _, table := range drift.UnknownTables {
+		fmt.Printf("  %s: 未记录的表\n", table)
+	}
+	for _, table := range drift.Tables {
+		if table.Missing {
+			fmt.Printf("  %s: 表不存在\n", table.Table)
+			continue
+		}
+		printDriftItems(table.Table, "缺少列", table.MissingColumns)
+		printDriftItems(table.Table, "多余列", table.ExtraColumns)
+		for _, column := range table.TypeMismatches {
+			fmt.Printf("  %s: 列 %s 类型为 %s，应为 %s\n", table.Table, column.Column, column.Actual, column.Expected)
+		}
+		printDriftItems(table.Table, "缺少索引", table.MissingIndexes)
+		printDriftItems(table.Table, "多余索引", table.ExtraIndexes)
+		printDriftItems(table.Table, "索引定义不同", table.ChangedIndexes)
+		printDriftItems(table.Table, "缺少外键", table.MissingForeignKeys)
+		printDriftItems(table.Table, "多余外键", table.ExtraForeignKeys)
+		for _, seed := range table.Seeds {
+			if seed.Missing {
+				fmt.Printf("  %s: 种子数据 %v 不存在\n", table.Table, seed.Key)
+				continue
+			}
+			fmt.Printf("  %s: 种子数据 %v 已被修改 %v\n", table.Table, seed.Key, seed.Columns)
+		}
+	}
+	return driftError(target, drift, failOnDrift)
+}
+
+func driftError(target string, drift orm.SchemaDrift, failOnDrift bool) error {
+	if failOnDrift && drift.HasDrift() {
+		return fmt.Errorf("数据库 %s 与 data/table 存在差异", target)
+	}
+	return nil
+}
+
+func printDriftItems(table, label string, items []string) {
+	if len(items) > 0 {
+		fmt.Printf("  %s: %s %s\n", table, label, strings.Join(items, ", "))
+	}
+}
+
+// RunMigrationCommand 执行版本化迁移子命令：make 生成迁移文件，status/up/down/redo 管理迁移历史。
+// steps 对 up 表示最多执行的迁移数（0 为全部），对 down/redo 表示回滚的迁移数（0 为 1）。
+func RunMigrationCommand(projectRoot, action, target string, steps int, name string) error {
+	closeDB, err := openMigrationDatabase(projectRoot, target)
+	if err != nil {
+		return err
+	}
+	defer closeDB()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+
+	switch action {
+	case "make":
+		migration, err := orm.GenerateMigration(ctx, target, name)
+		if err != nil {
+			return fmt.Errorf("生成迁移失败: %w", err)
+		}
+		if migration.Version == "" {
+			fmt.Printf("数据库 %s 已经是最新结构，无需生成迁移\n", target)
+			return nil
+		}
+		fmt.Printf("已生成迁移 %s_%s（%d 条语句）：\n", migration.Version, migration.Name, len(migration.UpSQL))
+		for _, stmt := range migration.UpSQL {
+			fmt.Printf("  - %s\n", stmt)
+		}
+		return nil
+	case "status":
+		statuses, err := orm.MigrationStatuses(ctx, target)
+		if err != nil {
+			return fmt.Errorf("读取迁移状态失败: %w", err)
+		}
+		printMigrationStatuses(target, statuses)
+		return nil
+	case "up":
+		applied, err := orm.MigrateUp(ctx, target, steps)
+		printMigrationResult("已执行", applied)
+		if err != nil {
+			return err
+		}
+		if len(applied) == 0 {
+			fmt.Printf("数据库 %s 没有待执行的迁移\n", target)
+		}
+		return nil
+	case "down":
+		reverted, err := orm.MigrateDown(ctx, target, steps)
+		printMigrationResult("已回滚", reverted)
+		if err != nil {
+			return err
+		}
+		if len(reverted) == 0 {
+			fmt.Printf("数据库 %s 没有可回滚的迁移\n", target)
+		}
+		return nil
+	case "redo":
+		reverted, applied, err := orm.MigrateRedo(ctx, target, steps)
+		printMigrationResult("已回滚", reverted)
+		printMigrationResult("已执行", applied)
+		return err
+	default:
+		return fmt.Errorf("未知的迁移命令 %q，可用：make、status、up、down、redo", action)
+	}
+}
+
+func printMigrationStatuses(target string, statuses []orm.MigrationStatus) {
+	if len(statuses) == 0 {
+		fmt.Printf("数据库 %s 没有迁移文件\n", target)
+		return
+	}
+	fmt.Printf("数据库 %s 迁移状态：\n", target)
+	for _, status := range statuses {
+		state := "待执行"
+		if status.Applied {
+			state = fmt.Sprintf("已执行 batch=%d %s", status.Batch, status.AppliedAt.Format("2006-01-02 15:04:05"))
+		}
+		if status.Missing {
+			state += "（迁移文件缺失）"
+		}
+		fmt.Printf("  %s_%s  %s\n", status.Version, status.Name, state)
+	}
+}
+
+func printMigrationResult(verb string, items []orm.MigrationStatus) {
+	for _, item := range items {
+		fmt.Printf("%s %s_%s\n", verb, item.Version, item.Name)
+	}
+}
+
+func openMigrationDatabase(projectRoot, target string) (func(), error) {
 	cfgPath := filepath.Join(projectRoot, config.DefaultPath)
 	cfg, err := config.Load(cfgPath)
 	if err != nil {
-		return fmt.Errorf("读取配置失败: %w", err)
+		return nil, fmt.Errorf("读取配置失败: %w", err)
 	}
 
 	orm.SetDefaultDatabase(cfg.Database.Default)
@@ -26,7 +261,7 @@ func RunMigrations(projectRoot, target string) error {
 	if !ok {
 		defaultCfg, ok := cfg.Database.Connections[cfg.Database.Default]
 		if !ok {
-			return fmt.Errorf("未找到数据库配置 %q，且默认配置缺失", target)
+			return nil, fmt.Errorf("未找到数据库配置 %q，且默认配置缺失", target)
 		}
 		connCfg = defaultCfg
 		if strings.TrimSpace(connCfg.DBName) == "" {
@@ -41,28 +276,9 @@ func RunMigrations(projectRoot, target string) error {
 
 	cfgMap := orm.ConfigFromDBConf(connCfg)
 	if _, err := orm.Init(target, cfgMap); err != nil {
-		return fmt.Errorf("初始化数据库连接失败: %w", err)
+		return nil, fmt.Errorf("初始化数据库连接失败: %w", err)
 	}
-	defer func() {
+	return func() {
 		_ = orm.Close(target)
-	}()
-
-	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
-	defer cancel()
-
-	statements, err := orm.ApplyRecordedSchemas(ctx, target)
-	if err != nil {
-		return fmt.Errorf("应用表结构失败: %w", err)
-	}
-
-	if len(statements) == 0 {
-		fmt.Printf("数据库 %s 已经是最新结构\n", target)
-		return nil
-	}
-
-	fmt.Printf("数据库 %s 已应用 %d 条结构变更：\n", target, len(statements))
-	for _, stmt := range statements {
-		fmt.Printf("  - %s\n", stmt)
-	}
-	return nil
+	}, nil
 }
@@ -0,0 +1,131 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"go/format"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shemic/dever/orm"
+)
+
+// ImportModels 从已有数据库反向生成 module/<module>/model/*.go，写入 data/table 表结构记录，
+// 并重新生成 data/load/model.go。已存在的模型文件默认跳过，force 为 true 时覆盖。
+func ImportModels(projectRoot, target, module string, tables []string, force bool) error {
+	closeDB, err := openMigrationDatabase(projectRoot, target)
+	if err != nil {
+		return err
+	}
+	defer closeDB()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	models, err := orm.ImportModels(ctx, target, tables)
+	if err != nil {
+		return fmt.Errorf("读取表结构失败: %w", err)
+	}
+	if len(models) == 0 {
+		fmt.Printf("数据库 %s 中没有可导入的表\n", target)
+		return nil
+	}
+
+	module = strings.TrimSpace(module)
+	if module == "" {
+		module = target
+	}
+	dir := filepath.Join(projectRoot, "module", module, "model")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("创建 model 目录失败: %w", err)
+	}
+
+	for _, model := range models {
+		path := filepath.Join(dir, strings.ToLower(model.Table)+".go")
+		if _, err := os.Stat(path); err == nil && !force {
+			fmt.Printf("  - 跳过 %s：文件已存在（使用 --force 覆盖）\n", path)
+			continue
+		}
+		code, err := buildImportedModelFile(model)
+		if err != nil {
+			return fmt.Errorf("生成模型 %s 失败: %w", model.Table, err)
+		}
+		if err := os.WriteFile(path, code, 0o644); err != nil {
+			return fmt.Errorf("写入模型 %s 失败: %w", model.Table, err)
+		}
+		if err := model.Record(); err != nil {
+			return fmt.Errorf("记录表结构 %s 失败: %w", model.Table, err)
+		}
+		fmt.Printf("  - %s -> %s\n", model.Table, path)
+	}
+
+	if err := GenerateModels(projectRoot); err != nil {
+		return err
+	}
+	fmt.Printf("数据库 %s 已导入 %d 张表\n", target, len(models))
+	return nil
+}
+
+func buildImportedModelFile(model orm.ImportedModel) ([]byte, error) {
+	imports := map[string]struct{}{"github.com/shemic/dever/orm": {}}
+	for _, field := range model.Fields {
+		if field.Import != "" {
+			imports[field.Import] = struct{}{}
+		}
+	}
+	var std, external []string
+	for path := range imports {
+		if strings.Contains(path, ".") {
+			external = append(external, path)
+		} else {
+			std = append(std, path)
+		}
+	}
+	sort.Strings(std)
+	sort.Strings(external)
+
+	builder := &strings.Builder{}
+	builder.WriteString("package model\n\n")
+	builder.WriteString("import (\n")
+	for _, path := range std {
+		builder.WriteString(strconv.Quote(path) + "\n")
+	}
+	if len(std) > 0 {
+		builder.WriteString("\n")
+	}
+	for _, path := range external {
+		builder.WriteString(strconv.Quote(path) + "\n")
+	}
+	builder.WriteString(")\n\n")
+
+	fmt.Fprintf(builder, "// %s 由 dever model import 根据数据库 %s 的 %s 表生成。\n", model.Struct, model.Database, model.Table)
+	fmt.Fprintf(builder, "type %s struct {\n", model.Struct)
+	for _, field := range model.Fields {
+		fmt.Fprintf(builder, "%s %s `%s`\n", field.Name, field.GoType, field.Tag)
+	}
+	builder.WriteString("}\n\n")
+
+	indexType := ""
+	if len(model.Indexes) > 0 {
+		indexType = model.Struct + "Index"
+		fmt.Fprintf(builder, "type %s struct {\n", indexType)
+		for _, index := range model.Indexes {
+			fmt.Fprintf(builder, "%s struct{} `%s`\n", index.Field, index.Tag())
+		}
+		builder.WriteString("}\n\n")
+	}
+
+	fmt.Fprintf(builder, "func New%sModel() *orm.Model[%s] {\n", model.Struct, model.Struct)
+	fmt.Fprintf(builder, "return orm.LoadModel[%s](%s, %s, orm.ModelConfig{\n", model.Struct, strconv.Quote(model.Name), strconv.Quote(model.Table))
+	if indexType != "" {
+		fmt.Fprintf(builder, "Index: %s{},\n", indexType)
+	}
+	fmt.Fprintf(builder, "Database: %s,\n", strconv.Quote(model.Database))
+	builder.WriteString("})\n}\n")
+
+	return format.Source([]byte(builder.String()))
+}
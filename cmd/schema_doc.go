@@ -0,0 +1,656 @@
+package cmd
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"html"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/shemic/dever/config"
+	"github.com/shemic/dever/orm"
+	"github.com/shemic/dever/util"
+)
+
+// schemaDocGroup 是一个模块或包下的表，未在源码中找到对应模型的表归入“未归属”。
+type schemaDocGroup struct {
+	Key    string
+	Title  string
+	Tables []orm.SchemaDoc
+}
+
+// schemaDocModel 是从 model 源码中静态解析出的 orm.LoadModel 调用。
+type schemaDocModel struct {
+	Name      string
+	Table     string
+	Source    util.ModuleSource
+	Relations []orm.Relation
+}
+
+var mermaidTypePattern = regexp.MustCompile(`[^A-Za-z0-9_()\[\]-]`)
+
+// RunSchemaDoc 读取 data/table 记录的表结构，结合 module/package 源码中
+// orm.LoadModel 字面量声明的模型名与 Relations，按模块、包分组输出 Markdown 参考文档、Mermaid erDiagram 与 Graphviz DOT。
+func RunSchemaDoc(projectRoot, output string) error {
+	docs, err := orm.RecordedSchemaDocs()
+	if err != nil {
+		return fmt.Errorf("读取表结构记录失败: %w", err)
+	}
+	if len(docs) == 0 {
+		return fmt.Errorf("data/table 中没有表结构记录，请先运行项目或执行 dever model import")
+	}
+
+	sources, err := LoadProjectSources(projectRoot)
+	if err != nil {
+		return err
+	}
+	models, err := scanSchemaDocModels(sources)
+	if err != nil {
+		return err
+	}
+	groups := groupSchemaDocs(docs, models, loadTablePrefixes(projectRoot))
+
+	if !filepath.IsAbs(output) {
+		output = filepath.Join(projectRoot, output)
+	}
+	if err := os.MkdirAll(output, 0o755); err != nil {
+		return fmt.Errorf("创建文档目录失败: %w", err)
+	}
+	files := map[string]string{
+		"README.md":  renderSchemaIndex(groups),
+		"schema.mmd": renderSchemaMermaid(allSchemaDocs(groups)),
+		"schema.dot": renderSchemaDOT(groups),
+	}
+	for _, group := range groups {
+		files[group.Key+".md"] = renderSchemaGroup(group)
+	}
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := os.WriteFile(filepath.Join(output, name), []byte(files[name]), 0o644); err != nil {
+			return fmt.Errorf("写入 %s 失败: %w", name, err)
+		}
+	}
+	fmt.Printf("已生成 %d 张表、%d 个分组的结构文档 → %s\n", len(docs), len(groups), output)
+	return nil
+}
+
+func scanSchemaDocModels(sources ProjectSources) ([]schemaDocModel, error) {
+	var models []schemaDocModel
+	for _, source := range sources.ModuleSources {
+		if err := walkGoFilesUnder(source, "model", func(file goSourceFile) error {
+			found, err := parseSchemaDocModels(file.FullPath)
+			if err != nil {
+				return fmt.Errorf("解析文件 %s 失败: %w", file.RelPath, err)
+			}
+			for _, model := range found {
+				model.Source = source
+				models = append(models, model)
+			}
+			return nil
+		}); err != nil {
+			return nil, err
+		}
+	}
+	return models, nil
+}
+
+// parseSchemaDocModels 找出 orm.LoadModel[T]("name", "table", orm.ModelConfig{...}) 调用，
+// 只解析字面量写出的表名与 Relations。
+func parseSchemaDocModels(path string) ([]schemaDocModel, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, path, nil, parser.SkipObjectResolution)
+	if err != nil {
+		return nil, err
+	}
+	var models []schemaDocModel
+	ast.Inspect(file, func(node ast.Node) bool {
+		call, ok := node.(*ast.CallExpr)
+		if !ok || !isLoadModelCall(call.Fun) || len(call.Args) < 2 {
+			return true
+		}
+		table, ok := stringLiteral(call.Args[1])
+		if !ok || strings.TrimSpace(table) == "" {
+			return true
+		}
+		name, _ := stringLiteral(call.Args[0])
+		model := schemaDocModel{Name: name, Table: strings.TrimSpace(table)}
+		if len(call.Args) > 2 {
+			model.Relations = parseRelationLiterals(call.Args[2])
+		}
+		models = append(models, model)
+		return true
+	})
+	return models, nil
+}
+
+func isLoadModelCall(fun ast.Expr) bool {
+	switch expr := fun.(type) {
+	case *ast.IndexExpr:
+		fun = expr.X
+	case *ast.IndexListExpr:
+		fun = expr.X
+	}
+	switch expr := fun.(type) {
+	case *ast.SelectorExpr:
+		return expr.Sel.Name == "LoadModel"
+	case *ast.Ident:
+		return expr.Name == "LoadModel"
+	}
+	return false
+}
+
+func parseRelationLiterals(expr ast.Expr) []orm.Relation {
+	config, ok := expr.(*ast.CompositeLit)
+	if !ok {
+		return nil
+	}
+	var relations []orm.Relation
+	for _, elt := range config.Elts {
+		field, ok := elt.(*ast.KeyValueExpr)
+		if !ok || identName(field.Key) != "Relations" {
+			continue
+		}
+		list, ok := field.Value.(*ast.CompositeLit)
+		if !ok {
+			continue
+		}
+		for _, item := range list.Elts {
+			if literal, ok := item.(*ast.UnaryExpr); ok && literal.Op == token.AND {
+				item = literal.X
+			}
+			relationLit, ok := item.(*ast.CompositeLit)
+			if !ok {
+				continue
+			}
+			var relation orm.Relation
+			for _, relationElt := range relationLit.Elts {
+				kv, ok := relationElt.(*ast.KeyValueExpr)
+				if !ok {
+					continue
+				}
+				value, ok := stringLiteral(kv.Value)
+				if !ok {
+					continue
+				}
+				switch identName(kv.Key) {
+				case "Kind":
+					relation.Kind = value
+				case "Name":
+					relation.Name = value
+				case "Field":
+					relation.Field = value
+				case "Table":
+					relation.Table = value
+				case "Through":
+					relation.Through = value
+				case "OwnerField":
+					relation.OwnerField = value
+				case "TargetField":
+					relation.TargetField = value
+				}
+			}
+			if relation.Table != "" {
+				relations = append(relations, relation)
+			}
+		}
+	}
+	return relations
+}
+
+// stringLiteral 读取字符串字面量，以及 orm.RelationHasOne 等关联类型常量。
+func stringLiteral(expr ast.Expr) (string, bool) {
+	switch value := expr.(type) {
+	case *ast.BasicLit:
+		if value.Kind != token.STRING {
+			return "", false
+		}
+		text, err := strconv.Unquote(value.Value)
+		return text, err == nil
+	case *ast.SelectorExpr:
+		switch value.Sel.Name {
+		case "RelationHasOne":
+			return orm.RelationHasOne, true
+		case "RelationHasMany":
+			return orm.RelationHasMany, true
+		case "RelationManyToMany":
+			return orm.RelationManyToMany, true
+		}
+	}
+	return "", false
+}
+
+func identName(expr ast.Expr) string {
+	if ident, ok := expr.(*ast.Ident); ok {
+		return ident.Name
+	}
+	return ""
+}
+
+// loadTablePrefixes 读取各数据库连接的表前缀，用于把源码中的表名对应到 data/table 中带前缀的表。
+func loadTablePrefixes(projectRoot string) []string {
+	cfg, err := config.Load(filepath.Join(projectRoot, config.DefaultPath))
+	if err != nil {
+		return nil
+	}
+	var prefixes []string
+	for _, conn := range cfg.Database.Connections {
+		if prefix := strings.TrimSpace(conn.Prefix); prefix != "" {
+			prefixes = append(prefixes, prefix)
+		}
+	}
+	sort.Strings(prefixes)
+	return prefixes
+}
+
+func resolveDocTable(name string, tables map[string]string, prefixes []string) string {
+	key := strings.ToLower(strings.TrimSpace(name))
+	if table, ok := tables[key]; ok {
+		return table
+	}
+	for _, prefix := range prefixes {
+		if table, ok := tables[strings.ToLower(prefix)+"_"+key]; ok {
+			return table
+		}
+	}
+	return name
+}
+
+func groupSchemaDocs(docs []orm.SchemaDoc, models []schemaDocModel, prefixes []string) []schemaDocGroup {
+	tables := make(map[string]string, len(docs))
+	for _, doc := range docs {
+		tables[strings.ToLower(doc.Table)] = doc.Table
+	}
+	owners := map[string]schemaDocModel{}
+	for _, model := range models {
+		table := resolveDocTable(model.Table, tables, prefixes)
+		if _, ok := owners[table]; !ok {
+			owners[table] = model
+		}
+	}
+
+	byKey := map[string]*schemaDocGroup{}
+	for _, doc := range docs {
+		key, title := "other", "未归属"
+		if model, ok := owners[doc.Table]; ok {
+			kind := model.Source.Kind
+			if kind == "" {
+				kind = util.ModuleSourceKindModule
+			}
+			key = kind + "-" + sanitizePathSegment(model.Source.Name)
+			title = kind + "/" + model.Source.Name
+			if doc.Model == "" {
+				doc.Model = model.Name
+			}
+			if len(doc.Relations) == 0 {
+				doc.Relations = model.Relations
+			}
+		}
+		// 关联表与外键引用的表解析为实际表名（含前缀）。
+		relations := make([]orm.Relation, len(doc.Relations))
+		for i, relation := range doc.Relations {
+			relation.Table = resolveDocTable(relation.Table, tables, prefixes)
+			if relation.Through != "" {
+				relation.Through = resolveDocTable(relation.Through, tables, prefixes)
+			}
+			relations[i] = relation
+		}
+		doc.Relations = relations
+		foreignKeys := make([]orm.SchemaDocForeignKey, len(doc.ForeignKeys))
+		for i, foreignKey := range doc.ForeignKeys {
+			foreignKey.RefTable = resolveDocTable(foreignKey.RefTable, tables, prefixes)
+			foreignKeys[i] = foreignKey
+		}
+		doc.ForeignKeys = foreignKeys
+		group, ok := byKey[key]
+		if !ok {
+			group = &schemaDocGroup{Key: key, Title: title}
+			byKey[key] = group
+		}
+		group.Tables = append(group.Tables, doc)
+	}
+
+	groups := make([]schemaDocGroup, 0, len(byKey))
+	for _, group := range byKey {
+		groups = append(groups, *group)
+	}
+	rank := func(key string) int {
+		switch {
+		case strings.HasPrefix(key, util.ModuleSourceKindModule+"-"):
+			return 0
+		case strings.HasPrefix(key, util.ModuleSourceKindPackage+"-"):
+			return 1
+		default:
+			return 2
+		}
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		if rank(groups[i].Key) != rank(groups[j].Key) {
+			return rank(groups[i].Key) < rank(groups[j].Key)
+		}
+		return groups[i].Key < groups[j].Key
+	})
+	return groups
+}
+
+func allSchemaDocs(groups []schemaDocGroup) []orm.SchemaDoc {
+	var docs []orm.SchemaDoc
+	for _, group := range groups {
+		docs = append(docs, group.Tables...)
+	}
+	return docs
+}
+
+func renderSchemaIndex(groups []schemaDocGroup) string {
+	var builder strings.Builder
+	builder.WriteString("# 数据模型\n\n")
+	builder.WriteString("> 由 `dever schema doc` 根据 data/table 与模型源码生成，请勿手改。整体 ER 图见 `schema.mmd`（Mermaid）与 `schema.dot`（Graphviz）。\n\n")
+	builder.WriteString("| 分组 | 表 |\n| --- | --- |\n")
+	for _, group := range groups {
+		names := make([]string, 0, len(group.Tables))
+		for _, doc := range group.Tables {
+			names = append(names, "`"+doc.Table+"`")
+		}
+		fmt.Fprintf(&builder, "| [%s](%s.md) | %s |\n", group.Title, group.Key, strings.Join(names, "、"))
+	}
+	return builder.String()
+}
+
+func renderSchemaGroup(group schemaDocGroup) string {
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "# %s\n\n", group.Title)
+	builder.WriteString("> 由 `dever schema doc` 生成，请勿手改。[返回目录](README.md)\n\n")
+	builder.WriteString("```mermaid\n")
+	builder.WriteString(renderSchemaMermaid(group.Tables))
+	builder.WriteString("```\n")
+
+	for _, doc := range group.Tables {
+		fmt.Fprintf(&builder, "\n## %s\n\n", doc.Table)
+		var meta []string
+		if doc.Model != "" {
+			meta = append(meta, "模型 `"+doc.Model+"`")
+		}
+		if len(meta) > 0 {
+			builder.WriteString(strings.Join(meta, "，") + "\n\n")
+		}
+
+		builder.WriteString("| 列 | 类型 | 可空 | 默认值 | 说明 |\n| --- | --- | --- | --- | --- |\n")
+		for _, column := range doc.Columns {
+			nullable := "是"
+			if column.NotNull {
+				nullable = "否"
+			}
+			notes := []string{}
+			if column.Primary {
+				notes = append(notes, "主键")
+			}
+			if column.AutoIncrement {
+				notes = append(notes, "自增")
+			}
+			if column.Label != "" {
+				notes = append(notes, column.Label)
+			}
+			defaultValue := ""
+			if column.Default != "" {
+				defaultValue = "`" + column.Default + "`"
+			}
+			fmt.Fprintf(&builder, "| `%s` | %s | %s | %s | %s |\n", column.Name, markdownCell(column.Type), nullable, markdownCell(defaultValue), markdownCell(strings.Join(notes, "，")))
+		}
+
+		if len(doc.Indexes) > 0 {
+			builder.WriteString("\n索引：\n\n")
+			for _, index := range doc.Indexes {
+				kind := "普通"
+				if index.Unique {
+					kind = "唯一"
+				}
+				fmt.Fprintf(&builder, "- `%s`（%s）：%s\n", index.Name, kind, strings.Join(index.Columns, ", "))
+			}
+		}
+		if len(doc.ForeignKeys) > 0 {
+			builder.WriteString("\n外键：\n\n")
+			for _, foreignKey := range doc.ForeignKeys {
+				fmt.Fprintf(&builder, "- `%s` → `%s.%s`", foreignKey.Column, foreignKey.RefTable, foreignKey.RefColumn)
+				if foreignKey.OnDelete != "" {
+					builder.WriteString(" ON DELETE " + foreignKey.OnDelete)
+				}
+				if foreignKey.OnUpdate != "" {
+					builder.WriteString(" ON UPDATE " + foreignKey.OnUpdate)
+				}
+				builder.WriteString("\n")
+			}
+		}
+		if len(doc.Relations) > 0 {
+			builder.WriteString("\n关联：\n\n")
+			for _, relation := range doc.Relations {
+				fmt.Fprintf(&builder, "- `%s`：%s `%s`", relationName(relation), relationKind(relation), relation.Table)
+				if relation.Through != "" {
+					fmt.Fprintf(&builder, "，中间表 `%s`", relation.Through)
+				}
+				if relation.OwnerField != "" {
+					fmt.Fprintf(&builder, "，关联字段 `%s`", relation.OwnerField)
+				}
+				builder.WriteString("\n")
+			}
+		}
+	}
+	return builder.String()
+}
+
+// renderSchemaMermaid 输出 docs 中表的 erDiagram；关系另一端在其他分组时只显示表名。
+func renderSchemaMermaid(docs []orm.SchemaDoc) string {
+	var builder strings.Builder
+	builder.WriteString("erDiagram\n")
+	included := map[string]bool{}
+	for _, doc := range docs {
+		included[doc.Table] = true
+	}
+	for _, doc := range docs {
+		foreign := foreignKeyColumns(doc)
+		unique := uniqueColumns(doc)
+		fmt.Fprintf(&builder, "    %s {\n", doc.Table)
+		for _, column := range doc.Columns {
+			var keys []string
+			if column.Primary {
+				keys = append(keys, "PK")
+			}
+			if foreign[column.Name] {
+				keys = append(keys, "FK")
+			}
+			if unique[column.Name] && !column.Primary {
+				keys = append(keys, "UK")
+			}
+			line := fmt.Sprintf("        %s %s", mermaidType(column.Type), column.Name)
+			if len(keys) > 0 {
+				line += " " + strings.Join(keys, ", ")
+			}
+			if column.Label != "" {
+				line += " \"" + strings.ReplaceAll(column.Label, `"`, "'") + "\""
+			}
+			builder.WriteString(line + "\n")
+		}
+		builder.WriteString("    }\n")
+	}
+	for _, edge := range schemaEdges(docs) {
+		if !included[edge.from] && !included[edge.to] {
+			continue
+		}
+		fmt.Fprintf(&builder, "    %s %s %s : \"%s\"\n", edge.from, edge.mermaid, edge.to, strings.ReplaceAll(edge.label, `"`, "'"))
+	}
+	return builder.String()
+}
+
+func renderSchemaDOT(groups []schemaDocGroup) string {
+	var builder strings.Builder
+	builder.WriteString("digraph schema {\n")
+	builder.WriteString("    graph [rankdir=LR, fontname=\"Helvetica\", fontsize=12];\n")
+	builder.WriteString("    node [shape=plaintext, fontname=\"Helvetica\", fontsize=10];\n")
+	builder.WriteString("    edge [fontname=\"Helvetica\", fontsize=9, color=\"#555555\"];\n")
+	for _, group := range groups {
+		fmt.Fprintf(&builder, "\n    subgraph \"cluster_%s\" {\n", group.Key)
+		fmt.Fprintf(&builder, "        label=\"%s\";\n        style=\"rounded\";\n        color=\"#999999\";\n", dotEscape(group.Title))
+		for _, doc := range group.Tables {
+			fmt.Fprintf(&builder, "        \"%s\" [label=<%s>];\n", doc.Table, dotTableLabel(doc))
+		}
+		builder.WriteString("    }\n")
+	}
+	builder.WriteString("\n")
+	for _, edge := range schemaEdges(allSchemaDocs(groups)) {
+		if edge.foreignKey {
+			fmt.Fprintf(&builder, "    \"%s\":\"%s\" -> \"%s\":\"%s\" [label=\"%s\"];\n", edge.child, edge.childColumn, edge.from, edge.fromColumn, dotEscape(edge.label))
+			continue
+		}
+		fmt.Fprintf(&builder, "    \"%s\" -> \"%s\" [style=dashed, label=\"%s\"];\n", edge.from, edge.to, dotEscape(edge.label))
+	}
+	builder.WriteString("}\n")
+	return builder.String()
+}
+
+func dotTableLabel(doc orm.SchemaDoc) string {
+	var builder strings.Builder
+	builder.WriteString(`<TABLE BORDER="0" CELLBORDER="1" CELLSPACING="0" CELLPADDING="4">`)
+	fmt.Fprintf(&builder, `<TR><TD BGCOLOR="#E8EEF7"><B>%s</B></TD></TR>`, html.EscapeString(doc.Table))
+	for _, column := range doc.Columns {
+		text := column.Name + " : " + column.Type
+		if column.Primary {
+			text += " (PK)"
+		}
+		if column.Label != "" {
+			text += " — " + column.Label
+		}
+		fmt.Fprintf(&builder, `<TR><TD PORT="%s" ALIGN="LEFT">%s</TD></TR>`, column.Name, html.EscapeString(text))
+	}
+	builder.WriteString(`</TABLE>`)
+	return builder.String()
+}
+
+// schemaEdge 是 ER 图中的一条边：外键从被引用表指向引用表，关联从定义关联的表指向关联表。
+type schemaEdge struct {
+	from        string
+	fromColumn  string
+	to          string
+	child       string
+	childColumn string
+	mermaid     string
+	label       string
+	foreignKey  bool
+}
+
+// schemaEdges 汇总外键与 Relations，两表之间已有外键时不再重复绘制同向的关联。
+func schemaEdges(docs []orm.SchemaDoc) []schemaEdge {
+	var edges []schemaEdge
+	linked := map[string]bool{}
+	pair := func(a, b string) string {
+		if a > b {
+			a, b = b, a
+		}
+		return a + "|" + b
+	}
+	for _, doc := range docs {
+		notNull := map[string]bool{}
+		for _, column := range doc.Columns {
+			notNull[column.Name] = column.NotNull
+		}
+		for _, foreignKey := range doc.ForeignKeys {
+			cardinality := "|o--o{"
+			if notNull[foreignKey.Column] {
+				cardinality = "||--o{"
+			}
+			edges = append(edges, schemaEdge{
+				from:        foreignKey.RefTable,
+				fromColumn:  foreignKey.RefColumn,
+				to:          doc.Table,
+				child:       doc.Table,
+				childColumn: foreignKey.Column,
+				mermaid:     cardinality,
+				label:       foreignKey.Column,
+				foreignKey:  true,
+			})
+			linked[pair(doc.Table, foreignKey.RefTable)] = true
+		}
+	}
+	for _, doc := range docs {
+		for _, relation := range doc.Relations {
+			if linked[pair(doc.Table, relation.Table)] && relation.Through == "" {
+				continue
+			}
+			cardinality := "||--o{"
+			switch relationKind(relation) {
+			case orm.RelationHasOne:
+				cardinality = "||--o|"
+			case orm.RelationManyToMany:
+				cardinality = "}o--o{"
+			}
+			label := relationName(relation)
+			if relation.Through != "" {
+				label += " via " + relation.Through
+			}
+			edges = append(edges, schemaEdge{from: doc.Table, to: relation.Table, mermaid: cardinality, label: label})
+		}
+	}
+	return edges
+}
+
+func relationKind(relation orm.Relation) string {
+	if relation.Kind != "" {
+		return relation.Kind
+	}
+	if relation.Through != "" {
+		return orm.RelationManyToMany
+	}
+	return orm.RelationHasMany
+}
+
+func relationName(relation orm.Relation) string {
+	if relation.Name != "" {
+		return relation.Name
+	}
+	if relation.Field != "" {
+		return relation.Field
+	}
+	return relation.Table
+}
+
+func foreignKeyColumns(doc orm.SchemaDoc) map[string]bool {
+	result := map[string]bool{}
+	for _, foreignKey := range doc.ForeignKeys {
+		result[foreignKey.Column] = true
+	}
+	return result
+}
+
+func uniqueColumns(doc orm.SchemaDoc) map[string]bool {
+	result := map[string]bool{}
+	for _, index := range doc.Indexes {
+		if index.Unique && len(index.Columns) == 1 {
+			result[index.Columns[0]] = true
+		}
+	}
+	return result
+}
+
+func mermaidType(sqlType string) string {
+	sqlType = strings.ReplaceAll(strings.TrimSpace(sqlType), ",", "-")
+	if sqlType == "" {
+		return "UNKNOWN"
+	}
+	return mermaidTypePattern.ReplaceAllString(sqlType, "_")
+}
+
+func markdownCell(value string) string {
+	value = strings.ReplaceAll(value, "|", `\|`)
+	return strings.ReplaceAll(value, "\n", " ")
+}
+
+func dotEscape(value string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(value, `\`, `\\`), `"`, `\"`)
+}
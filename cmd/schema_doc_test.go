@@ -0,0 +1,199 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeSchemaDocFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func writeSchemaDocTable(t *testing.T, root string, schema map[string]any) {
+	t.Helper()
+	data, err := json.Marshal(schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeSchemaDocFile(t, filepath.Join(root, "data", "table", schema["table"].(string)+".json"), string(data))
+}
+
+func schemaDocColumn(name, typ string, notNull bool) map[string]any {
+	return map[string]any{"name": name, "type": typ, "notNull": notNull, "primary": name == "id", "autoIncrement": name == "id"}
+}
+
+// setupSchemaDocProject 创建带 app 表前缀的项目：shop 模块声明 order、user 模型，其余表没有对应模型。
+func setupSchemaDocProject(t *testing.T) string {
+	t.Helper()
+	root := t.TempDir()
+	writeSchemaDocFile(t, filepath.Join(root, "go.mod"), "module example.com/demo\n\ngo 1.22\n")
+	writeSchemaDocFile(t, filepath.Join(root, "config", "setting.json"), `{"database": {"default": {"driver": "sqlite3", "prefix": "app"}}}`)
+	writeSchemaDocFile(t, filepath.Join(root, "module", "shop", "model", "order.go"), `package model
+
+import "github.com/shemic/dever/orm"
+
+type Order struct{}
+
+type User struct{}
+
+var tableName = "dynamic"
+
+var Orders = orm.LoadModel[Order]("订单", "order", orm.ModelConfig{
+	Relations: []orm.Relation{
+		{Kind: orm.RelationHasOne, Name: "buyer", Table: "user", OwnerField: "user_id"},
+		{Name: "tags", Table: "tag", Through: "order_tag"},
+	},
+})
+
+var Users = orm.LoadModel[User]("用户", "user", orm.ModelConfig{})
+
+var Dynamic = orm.LoadModel[User]("动态", tableName, orm.ModelConfig{})
+`)
+
+	writeSchemaDocTable(t, root, map[string]any{
+		"table": "app_user",
+		"columns": []any{
+			schemaDocColumn("id", "bigint", true),
+			map[string]any{"name": "name", "type": "varchar(32)", "notNull": true, "comment": `昵称 "展示用"`},
+		},
+		"indexes": []any{map[string]any{"name": "uidx_app_user_name", "columns": []string{"name"}, "unique": true}},
+	})
+	writeSchemaDocTable(t, root, map[string]any{
+		"table": "app_order",
+		"columns": []any{
+			schemaDocColumn("id", "bigint", true),
+			schemaDocColumn("user_id", "bigint", true),
+			map[string]any{"name": "amount", "type": "decimal(10,2)", "notNull": true, "defaultValue": "0", "comment": "金额|元"},
+		},
+		"foreignKeys": []any{map[string]any{"name": "fk_app_order_user_id", "column": "user_id", "refTable": "app_user", "refColumn": "id", "onDelete": "CASCADE"}},
+	})
+	writeSchemaDocTable(t, root, map[string]any{"table": "app_tag", "columns": []any{schemaDocColumn("id", "bigint", true)}})
+	writeSchemaDocTable(t, root, map[string]any{"table": "app_order_tag", "columns": []any{schemaDocColumn("id", "bigint", true)}})
+	writeSchemaDocTable(t, root, map[string]any{"table": "legacy_log", "columns": []any{schemaDocColumn("id", "bigint", true)}})
+	return root
+}
+
+func TestParseSchemaDocModels(t *testing.T) {
+	root := setupSchemaDocProject(t)
+	models, err := parseSchemaDocModels(filepath.Join(root, "module", "shop", "model", "order.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 表名不是字面量的 LoadModel 调用无法静态解析，直接跳过。
+	if len(models) != 2 || models[0].Name != "订单" || models[0].Table != "order" || models[1].Table != "user" {
+		t.Fatalf("models = %+v", models)
+	}
+	relations := models[0].Relations
+	if len(relations) != 2 {
+		t.Fatalf("relations = %+v", relations)
+	}
+	if relations[0].Kind != "hasOne" || relations[0].Name != "buyer" || relations[0].OwnerField != "user_id" {
+		t.Errorf("relations[0] = %+v", relations[0])
+	}
+	if relations[1].Table != "tag" || relations[1].Through != "order_tag" || relationKind(relations[1]) != "manyToMany" {
+		t.Errorf("relations[1] = %+v", relations[1])
+	}
+}
+
+func TestRunSchemaDoc(t *testing.T) {
+	root := setupSchemaDocProject(t)
+	t.Chdir(root)
+	if err := RunSchemaDoc(root, "docs/schema"); err != nil {
+		t.Fatal(err)
+	}
+	read := func(name string) string {
+		t.Helper()
+		data, err := os.ReadFile(filepath.Join(root, "docs", "schema", name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(data)
+	}
+	assertContains := func(name, content string, fragments ...string) {
+		t.Helper()
+		for _, fragment := range fragments {
+			if !strings.Contains(content, fragment) {
+				t.Errorf("%s missing %q:\n%s", name, fragment, content)
+			}
+		}
+	}
+
+	index := read("README.md")
+	assertContains("README.md", index,
+		"| [module/shop](module-shop.md) | `app_order`、`app_user` |",
+		"| [未归属](other.md) | `app_order_tag`、`app_tag`、`legacy_log` |",
+	)
+	if strings.Index(index, "module-shop.md") > strings.Index(index, "other.md") {
+		t.Errorf("README.md: 未归属 should come last:\n%s", index)
+	}
+
+	// 源码中的表名按 app 前缀对应到记录的表，关联表与中间表同样补全前缀。
+	group := read("module-shop.md")
+	assertContains("module-shop.md", group,
+		"## app_order\n\n模型 `订单`",
+		"| `amount` | DECIMAL(10,2) | 否 | `0` | 金额\\|元 |",
+		"| `id` | BIGINT | 否 |  | 主键，自增 |",
+		"- `idx_app_order_user_id`（普通）：user_id",
+		"- `user_id` → `app_user.id` ON DELETE CASCADE",
+		"- `buyer`：hasOne `app_user`，关联字段 `user_id`",
+		"- `tags`：manyToMany `app_tag`，中间表 `app_order_tag`",
+		"- `uidx_app_user_name`（唯一）：name",
+	)
+
+	mermaid := read("schema.mmd")
+	assertContains("schema.mmd", mermaid,
+		"        DECIMAL(10-2) amount \"金额|元\"",
+		"        BIGINT user_id FK",
+		"        VARCHAR(32) name UK \"昵称 '展示用'\"",
+		"    app_user ||--o{ app_order : \"user_id\"",
+		"    app_order }o--o{ app_tag : \"tags via app_order_tag\"",
+	)
+	// 已有外键的两表之间不再重复绘制同向的关联。
+	if strings.Contains(mermaid, "buyer") {
+		t.Errorf("schema.mmd draws relation already covered by foreign key:\n%s", mermaid)
+	}
+
+	assertContains("schema.dot", read("schema.dot"),
+		"subgraph \"cluster_module-shop\"",
+		"subgraph \"cluster_other\"",
+		"\"app_order\":\"user_id\" -> \"app_user\":\"id\" [label=\"user_id\"];",
+		"\"app_order\" -> \"app_tag\" [style=dashed, label=\"tags via app_order_tag\"];",
+		"name : VARCHAR(32) — 昵称 &#34;展示用&#34;",
+	)
+}
+
+func TestRunSchemaDocWithoutRecords(t *testing.T) {
+	root := t.TempDir()
+	t.Chdir(root)
+	if err := RunSchemaDoc(root, "docs"); err == nil || !strings.Contains(err.Error(), "没有表结构记录") {
+		t.Fatalf("err = %v, want missing records error", err)
+	}
+}
+
+func TestSchemaDocEscaping(t *testing.T) {
+	cases := []struct {
+		name string
+		got  string
+		want string
+	}{
+		{"mermaid decimal", mermaidType("DECIMAL(10,2)"), "DECIMAL(10-2)"},
+		{"mermaid space", mermaidType("DOUBLE PRECISION"), "DOUBLE_PRECISION"},
+		{"mermaid empty", mermaidType(" "), "UNKNOWN"},
+		{"markdown", markdownCell("a|b\nc"), `a\|b c`},
+		{"dot", dotEscape(`say "hi" \n`), `say \"hi\" \\n`},
+	}
+	for _, tc := range cases {
+		if tc.got != tc.want {
+			t.Errorf("%s = %q, want %q", tc.name, tc.got, tc.want)
+		}
+	}
+}
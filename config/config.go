@@ -299,6 +299,7 @@ type DBConf struct {
 	ConnMaxLifetime   Duration          `json:"connMaxLifetime"`
 	ConnMaxIdleTime   Duration          `json:"connMaxIdleTime"`
 	HealthCheckPeriod Duration          `json:"healthCheckPeriod"`
+	Replicas          []DBConf          `json:"replicas"`
 }
 
 // Temporal 表示 Temporal 相关配置。
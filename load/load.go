@@ -3,6 +3,7 @@ package load
 import (
 	"context"
 	"fmt"
+	"sort"
 
 	"github.com/shemic/dever/server"
 )
@@ -33,6 +34,19 @@ func Service(name string, args ...any) any {
 	return invokeBinding(binding, args...)
 }
 
+// Models 返回已注册的模型名称（已归一化并排序），可逐个传给 Model 加载。
+func Models() []string {
+	current := registry.Load().(map[string]*binding)
+	names := make([]string, 0, len(current))
+	for name, binding := range current {
+		if binding.modelFn != nil {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
 // Model 加载注册的模型函数并返回模型实例。
 func Model(name string, args ...any) any {
 	binding := mustBinding(name)
@@ -26,6 +26,7 @@ type Config struct {
 	ConnMaxLifetime   time.Duration     `mapstructure:"connMaxLifetime"`
 	ConnMaxIdleTime   time.Duration     `mapstructure:"connMaxIdleTime"`
 	HealthCheckPeriod time.Duration     `mapstructure:"healthCheckPeriod"`
+	Replicas          []Config          `mapstructure:"replicas"`
 }
 
 // ConfigFromDBConf 将配置文件中的 DBConf 转换为 orm.Config。
@@ -34,6 +35,10 @@ func ConfigFromDBConf(dbCfg config.DBConf) Config {
 	for k, v := range dbCfg.Params {
 		params[k] = v
 	}
+	var replicas []Config
+	for _, replica := range dbCfg.Replicas {
+		replicas = append(replicas, ConfigFromDBConf(replica))
+	}
 	return Config{
 		Driver:            dbCfg.Driver,
 		Host:              dbCfg.Host,
@@ -49,6 +54,7 @@ func ConfigFromDBConf(dbCfg config.DBConf) Config {
 		ConnMaxLifetime:   dbCfg.ConnMaxLifetime.Duration(),
 		ConnMaxIdleTime:   dbCfg.ConnMaxIdleTime.Duration(),
 		HealthCheckPeriod: dbCfg.HealthCheckPeriod.Duration(),
+		Replicas:          replicas,
 	}
 }
 
@@ -0,0 +1,516 @@
+package orm
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// 数据导出/导入：按 data/table 记录的表结构把数据写成每表一个 <table>.jsonl（每行一个 JSON 对象），
+// 导入时按列类型还原时间、布尔、JSON、二进制值，可在不同驱动之间迁移数据（如 sqlite 到 postgres）。
+
+const dataFileExt = ".jsonl"
+
+// DataOptions 配置 DumpData / LoadData。
+type DataOptions struct {
+	Tables    []string // 为空时处理全部已记录的表（导入时为目录中的全部 .jsonl 文件）
+	BatchSize int      // 导入时每批插入的行数，默认 500
+	Append    bool     // 导入时保留目标表已有数据，默认先清空要导入的表
+}
+
+// DataTable 是一张表的导出/导入结果。
+type DataTable struct {
+	Table string
+	File  string
+	Rows  int64
+}
+
+// DumpData 将指定数据库中已记录表结构的表逐行导出到 dir，只导出 data/table 中记录的列，按主键排序；
+// 全部表在同一个只读的可重复读事务中读取，导出结果对应同一时刻的快照（sqlite 由读事务的锁保证）。
+func DumpData(ctx context.Context, dbName, dir string, options DataOptions) ([]DataTable, error) {
+	ctx = normalizeContext(ctx)
+	db, err := Get(dbName)
+	if err != nil {
+		return nil, err
+	}
+	driver := normalizeDriver(db.DriverName())
+	schemas, err := selectDataSchemas(options.Tables)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	// mattn/go-sqlite3 忽略 TxOptions，按 _txlock（默认 deferred）执行 BEGIN：首次读取时取得共享锁（WAL 模式为读快照），
+	// 之后直到事务结束读到的都是同一时刻的数据，因此同样是一致的快照。
+	tx, err := db.BeginTxx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+	results := make([]DataTable, 0, len(schemas))
+	for _, schema := range schemas {
+		result, err := dumpTable(ctx, tx, driver, dir, schema)
+		if err != nil {
+			return results, fmt.Errorf("orm: dump %s: %w", schema.Table, err)
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// LoadData 将 dir 中的 <table>.jsonl 导入指定数据库：按外键依赖顺序分批插入，整个导入在一个事务中完成，
+// postgres 在导入后推进自增序列。目标表需已按 data/table 建好（先执行 dever migrate）。
+// 引用本表的可空外键列（如 parent_id）先写入 NULL，整表插入后再按主键回填，子行主键小于父行时也能导入。
+func LoadData(ctx context.Context, dbName, dir string, options DataOptions) ([]DataTable, error) {
+	ctx = normalizeContext(ctx)
+	db, err := Get(dbName)
+	if err != nil {
+		return nil, err
+	}
+	driver := normalizeDriver(db.DriverName())
+	tables := options.Tables
+	if len(tables) == 0 {
+		if tables, err = listDataFiles(dir); err != nil {
+			return nil, err
+		}
+	}
+	schemas, err := selectDataSchemas(tables)
+	if err != nil {
+		return nil, err
+	}
+	schemas = sortSchemasByDependency(schemas)
+
+	tx, err := db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+	if !options.Append {
+		// 先删除引用方再删除被引用方。
+		for i := len(schemas) - 1; i >= 0; i-- {
+			if _, err := tx.ExecContext(ctx, "DELETE FROM "+quoteIdentifier(driver, schemas[i].Table)); err != nil {
+				return nil, fmt.Errorf("orm: clear %s: %w", schemas[i].Table, err)
+			}
+		}
+	}
+	results := make([]DataTable, 0, len(schemas))
+	for _, schema := range schemas {
+		result, err := loadTable(ctx, tx, driver, dir, schema, options.BatchSize)
+		if err != nil {
+			return nil, fmt.Errorf("orm: load %s: %w", schema.Table, err)
+		}
+		if err := syncPostgresSequences(ctx, tx, driver, schema); err != nil {
+			return nil, fmt.Errorf("orm: sync sequence of %s: %w", schema.Table, err)
+		}
+		results = append(results, result)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	for _, schema := range schemas {
+		invalidateTableCache(ctx, dbName, schema.Table)
+	}
+	return results, nil
+}
+
+// selectDataSchemas 返回 tables 对应的已记录表结构，tables 为空时返回全部。
+func selectDataSchemas(tables []string) ([]*tableSchema, error) {
+	schemas, err := listRecordedSchemas()
+	if err != nil {
+		return nil, err
+	}
+	if len(tables) == 0 {
+		if len(schemas) == 0 {
+			return nil, fmt.Errorf("orm: no table schema recorded in data/table")
+		}
+		return schemas, nil
+	}
+	byName := make(map[string]*tableSchema, len(schemas))
+	for _, schema := range schemas {
+		byName[strings.ToLower(schema.Table)] = schema
+	}
+	selected := make([]*tableSchema, 0, len(tables))
+	seen := map[string]struct{}{}
+	for _, table := range tables {
+		key := strings.ToLower(strings.TrimSpace(table))
+		if key == "" {
+			continue
+		}
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+		schema, ok := byName[key]
+		if !ok {
+			return nil, fmt.Errorf("orm: table %s schema not recorded in data/table", table)
+		}
+		selected = append(selected, schema)
+	}
+	return selected, nil
+}
+
+func listDataFiles(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var tables []string
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), dataFileExt) {
+			tables = append(tables, strings.TrimSuffix(entry.Name(), dataFileExt))
+		}
+	}
+	if len(tables) == 0 {
+		return nil, fmt.Errorf("orm: no %s file in %s", dataFileExt, dir)
+	}
+	sort.Strings(tables)
+	return tables, nil
+}
+
+func dumpTable(ctx context.Context, tx *sqlx.Tx, driver, dir string, schema *tableSchema) (DataTable, error) {
+	result := DataTable{Table: schema.Table, File: filepath.Join(dir, schema.Table+dataFileExt)}
+	if err := ensureIdentifier(schema.Table); err != nil {
+		return result, err
+	}
+	columns := make([]string, 0, len(schema.Columns))
+	var order []string
+	for _, column := range schema.Columns {
+		if err := ensureIdentifier(column.Name); err != nil {
+			return result, err
+		}
+		columns = append(columns, quoteIdentifier(driver, column.Name))
+		if column.Primary {
+			order = append(order, quoteIdentifier(driver, column.Name))
+		}
+	}
+	query := fmt.Sprintf("SELECT %s FROM %s", strings.Join(columns, ", "), quoteIdentifier(driver, schema.Table))
+	if len(order) > 0 {
+		query += " ORDER BY " + strings.Join(order, ", ")
+	}
+	rows, err := tx.QueryxContext(ctx, query)
+	if err != nil {
+		return result, err
+	}
+	defer rows.Close()
+
+	file, err := os.Create(result.File)
+	if err != nil {
+		return result, err
+	}
+	defer file.Close()
+	writer := bufio.NewWriter(file)
+	encoder := json.NewEncoder(writer)
+	encoder.SetEscapeHTML(false)
+	for rows.Next() {
+		values, err := rows.SliceScan()
+		if err != nil {
+			return result, err
+		}
+		row := make(map[string]any, len(values))
+		for i, column := range schema.Columns {
+			row[column.Name] = dumpValue(values[i], column)
+		}
+		if err := encoder.Encode(row); err != nil {
+			return result, err
+		}
+		result.Rows++
+	}
+	if err := rows.Err(); err != nil {
+		return result, err
+	}
+	if err := writer.Flush(); err != nil {
+		return result, err
+	}
+	return result, file.Close()
+}
+
+// dumpValue 按记录的列类型整理驱动返回的值：文本列的 []byte 转为字符串，二进制列保持 []byte（JSON 中为 base64），
+// 布尔、JSON、数值列统一为对应的 JSON 类型，时间列保持驱动返回的 time.Time 或字符串。
+func dumpValue(value any, column columnDef) any {
+	if value == nil {
+		return nil
+	}
+	if raw, ok := value.([]byte); ok {
+		if isBinarySQLType(column.Type) {
+			return raw
+		}
+		value = string(raw)
+	}
+	if isTimeSQLType(column.Type) {
+		return value
+	}
+	if isJSONColumnType(column.Type) {
+		if text, ok := value.(string); ok && strings.TrimSpace(text) == "" {
+			return nil
+		}
+	}
+	if isDecimalSQLType(column.Type) {
+		// 导出数据保持定点小数的原始精度。
+		return decimalString(value)
+	}
+	return normalizeValueByType(value, column.Type)
+}
+
+func loadTable(ctx context.Context, tx *sqlx.Tx, driver, dir string, schema *tableSchema, batchSize int) (DataTable, error) {
+	result := DataTable{Table: schema.Table, File: filepath.Join(dir, schema.Table+dataFileExt)}
+	file, err := os.Open(result.File)
+	if err != nil {
+		return result, err
+	}
+	defer file.Close()
+
+	byName := make(map[string]columnDef, len(schema.Columns))
+	for _, column := range schema.Columns {
+		byName[column.Name] = column
+	}
+	decoder := json.NewDecoder(bufio.NewReader(file))
+	decoder.UseNumber()
+
+	selfRefs, primary := selfReferenceColumns(schema)
+	var (
+		columns []string
+		batch   []map[string]any
+		pending []selfReference
+	)
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		if err := insertDataBatch(ctx, tx, driver, schema.Table, columns, batch); err != nil {
+			return err
+		}
+		result.Rows += int64(len(batch))
+		batch = batch[:0]
+		return nil
+	}
+	for line := 1; ; line++ {
+		var raw map[string]any
+		if err := decoder.Decode(&raw); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return result, fmt.Errorf("row %d: %w", line, err)
+		}
+		row := make(map[string]any, len(raw))
+		for key, value := range raw {
+			column, ok := byName[key]
+			if !ok {
+				return result, fmt.Errorf("row %d: column %s not in recorded schema", line, key)
+			}
+			converted, err := loadValue(value, column)
+			if err != nil {
+				return result, fmt.Errorf("row %d: column %s: %w", line, key, err)
+			}
+			row[key] = converted
+		}
+		if len(row) == 0 {
+			continue
+		}
+		for _, column := range selfRefs {
+			if value := row[column]; value != nil {
+				pending = append(pending, selfReference{column: column, key: row[primary], value: value})
+				row[column] = nil
+			}
+		}
+		// 列集合变化时先写入已累积的行，保证同一批次的列一致。
+		if keys := sortedColumnKeys(row); !slices.Equal(keys, columns) {
+			if err := flush(); err != nil {
+				return result, err
+			}
+			columns = keys
+		}
+		batch = append(batch, row)
+		if len(batch) >= batchChunkSize(batchSize, len(columns)) {
+			if err := flush(); err != nil {
+				return result, err
+			}
+		}
+	}
+	if err := flush(); err != nil {
+		return result, err
+	}
+	return result, updateSelfReferences(ctx, tx, driver, schema.Table, primary, pending)
+}
+
+type selfReference struct {
+	column string
+	key    any
+	value  any
+}
+
+// selfReferenceColumns 返回引用本表的可空外键列及单列主键，没有单列主键时不延后回填。
+func selfReferenceColumns(schema *tableSchema) ([]string, string) {
+	primary := ""
+	for _, column := range schema.Columns {
+		if column.Primary {
+			if primary != "" {
+				return nil, ""
+			}
+			primary = column.Name
+		}
+	}
+	if primary == "" {
+		return nil, ""
+	}
+	nullable := make(map[string]bool, len(schema.Columns))
+	for _, column := range schema.Columns {
+		nullable[column.Name] = !column.NotNull
+	}
+	var columns []string
+	for _, foreignKey := range schema.ForeignKeys {
+		if strings.EqualFold(foreignKey.RefTable, schema.Table) && nullable[foreignKey.Column] && foreignKey.Column != primary {
+			columns = append(columns, foreignKey.Column)
+		}
+	}
+	return columns, primary
+}
+
+func updateSelfReferences(ctx context.Context, tx *sqlx.Tx, driver, table, primary string, pending []selfReference) error {
+	for _, ref := range pending {
+		if ref.key == nil {
+			return fmt.Errorf("column %s: row without primary key %s", ref.column, primary)
+		}
+		query := fmt.Sprintf("UPDATE %s SET %s = ? WHERE %s = ?", quoteIdentifier(driver, table),
+			quoteIdentifier(driver, ref.column), quoteIdentifier(driver, primary))
+		if _, err := tx.ExecContext(ctx, tx.Rebind(query), ref.value, ref.key); err != nil {
+			return fmt.Errorf("column %s: %w", ref.column, err)
+		}
+	}
+	return nil
+}
+
+func insertDataBatch(ctx context.Context, tx *sqlx.Tx, driver, table string, columns []string, rows []map[string]any) error {
+	if err := ensureIdentifier(table); err != nil {
+		return err
+	}
+	quoted := make([]string, len(columns))
+	for i, column := range columns {
+		if err := ensureIdentifier(column); err != nil {
+			return err
+		}
+		quoted[i] = quoteIdentifier(driver, column)
+	}
+	placeholders := "(" + strings.TrimSuffix(strings.Repeat("?, ", len(columns)), ", ") + ")"
+	values := make([]string, len(rows))
+	args := make([]any, 0, len(rows)*len(columns))
+	for i, row := range rows {
+		values[i] = placeholders
+		for _, column := range columns {
+			args = append(args, row[column])
+		}
+	}
+	query := fmt.Sprintf("INSERT INTO %s (%s) VALUES %s", quoteIdentifier(driver, table), strings.Join(quoted, ", "), strings.Join(values, ", "))
+	_, err := tx.ExecContext(ctx, tx.Rebind(query), args...)
+	return err
+}
+
+// loadValue 把 JSON 解码后的值还原为列类型对应的驱动参数。
+func loadValue(value any, column columnDef) (any, error) {
+	if value == nil {
+		return nil, nil
+	}
+	sqlType := strings.ToUpper(column.Type)
+	switch {
+	case isJSONColumnType(sqlType):
+		if text, ok := value.(string); ok {
+			return text, nil
+		}
+		data, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+		return string(data), nil
+	case isBinarySQLType(sqlType):
+		text, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("expect base64 string for %s", column.Type)
+		}
+		return base64.StdEncoding.DecodeString(text)
+	case strings.Contains(sqlType, "BOOL"):
+		if number, ok := value.(json.Number); ok {
+			value = number.String()
+		}
+		if v, ok := toBool(value); ok {
+			return v, nil
+		}
+		return nil, fmt.Errorf("invalid boolean %v", value)
+	case isTimeSQLType(sqlType):
+		if text, ok := value.(string); ok {
+			return parseDataTime(text)
+		}
+	}
+	if number, ok := value.(json.Number); ok {
+		switch {
+		case strings.Contains(sqlType, "DECIMAL"), strings.Contains(sqlType, "NUMERIC"):
+			return number.String(), nil
+		case strings.Contains(sqlType, "INT"):
+			return number.Int64()
+		}
+		if v, err := number.Int64(); err == nil {
+			return v, nil
+		}
+		return number.Float64()
+	}
+	return value, nil
+}
+
+var dataTimeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02",
+}
+
+func parseDataTime(text string) (any, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil, nil
+	}
+	for _, layout := range dataTimeLayouts {
+		if parsed, err := time.Parse(layout, text); err == nil {
+			return parsed, nil
+		}
+	}
+	return nil, fmt.Errorf("invalid time %q", text)
+}
+
+func isBinarySQLType(sqlType string) bool {
+	upper := strings.ToUpper(sqlType)
+	return strings.Contains(upper, "BLOB") || strings.Contains(upper, "BYTEA") || strings.Contains(upper, "BINARY")
+}
+
+// syncPostgresSequences 导入显式主键后推进 postgres 自增序列，与种子数据的处理一致。
+func syncPostgresSequences(ctx context.Context, tx *sqlx.Tx, driver string, schema *tableSchema) error {
+	if driver != "postgres" {
+		return nil
+	}
+	for _, column := range schema.Columns {
+		if !column.AutoIncrement {
+			continue
+		}
+		var value int64
+		if err := tx.QueryRowxContext(ctx, buildPostgresSequenceSync(schema.Table, column.Name)).Scan(&value); err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,149 @@
+package orm
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type dataCategory struct {
+	ID       uint64  `dorm:"primaryKey;autoIncrement"`
+	ParentID *uint64 `dorm:"fk:data_category.id"`
+	Name     string  `dorm:"type:varchar(32)"`
+}
+
+type dataItem struct {
+	ID             uint64 `dorm:"primaryKey;autoIncrement"`
+	DataCategoryID uint64 `dorm:"fk:data_category.id"`
+	Active         bool
+	Meta           map[string]any
+	Raw            []byte
+	CreatedAt      time.Time
+}
+
+// setupDataDatabases 在临时目录记录表结构，并创建按记录建表、开启外键的 source/target 两个 sqlite 库。
+func setupDataDatabases(t *testing.T) (source, target *sqlx.DB) {
+	t.Helper()
+	dir := t.TempDir()
+	t.Chdir(dir)
+	if err := os.MkdirAll(filepath.Join("data", "table"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	var schemas []*tableSchema
+	for table, model := range map[string]any{"data_category": dataCategory{}, "data_item": dataItem{}} {
+		schema, err := buildSchema(table, model, schemaOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := json.Marshal(schema)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join("data", "table", table+".json"), data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		schemas = append(schemas, schema)
+	}
+	schemas = sortSchemasByDependency(schemas)
+	open := func(name string) *sqlx.DB {
+		db, err := Init(name, Config{Driver: "sqlite3", Path: filepath.Join(dir, name+".db"), Params: map[string]string{"_foreign_keys": "1"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { _ = Close(name) })
+		for _, schema := range schemas {
+			if _, err := syncTableSchema(context.Background(), newSchemaConn(db, false), "sqlite", schema.Table, schema); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return db
+	}
+	return open("data_source"), open("data_target")
+}
+
+func dataRows(t *testing.T, db *sqlx.DB, query string) []map[string]any {
+	t.Helper()
+	rows, err := db.Queryx(query)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	var result []map[string]any
+	for rows.Next() {
+		row := map[string]any{}
+		if err := rows.MapScan(row); err != nil {
+			t.Fatal(err)
+		}
+		normalizeMapValues(row)
+		result = append(result, row)
+	}
+	return result
+}
+
+func TestDumpAndLoadData(t *testing.T) {
+	source, target := setupDataDatabases(t)
+	ctx := context.Background()
+	// 子分类 1 引用主键更大的父分类 2，导入时需先写 NULL 再回填。
+	for _, stmt := range []string{
+		`INSERT INTO "data_category" ("id", "parent_id", "name") VALUES (2, NULL, 'root')`,
+		`INSERT INTO "data_category" ("id", "parent_id", "name") VALUES (1, 2, 'child')`,
+		`INSERT INTO "data_item" ("id", "data_category_id", "active", "meta", "raw", "created_at") VALUES (1, 1, 1, '{"k":[1,2]}', x'00ff', '2024-05-06 07:08:09')`,
+	} {
+		if _, err := source.Exec(stmt); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := target.Exec(`INSERT INTO "data_category" ("id", "parent_id", "name") VALUES (7, 8, 'dangling')`); err == nil {
+		t.Fatal("foreign keys not enforced on target")
+	}
+	out := filepath.Join(t.TempDir(), "dump")
+	dumped, err := DumpData(ctx, "data_source", out, DataOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dumped) != 2 || dumped[0].Rows+dumped[1].Rows != 3 {
+		t.Fatalf("dumped = %+v", dumped)
+	}
+
+	loaded, err := LoadData(ctx, "data_target", out, DataOptions{BatchSize: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 被引用的 data_category 先导入。
+	if len(loaded) != 2 || loaded[0].Table != "data_category" || loaded[1].Table != "data_item" {
+		t.Fatalf("load order = %+v", loaded)
+	}
+	const categories = `SELECT "id", "parent_id", "name" FROM "data_category" ORDER BY "id"`
+	const items = `SELECT "id", "data_category_id", "active", "meta", hex("raw") AS "raw", "created_at" FROM "data_item" ORDER BY "id"`
+	for _, query := range []string{categories, items} {
+		if got, want := dataRows(t, target, query), dataRows(t, source, query); !reflect.DeepEqual(got, want) {
+			t.Fatalf("%s:\ngot  %v\nwant %v", query, got, want)
+		}
+	}
+
+	// --append 保留目标表已有数据；默认先清空要导入的表。
+	if _, err := target.Exec(`DELETE FROM "data_item"`); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := target.Exec(`INSERT INTO "data_item" ("id", "data_category_id", "active", "meta", "raw", "created_at") VALUES (9, 2, 0, '{}', x'', '2024-01-01 00:00:00')`); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadData(ctx, "data_target", out, DataOptions{Tables: []string{"data_item"}, Append: true}); err != nil {
+		t.Fatal(err)
+	}
+	if rows := dataRows(t, target, `SELECT "id" FROM "data_item" ORDER BY "id"`); len(rows) != 2 {
+		t.Fatalf("append rows = %v, want existing row kept", rows)
+	}
+	if _, err := LoadData(ctx, "data_target", out, DataOptions{Tables: []string{"data_item"}}); err != nil {
+		t.Fatal(err)
+	}
+	if rows := dataRows(t, target, `SELECT "id" FROM "data_item" ORDER BY "id"`); len(rows) != 1 {
+		t.Fatalf("replace rows = %v, want table cleared first", rows)
+	}
+}
@@ -3,6 +3,11 @@ package orm
 import (
 	"database/sql"
 	"errors"
+	"fmt"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/mattn/go-sqlite3"
 )
 
 // ErrNotFound 表示未找到符合条件的记录。
@@ -11,11 +16,54 @@ var ErrNotFound = errors.New("orm: record not found")
 // ErrVersionConflict 表示乐观锁版本冲突。
 var ErrVersionConflict = errors.New("orm: version conflict")
 
+// ErrUniqueViolation 表示违反唯一约束（含主键冲突）。
+var ErrUniqueViolation = errors.New("orm: unique constraint violation")
+
+// ErrForeignKeyViolation 表示违反外键约束。
+var ErrForeignKeyViolation = errors.New("orm: foreign key constraint violation")
+
+// ErrSerialization 表示事务因并发冲突无法串行化（postgres 40001、sqlite busy/locked），可重试。
+var ErrSerialization = errors.New("orm: serialization failure")
+
+// ErrDeadlock 表示事务因死锁被数据库中止（postgres 40P01、mysql 1213），可重试。
+var ErrDeadlock = errors.New("orm: deadlock detected")
+
+// ErrTenantRequired 表示访问带租户列的表时上下文中没有租户（见 WithTenant / SkipTenant）。
+var ErrTenantRequired = errors.New("orm: tenant required")
+
+// ErrTenantMismatch 表示写入的租户与上下文中的租户不一致。
+var ErrTenantMismatch = errors.New("orm: tenant mismatch")
+
+// ErrUnknownRelation 表示 with 中的关联名未在 ModelConfig.Relations 中定义。
+var ErrUnknownRelation = errors.New("orm: unknown relation")
+
 // IsVersionConflict 判断错误是否为乐观锁冲突。
 func IsVersionConflict(err error) bool {
 	return errors.Is(err, ErrVersionConflict)
 }
 
+// IsUniqueViolation 判断错误是否为唯一约束冲突。
+func IsUniqueViolation(err error) bool {
+	return errors.Is(err, ErrUniqueViolation)
+}
+
+// IsForeignKeyViolation 判断错误是否为外键约束冲突。
+func IsForeignKeyViolation(err error) bool {
+	return errors.Is(err, ErrForeignKeyViolation)
+}
+
+// IsRetryable 判断错误是否为可重试的事务冲突（串行化失败或死锁）。
+func IsRetryable(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, ErrSerialization) || errors.Is(err, ErrDeadlock) {
+		return true
+	}
+	kind := classifyDriverError(err)
+	return kind == ErrSerialization || kind == ErrDeadlock
+}
+
 func normalizeError(err error) error {
 	if err == nil {
 		return nil
@@ -23,5 +71,52 @@ func normalizeError(err error) error {
 	if errors.Is(err, sql.ErrNoRows) {
 		return ErrNotFound
 	}
+	if kind := classifyDriverError(err); kind != nil && !errors.Is(err, kind) {
+		return fmt.Errorf("%w: %w", kind, err)
+	}
 	return err
 }
+
+// classifyDriverError 将 mysql/pgx/sqlite 的约束错误与事务冲突统一映射为 orm 哨兵错误。
+func classifyDriverError(err error) error {
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		switch mysqlErr.Number {
+		case 1062, 1586:
+			return ErrUniqueViolation
+		case 1216, 1217, 1451, 1452:
+			return ErrForeignKeyViolation
+		case 1213:
+			return ErrDeadlock
+		}
+		return nil
+	}
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case "23505":
+			return ErrUniqueViolation
+		case "23503":
+			return ErrForeignKeyViolation
+		case "40001":
+			return ErrSerialization
+		case "40P01":
+			return ErrDeadlock
+		}
+		return nil
+	}
+	var sqliteErr sqlite3.Error
+	if errors.As(err, &sqliteErr) {
+		switch sqliteErr.ExtendedCode {
+		case sqlite3.ErrConstraintUnique, sqlite3.ErrConstraintPrimaryKey:
+			return ErrUniqueViolation
+		case sqlite3.ErrConstraintForeignKey:
+			return ErrForeignKeyViolation
+		}
+		switch sqliteErr.Code {
+		case sqlite3.ErrBusy, sqlite3.ErrLocked:
+			return ErrSerialization
+		}
+	}
+	return nil
+}
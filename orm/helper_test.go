@@ -0,0 +1,67 @@
+package orm_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/shemic/dever/observe"
+)
+
+// queryRecorder 是记录 SQL 语句的 observe provider，供断言查询次数使用。
+type queryRecorder struct {
+	mu         sync.Mutex
+	statements []string
+}
+
+func (r *queryRecorder) OnStart(context.Context, observe.Snapshot) {}
+
+func (r *queryRecorder) OnFinish(_ context.Context, snapshot observe.Snapshot) {
+	if snapshot.Kind != observe.KindDB {
+		return
+	}
+	r.mu.Lock()
+	r.statements = append(r.statements, fmt.Sprint(snapshot.Attributes["db.statement"]))
+	r.mu.Unlock()
+}
+
+var (
+	recorderOnce sync.Once
+	recorder     = &queryRecorder{}
+)
+
+// recordQueries 返回 fn 执行期间发出的 SQL 语句。
+func recordQueries(t *testing.T, fn func()) []string {
+	t.Helper()
+	recorderOnce.Do(func() {
+		observe.Register("orm-test-recorder", func(observe.Config) (observe.Provider, error) {
+			return recorder, nil
+		})
+	})
+	if err := observe.Configure(observe.Config{Enabled: true, Provider: "orm-test-recorder"}); err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = observe.Configure(observe.Config{})
+	}()
+	recorder.mu.Lock()
+	recorder.statements = nil
+	recorder.mu.Unlock()
+	fn()
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+	return append([]string(nil), recorder.statements...)
+}
+
+// countQueries 统计包含 fragment 的语句数。
+func countQueries(statements []string, fragment string) int {
+	total := 0
+	for _, statement := range statements {
+		if strings.Contains(statement, fragment) {
+			total++
+		}
+	}
+	return total
+}
@@ -14,6 +14,12 @@ var (
 	dbInitErr  error
 )
 
+// DisableConfigInit 使首次加载模型时不再读取配置文件初始化连接，连接与迁移选项由调用方通过 Init 等函数设置；
+// 需在加载任何模型之前调用，供测试等场景使用。
+func DisableConfigInit() {
+	dbInitOnce.Do(func() {})
+}
+
 // ensureDatabaseInitialized 在首次模型加载时初始化数据库连接与迁移配置。
 func ensureDatabaseInitialized() error {
 	dbInitOnce.Do(func() {
@@ -18,6 +18,7 @@ var (
 	databases       = map[string]*sqlx.DB{}
 	defaultDatabase = "default"
 	dbPrefixes      = map[string]string{}
+	replicas        = map[string]*replicaSet{}
 )
 
 // Init 根据配置初始化命名数据库连接。
@@ -39,11 +40,42 @@ func Init(name string, cfg Config) (*sqlx.DB, error) {
 		return nil, fmt.Errorf("orm: build dsn for %q failed: %w", name, err)
 	}
 
-	db, err := sqlx.Open(driver, dsn)
+	db, err := openDatabase(driver, dsn, cfg)
 	if err != nil {
 		return nil, fmt.Errorf("orm: open %q failed: %w", name, err)
 	}
+	if err := pingDatabase(db, cfg); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("orm: ping %q failed: %w", name, err)
+	}
+	replicaPool, err := openReplicas(name, cfg)
+	if err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if existing, ok := databases[name]; ok {
+		_ = existing.Close()
+	}
+	if existing, ok := replicas[name]; ok {
+		_ = existing.close()
+		delete(replicas, name)
+	}
+	databases[name] = db
+	dbPrefixes[name] = prefix
+	if replicaPool != nil {
+		replicas[name] = replicaPool
+	}
+	return db, nil
+}
 
+func openDatabase(driver, dsn string, cfg Config) (*sqlx.DB, error) {
+	db, err := sqlx.Open(driver, dsn)
+	if err != nil {
+		return nil, err
+	}
 	if cfg.MaxOpenConns > 0 {
 		db.SetMaxOpenConns(cfg.MaxOpenConns)
 	}
@@ -56,22 +88,13 @@ func Init(name string, cfg Config) (*sqlx.DB, error) {
 	if cfg.ConnMaxIdleTime > 0 {
 		db.SetConnMaxIdleTime(cfg.ConnMaxIdleTime)
 	}
+	return db, nil
+}
 
+func pingDatabase(db *sqlx.DB, cfg Config) error {
 	ctx, cancel := context.WithTimeout(context.Background(), timeoutOrDefault(cfg.HealthCheckPeriod))
 	defer cancel()
-	if err := db.PingContext(ctx); err != nil {
-		_ = db.Close()
-		return nil, fmt.Errorf("orm: ping %q failed: %w", name, err)
-	}
-
-	mu.Lock()
-	defer mu.Unlock()
-	if existing, ok := databases[name]; ok {
-		_ = existing.Close()
-	}
-	databases[name] = db
-	dbPrefixes[name] = prefix
-	return db, nil
+	return db.PingContext(ctx)
 }
 
 // InitDefault 初始化默认数据库连接。
@@ -105,11 +128,17 @@ func Close(name string) error {
 		delete(databases, name)
 		delete(dbPrefixes, name)
 	}
+	set := replicas[name]
+	delete(replicas, name)
 	mu.Unlock()
-	if !ok {
-		return nil
+	var errs []error
+	if set != nil {
+		errs = append(errs, set.close())
+	}
+	if ok {
+		errs = append(errs, db.Close())
 	}
-	return db.Close()
+	return errors.Join(errs...)
 }
 
 // CloseAll 关闭所有数据库连接。
@@ -124,6 +153,12 @@ func CloseAll() error {
 		delete(databases, name)
 		delete(dbPrefixes, name)
 	}
+	for name, set := range replicas {
+		if err := set.close(); err != nil {
+			errs = append(errs, fmt.Errorf("%s replica: %w", name, err))
+		}
+		delete(replicas, name)
+	}
 	return errors.Join(errs...)
 }
 
@@ -193,6 +228,10 @@ func withOptionalTimeout(ctx context.Context, fallback time.Duration) (context.C
 }
 
 func ensureDatabaseExists(driver string, cfg Config) error {
+	// 只提供 DSN 时无法推导管理连接，要求数据库已存在。
+	if driver != "sqlite3" && strings.TrimSpace(cfg.DSN) != "" && strings.TrimSpace(cfg.DBName) == "" {
+		return nil
+	}
 	switch driver {
 	case "mysql":
 		return ensureMySQLDatabase(cfg)
@@ -0,0 +1,610 @@
+package orm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// 版本化迁移：data/migrations/<database>/<version>_<name>.up.sql 与 .down.sql 文件，
+// 以及 RegisterMigration 注册的 Go 迁移，按版本号顺序执行，执行记录写入 dever_migrations 表。
+// ApplyRecordedSchemas（dever migrate <database>）与自动迁移直接按 data/table 同步表结构，不读写执行记录；
+// 同一数据库应只选用其中一种方式管理结构变更，两者混用时以数据库实际结构为准，migrate make 基于实际结构生成差异。
+
+const (
+	migrationHistoryTable = "dever_migrations"
+	migrationUpSuffix     = ".up.sql"
+	migrationDownSuffix   = ".down.sql"
+	// migrationIrreversibleMark 出现在 down 文件中时表示该迁移无法回滚。
+	migrationIrreversibleMark = "-- dever:irreversible"
+)
+
+// ErrIrreversibleMigration 表示迁移没有可执行的回滚步骤。
+var ErrIrreversibleMigration = errors.New("orm: migration is irreversible")
+
+// Migration 描述一个版本化迁移。文件迁移使用 UpSQL/DownSQL，Go 迁移使用 Up/Down，
+// 回调在迁移事务中执行，ctx 已携带事务，可直接使用模型方法。
+type Migration struct {
+	Version  string
+	Name     string
+	Database string
+	UpSQL    []string
+	DownSQL  []string
+	Up       func(ctx context.Context, tx *sqlx.Tx) error
+	Down     func(ctx context.Context, tx *sqlx.Tx) error
+
+	source       string
+	irreversible bool
+}
+
+// MigrationStatus 描述迁移的执行状态；Missing 表示已执行但迁移文件或注册已不存在。
+type MigrationStatus struct {
+	Version   string
+	Name      string
+	Source    string
+	Applied   bool
+	Batch     int
+	AppliedAt time.Time
+	Missing   bool
+}
+
+type migrationRecord struct {
+	Version   string    `db:"version"`
+	Name      string    `db:"name"`
+	Batch     int       `db:"batch"`
+	AppliedAt time.Time `db:"applied_at"`
+}
+
+var (
+	migrationMu          sync.RWMutex
+	registeredMigrations = map[string]map[string]Migration{}
+)
+
+// RegisterMigration 注册 Go 迁移，Database 为空时归属默认数据库。
+func RegisterMigration(migration Migration) error {
+	migration.Version = strings.TrimSpace(migration.Version)
+	migration.Name = strings.TrimSpace(migration.Name)
+	if migration.Version == "" {
+		return fmt.Errorf("orm: migration version required")
+	}
+	if strings.ContainsAny(migration.Version, "_/\\ ") {
+		return fmt.Errorf("orm: invalid migration version %q", migration.Version)
+	}
+	if migration.Up == nil {
+		return fmt.Errorf("orm: migration %s requires Up", migration.Version)
+	}
+	database := migrationDatabase(migration.Database)
+	migration.Database = database
+	migration.source = "go"
+
+	migrationMu.Lock()
+	defer migrationMu.Unlock()
+	if registeredMigrations[database] == nil {
+		registeredMigrations[database] = map[string]Migration{}
+	}
+	if _, ok := registeredMigrations[database][migration.Version]; ok {
+		return fmt.Errorf("orm: migration %s already registered for %s", migration.Version, database)
+	}
+	registeredMigrations[database][migration.Version] = migration
+	return nil
+}
+
+// GenerateMigration 对比 data/table 中记录的表结构与数据库现状，生成 up/down 迁移文件但不执行。
+// 没有结构差异时返回空 Version。
+func GenerateMigration(ctx context.Context, dbName, name string) (Migration, error) {
+	ctx = normalizeContext(ctx)
+	dbName = migrationDatabase(dbName)
+	db, err := Get(dbName)
+	if err != nil {
+		return Migration{}, err
+	}
+	changes, err := planRecordedSchemas(ctx, db)
+	if err != nil {
+		return Migration{}, err
+	}
+	if len(changes) == 0 {
+		return Migration{}, nil
+	}
+
+	migration := Migration{Name: migrationFileName(name), Database: dbName, source: "file"}
+	for _, change := range changes {
+		migration.UpSQL = append(migration.UpSQL, change.Up...)
+	}
+	var down strings.Builder
+	for i := len(changes) - 1; i >= 0; i-- {
+		change := changes[i]
+		if change.Irreversible {
+			migration.irreversible = true
+			fmt.Fprintf(&down, "%s %s\n", migrationIrreversibleMark, strings.Join(change.Up, "; "))
+			continue
+		}
+		for _, stmt := range change.Down {
+			migration.DownSQL = append(migration.DownSQL, stmt)
+			down.WriteString(stmt + ";\n")
+		}
+	}
+
+	dir := migrationDir(dbName)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return Migration{}, err
+	}
+	existing, err := loadFileMigrations(dbName, normalizeDriver(db.DriverName()))
+	if err != nil {
+		return Migration{}, err
+	}
+	migration.Version = nextMigrationVersion(existing, time.Now())
+
+	var up strings.Builder
+	for _, stmt := range migration.UpSQL {
+		up.WriteString(stmt + ";\n")
+	}
+	header := fmt.Sprintf("-- Generated by dever migrate make\n-- Database: %s, Driver: %s\n\n", dbName, normalizeDriver(db.DriverName()))
+	base := filepath.Join(dir, migration.Version+"_"+migration.Name)
+	if err := os.WriteFile(base+migrationUpSuffix, []byte(header+up.String()), 0o644); err != nil {
+		return Migration{}, err
+	}
+	if err := os.WriteFile(base+migrationDownSuffix, []byte(header+down.String()), 0o644); err != nil {
+		return Migration{}, err
+	}
+	return migration, nil
+}
+
+// planRecordedSchemas 以 plan 模式对比 data/table 中的表结构，返回待执行的变更。
+func planRecordedSchemas(ctx context.Context, db *sqlx.DB) ([]SchemaChange, error) {
+	driver := normalizeDriver(db.DriverName())
+	schemas, err := listRecordedSchemas()
+	if err != nil {
+		return nil, err
+	}
+	conn := newSchemaConn(db, true)
+	for _, schema := range sortSchemasByDependency(schemas) {
+		if schema == nil {
+			continue
+		}
+		if _, err := syncTableSchema(ctx, conn, driver, schema.Table, schema); err != nil {
+			return nil, err
+		}
+	}
+	return conn.changes, nil
+}
+
+// MigrationStatuses 返回指定数据库全部迁移的执行状态，按版本号排序。
+func MigrationStatuses(ctx context.Context, dbName string) ([]MigrationStatus, error) {
+	ctx = normalizeContext(ctx)
+	dbName = migrationDatabase(dbName)
+	migrations, history, err := loadMigrationState(ctx, dbName)
+	if err != nil {
+		return nil, err
+	}
+	statuses := make([]MigrationStatus, 0, len(migrations))
+	known := make(map[string]struct{}, len(migrations))
+	for _, migration := range migrations {
+		known[migration.Version] = struct{}{}
+		status := MigrationStatus{Version: migration.Version, Name: migration.Name, Source: migration.source}
+		if record, ok := history[migration.Version]; ok {
+			status.Applied = true
+			status.Batch = record.Batch
+			status.AppliedAt = record.AppliedAt
+		}
+		statuses = append(statuses, status)
+	}
+	for version, record := range history {
+		if _, ok := known[version]; ok {
+			continue
+		}
+		statuses = append(statuses, MigrationStatus{
+			Version:   version,
+			Name:      record.Name,
+			Applied:   true,
+			Batch:     record.Batch,
+			AppliedAt: record.AppliedAt,
+			Missing:   true,
+		})
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Version < statuses[j].Version
+	})
+	return statuses, nil
+}
+
+// MigrateUp 按版本顺序执行未执行的迁移，steps<=0 时执行全部；本次执行的迁移记为同一批次。
+func MigrateUp(ctx context.Context, dbName string, steps int) ([]MigrationStatus, error) {
+	return migrateUp(ctx, dbName, steps, nil)
+}
+
+// MigrateRedo 回滚最近 steps 个迁移（<=0 时为 1），再按版本顺序重新执行这些迁移。
+func MigrateRedo(ctx context.Context, dbName string, steps int) (reverted, applied []MigrationStatus, err error) {
+	reverted, err = MigrateDown(ctx, dbName, steps)
+	if err != nil || len(reverted) == 0 {
+		return reverted, nil, err
+	}
+	only := make(map[string]struct{}, len(reverted))
+	for _, item := range reverted {
+		only[item.Version] = struct{}{}
+	}
+	applied, err = migrateUp(ctx, dbName, 0, only)
+	return reverted, applied, err
+}
+
+// migrateUp 执行未执行的迁移，only 不为空时只执行其中的版本。
+func migrateUp(ctx context.Context, dbName string, steps int, only map[string]struct{}) ([]MigrationStatus, error) {
+	ctx = normalizeContext(ctx)
+	dbName = migrationDatabase(dbName)
+	migrations, history, err := loadMigrationState(ctx, dbName)
+	if err != nil {
+		return nil, err
+	}
+	batch := 0
+	for _, record := range history {
+		batch = max(batch, record.Batch)
+	}
+	batch++
+
+	var applied []MigrationStatus
+	for _, migration := range migrations {
+		if _, ok := history[migration.Version]; ok {
+			continue
+		}
+		if _, ok := only[migration.Version]; only != nil && !ok {
+			continue
+		}
+		if steps > 0 && len(applied) >= steps {
+			break
+		}
+		if err := runMigration(ctx, dbName, migration, true, batch); err != nil {
+			return applied, fmt.Errorf("orm: migration %s_%s up failed: %w", migration.Version, migration.Name, err)
+		}
+		applied = append(applied, MigrationStatus{Version: migration.Version, Name: migration.Name, Source: migration.source, Applied: true, Batch: batch})
+	}
+	return applied, nil
+}
+
+// MigrateDown 按执行顺序倒序回滚最近 steps 个迁移（<=0 时为 1）。
+func MigrateDown(ctx context.Context, dbName string, steps int) ([]MigrationStatus, error) {
+	ctx = normalizeContext(ctx)
+	dbName = migrationDatabase(dbName)
+	if steps <= 0 {
+		steps = 1
+	}
+	migrations, history, err := loadMigrationState(ctx, dbName)
+	if err != nil {
+		return nil, err
+	}
+	byVersion := make(map[string]Migration, len(migrations))
+	for _, migration := range migrations {
+		byVersion[migration.Version] = migration
+	}
+	records := make([]migrationRecord, 0, len(history))
+	for _, record := range history {
+		records = append(records, record)
+	}
+	sort.Slice(records, func(i, j int) bool {
+		if records[i].Batch != records[j].Batch {
+			return records[i].Batch > records[j].Batch
+		}
+		return records[i].Version > records[j].Version
+	})
+
+	var reverted []MigrationStatus
+	for _, record := range records {
+		if len(reverted) >= steps {
+			break
+		}
+		migration, ok := byVersion[record.Version]
+		if !ok {
+			return reverted, fmt.Errorf("orm: migration %s_%s not found, cannot roll back", record.Version, record.Name)
+		}
+		if err := runMigration(ctx, dbName, migration, false, record.Batch); err != nil {
+			return reverted, fmt.Errorf("orm: migration %s_%s down failed: %w", migration.Version, migration.Name, err)
+		}
+		reverted = append(reverted, MigrationStatus{Version: migration.Version, Name: migration.Name, Source: migration.source, Batch: record.Batch})
+	}
+	return reverted, nil
+}
+
+// runMigration 在一个事务中执行迁移并写入或删除执行记录。postgres 与 sqlite 的 DDL 可随事务回滚；
+// mysql 每条 DDL 都会隐式提交，失败时此前的语句已生效且不会写入执行记录，错误中的语句序号用于确认进度，
+// 修复后需手工撤销已生效的语句再重新执行，因此 mysql 迁移建议每个文件只包含一条 DDL。
+func runMigration(ctx context.Context, dbName string, migration Migration, up bool, batch int) error {
+	if !up && (migration.irreversible || (migration.Down == nil && len(migration.DownSQL) == 0 && migration.source == "go")) {
+		return ErrIrreversibleMigration
+	}
+	return Transaction(ctx, func(txCtx context.Context) error {
+		tx := txFromContext(txCtx)
+		statements, fn := migration.UpSQL, migration.Up
+		if !up {
+			statements, fn = migration.DownSQL, migration.Down
+		}
+		for i, stmt := range statements {
+			if _, err := tx.ExecContext(txCtx, stmt); err != nil {
+				return fmt.Errorf("statement %d/%d: %w", i+1, len(statements), err)
+			}
+		}
+		if fn != nil {
+			if err := fn(txCtx, tx); err != nil {
+				return err
+			}
+		}
+		table := quoteIdentifier(normalizeDriver(tx.DriverName()), migrationHistoryTable)
+		if up {
+			query := tx.Rebind(fmt.Sprintf("INSERT INTO %s (version, name, batch, applied_at) VALUES (?, ?, ?, ?)", table))
+			_, err := tx.ExecContext(txCtx, query, migration.Version, migration.Name, batch, time.Now())
+			return err
+		}
+		query := tx.Rebind(fmt.Sprintf("DELETE FROM %s WHERE version = ?", table))
+		_, err := tx.ExecContext(txCtx, query, migration.Version)
+		return err
+	}, dbName)
+}
+
+func loadMigrationState(ctx context.Context, dbName string) ([]Migration, map[string]migrationRecord, error) {
+	db, err := Get(dbName)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := ensureMigrationTable(ctx, db); err != nil {
+		return nil, nil, err
+	}
+	migrations, err := loadMigrations(dbName, normalizeDriver(db.DriverName()))
+	if err != nil {
+		return nil, nil, err
+	}
+	var records []migrationRecord
+	query := fmt.Sprintf("SELECT version, name, batch, applied_at FROM %s", quoteIdentifier(normalizeDriver(db.DriverName()), migrationHistoryTable))
+	if err := db.SelectContext(ctx, &records, query); err != nil {
+		return nil, nil, err
+	}
+	history := make(map[string]migrationRecord, len(records))
+	for _, record := range records {
+		history[record.Version] = record
+	}
+	return migrations, history, nil
+}
+
+func ensureMigrationTable(ctx context.Context, db *sqlx.DB) error {
+	driver := normalizeDriver(db.DriverName())
+	timeType := "DATETIME"
+	if driver == "postgres" {
+		timeType = "TIMESTAMP"
+	}
+	stmt := fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (version VARCHAR(64) NOT NULL, name VARCHAR(255) NOT NULL, batch INT NOT NULL, applied_at %s NOT NULL, PRIMARY KEY (version))",
+		quoteIdentifier(driver, migrationHistoryTable), timeType)
+	_, err := db.ExecContext(ctx, stmt)
+	return err
+}
+
+// loadMigrations 合并迁移文件与 Go 迁移，同一版本同时存在时返回错误。
+func loadMigrations(dbName, driver string) ([]Migration, error) {
+	migrations, err := loadFileMigrations(dbName, driver)
+	if err != nil {
+		return nil, err
+	}
+	byVersion := make(map[string]struct{}, len(migrations))
+	for _, migration := range migrations {
+		byVersion[migration.Version] = struct{}{}
+	}
+	migrationMu.RLock()
+	for version, migration := range registeredMigrations[dbName] {
+		if _, ok := byVersion[version]; ok {
+			migrationMu.RUnlock()
+			return nil, fmt.Errorf("orm: migration version %s defined by both file and RegisterMigration", version)
+		}
+		migrations = append(migrations, migration)
+	}
+	migrationMu.RUnlock()
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	return migrations, nil
+}
+
+func loadFileMigrations(dbName, driver string) ([]Migration, error) {
+	entries, err := os.ReadDir(migrationDir(dbName))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	byVersion := map[string]*Migration{}
+	for _, entry := range entries {
+		fileName := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(fileName, migrationUpSuffix) {
+			continue
+		}
+		version, name, ok := strings.Cut(strings.TrimSuffix(fileName, migrationUpSuffix), "_")
+		if !ok || version == "" {
+			continue
+		}
+		if _, exists := byVersion[version]; exists {
+			return nil, fmt.Errorf("orm: duplicate migration version %s in %s", version, migrationDir(dbName))
+		}
+		base := filepath.Join(migrationDir(dbName), strings.TrimSuffix(fileName, migrationUpSuffix))
+		upContent, err := os.ReadFile(base + migrationUpSuffix)
+		if err != nil {
+			return nil, err
+		}
+		migration := &Migration{Version: version, Name: name, Database: dbName, UpSQL: splitSQLStatements(string(upContent), driver), source: "file"}
+		downContent, err := os.ReadFile(base + migrationDownSuffix)
+		switch {
+		case err == nil:
+			migration.DownSQL = splitSQLStatements(string(downContent), driver)
+			migration.irreversible = strings.Contains(string(downContent), migrationIrreversibleMark)
+		case os.IsNotExist(err):
+			migration.irreversible = true
+		default:
+			return nil, err
+		}
+		byVersion[version] = migration
+	}
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		migrations = append(migrations, *migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	return migrations, nil
+}
+
+// splitSQLStatements 按分号拆分 SQL 文件：忽略引号、postgres $$ / $tag$ 函数体与注释内的分号，
+// 去掉 -- 行内注释与 /* */ 块注释（保留 mysql 的 /*! */ 可执行注释）。
+// 引号内的反斜杠按驱动处理：mysql 字符串中 \' 为转义；postgres 只有 E'...' 字符串转义，标准字符串与 sqlite 中反斜杠是普通字符。
+func splitSQLStatements(content, driver string) []string {
+	var (
+		statements []string
+		current    strings.Builder
+	)
+	flush := func() {
+		if stmt := strings.TrimSpace(current.String()); stmt != "" {
+			statements = append(statements, stmt)
+		}
+		current.Reset()
+	}
+	for i := 0; i < len(content); {
+		c := content[i]
+		switch {
+		case c == '\'' || c == '"' || c == '`':
+			escapes := c != '`' && backslashEscapes(content, i, driver)
+			end := i + 1
+			for end < len(content) && content[end] != c {
+				if escapes && content[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			end = min(end+1, len(content))
+			current.WriteString(content[i:end])
+			i = end
+		case c == '-' && strings.HasPrefix(content[i:], "--"):
+			end := strings.IndexByte(content[i:], '\n')
+			if end < 0 {
+				i = len(content)
+			} else {
+				i += end
+			}
+		case c == '/' && strings.HasPrefix(content[i:], "/*"):
+			end := strings.Index(content[i+2:], "*/")
+			if end < 0 {
+				end = len(content)
+			} else {
+				end += i + 4
+			}
+			if strings.HasPrefix(content[i:], "/*!") {
+				current.WriteString(content[i:end])
+			} else {
+				current.WriteByte(' ')
+			}
+			i = end
+		case c == '$':
+			tag := dollarQuoteTag(content[i:])
+			if tag == "" {
+				current.WriteByte(c)
+				i++
+				break
+			}
+			end := strings.Index(content[i+len(tag):], tag)
+			if end < 0 {
+				end = len(content)
+			} else {
+				end += i + 2*len(tag)
+			}
+			current.WriteString(content[i:end])
+			i = end
+		case c == ';':
+			flush()
+			i++
+		default:
+			current.WriteByte(c)
+			i++
+		}
+	}
+	flush()
+	return statements
+}
+
+// backslashEscapes 判断 content[quote] 开始的字符串中反斜杠是否为转义符。
+func backslashEscapes(content string, quote int, driver string) bool {
+	switch driver {
+	case "mysql":
+		return true
+	case "postgres":
+		if content[quote] != '\'' || quote == 0 || (content[quote-1] != 'E' && content[quote-1] != 'e') {
+			return false
+		}
+		if quote == 1 {
+			return true
+		}
+		prev := content[quote-2]
+		return !(prev == '_' || prev >= 'a' && prev <= 'z' || prev >= 'A' && prev <= 'Z' || prev >= '0' && prev <= '9')
+	default:
+		return false
+	}
+}
+
+// dollarQuoteTag 返回 s 开头的 postgres 美元引号标记（$$ 或 $tag$），不是标记时返回空串；$1 等占位符不匹配。
+func dollarQuoteTag(s string) string {
+	for i := 1; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '$':
+			return s[:i+1]
+		case c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= 0x80:
+		case c >= '0' && c <= '9' && i > 1:
+		default:
+			return ""
+		}
+	}
+	return ""
+}
+
+func nextMigrationVersion(existing []Migration, now time.Time) string {
+	used := make(map[string]struct{}, len(existing))
+	for _, migration := range existing {
+		used[migration.Version] = struct{}{}
+	}
+	for {
+		version := now.Format("20060102150405")
+		if _, ok := used[version]; !ok {
+			return version
+		}
+		now = now.Add(time.Second)
+	}
+}
+
+func migrationFileName(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	var builder strings.Builder
+	for _, r := range name {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			builder.WriteRune(r)
+		default:
+			builder.WriteRune('_')
+		}
+	}
+	result := strings.Trim(builder.String(), "_")
+	if result == "" {
+		return "schema"
+	}
+	return result
+}
+
+func migrationDir(dbName string) string {
+	return filepath.Join("data", "migrations", dbName)
+}
+
+func migrationDatabase(name string) string {
+	if strings.TrimSpace(name) == "" {
+		return currentDefaultDatabase()
+	}
+	return strings.TrimSpace(name)
+}
@@ -0,0 +1,211 @@
+package orm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+)
+
+func TestSplitSQLStatements(t *testing.T) {
+	content := `-- 建表
+CREATE TABLE "user" (id INT, name VARCHAR(32) DEFAULT 'a;b'); -- 行尾注释; 含分号
+/* 块注释; */ INSERT INTO "user" VALUES (1, 'it''s;');
+CREATE FUNCTION touch() RETURNS trigger AS $$
+BEGIN
+  NEW.updated_at = now();
+  RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+CREATE FUNCTION f() RETURNS text AS $body$ SELECT 'x;y' $body$ LANGUAGE sql;
+SELECT $1 FROM t;
+/*!40101 SET NAMES utf8mb4 */;
+`
+	got := splitSQLStatements(content, "postgres")
+	want := []string{
+		`CREATE TABLE "user" (id INT, name VARCHAR(32) DEFAULT 'a;b')`,
+		`INSERT INTO "user" VALUES (1, 'it''s;')`,
+		"CREATE FUNCTION touch() RETURNS trigger AS $$\nBEGIN\n  NEW.updated_at = now();\n  RETURN NEW;\nEND;\n$$ LANGUAGE plpgsql",
+		`CREATE FUNCTION f() RETURNS text AS $body$ SELECT 'x;y' $body$ LANGUAGE sql`,
+		`SELECT $1 FROM t`,
+		`/*!40101 SET NAMES utf8mb4 */`,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("splitSQLStatements() =\n%q\nwant\n%q", got, want)
+	}
+}
+
+func TestSplitSQLStatementsBackslashByDriver(t *testing.T) {
+	cases := []struct {
+		driver  string
+		content string
+		want    []string
+	}{
+		{"mysql", `INSERT INTO t VALUES ('it\'s; ok', "say \"hi;\""); SELECT 1;`,
+			[]string{`INSERT INTO t VALUES ('it\'s; ok', "say \"hi;\"")`, `SELECT 1`}},
+		{"sqlite", `INSERT INTO t VALUES ('C:\'); SELECT 1;`,
+			[]string{`INSERT INTO t VALUES ('C:\')`, `SELECT 1`}},
+		{"postgres", `INSERT INTO t VALUES ('C:\'); SELECT 1;`,
+			[]string{`INSERT INTO t VALUES ('C:\')`, `SELECT 1`}},
+		{"postgres", `SELECT E'it\'s;', type'x\'; SELECT 1;`,
+			[]string{`SELECT E'it\'s;', type'x\'`, `SELECT 1`}},
+	}
+	for _, tc := range cases {
+		if got := splitSQLStatements(tc.content, tc.driver); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("%s: splitSQLStatements(%q) = %q, want %q", tc.driver, tc.content, got, tc.want)
+		}
+	}
+}
+
+// setupMigrationDatabase 在临时目录中初始化 sqlite 库，并切换工作目录使 data/migrations 指向该目录。
+func setupMigrationDatabase(t *testing.T, name string) *sqlx.DB {
+	t.Helper()
+	dir := t.TempDir()
+	t.Chdir(dir)
+	db, err := Init(name, Config{Driver: "sqlite3", Path: filepath.Join(dir, name+".db")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_ = Close(name)
+		migrationMu.Lock()
+		delete(registeredMigrations, name)
+		migrationMu.Unlock()
+	})
+	if err := os.MkdirAll(migrationDir(name), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func writeMigrationFile(t *testing.T, dbName, file, content string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(migrationDir(dbName), file), []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func migrationSummary(t *testing.T, dbName string) string {
+	t.Helper()
+	statuses, err := MigrationStatuses(context.Background(), dbName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parts := make([]string, 0, len(statuses))
+	for _, status := range statuses {
+		state := "pending"
+		if status.Applied {
+			state = fmt.Sprintf("batch%d", status.Batch)
+		}
+		if status.Missing {
+			state += "-missing"
+		}
+		parts = append(parts, status.Version+":"+status.Source+":"+state)
+	}
+	return strings.Join(parts, " ")
+}
+
+func TestMigrateUpDownRedoAndStatus(t *testing.T) {
+	const dbName = "migration_flow"
+	db := setupMigrationDatabase(t, dbName)
+	ctx := context.Background()
+	writeMigrationFile(t, dbName, "001_create_note.up.sql", `CREATE TABLE note (id INTEGER PRIMARY KEY, body TEXT);
+INSERT INTO note (body) VALUES ('first; row');`)
+	writeMigrationFile(t, dbName, "001_create_note.down.sql", `DROP TABLE note;`)
+	writeMigrationFile(t, dbName, "002_add_tag.up.sql", `ALTER TABLE note ADD COLUMN tag TEXT;`)
+	err := RegisterMigration(Migration{
+		Version:  "003",
+		Name:     "seed_note",
+		Database: dbName,
+		Up: func(ctx context.Context, tx *sqlx.Tx) error {
+			_, err := tx.ExecContext(ctx, `INSERT INTO note (body) VALUES ('seed')`)
+			return err
+		},
+		Down: func(ctx context.Context, tx *sqlx.Tx) error {
+			_, err := tx.ExecContext(ctx, `DELETE FROM note WHERE body = 'seed'`)
+			return err
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	countNotes := func() int {
+		var total int
+		if err := db.Get(&total, `SELECT COUNT(*) FROM note`); err != nil {
+			t.Fatal(err)
+		}
+		return total
+	}
+
+	if got := migrationSummary(t, dbName); got != "001:file:pending 002:file:pending 003:go:pending" {
+		t.Fatalf("initial status = %s", got)
+	}
+	if applied, err := MigrateUp(ctx, dbName, 1); err != nil || len(applied) != 1 || applied[0].Version != "001" {
+		t.Fatalf("up 1 = %+v, %v", applied, err)
+	}
+	if applied, err := MigrateUp(ctx, dbName, 0); err != nil || len(applied) != 2 {
+		t.Fatalf("up all = %+v, %v", applied, err)
+	}
+	if got := migrationSummary(t, dbName); got != "001:file:batch1 002:file:batch2 003:go:batch2" {
+		t.Fatalf("status after up = %s", got)
+	}
+	if got := countNotes(); got != 2 {
+		t.Fatalf("notes = %d, want 2", got)
+	}
+
+	reverted, applied, err := MigrateRedo(ctx, dbName, 1)
+	if err != nil || len(reverted) != 1 || reverted[0].Version != "003" || len(applied) != 1 {
+		t.Fatalf("redo = %+v / %+v, %v", reverted, applied, err)
+	}
+	if got := migrationSummary(t, dbName); got != "001:file:batch1 002:file:batch2 003:go:batch3" {
+		t.Fatalf("status after redo = %s", got)
+	}
+	if got := countNotes(); got != 2 {
+		t.Fatalf("notes after redo = %d, want 2", got)
+	}
+
+	if reverted, err := MigrateDown(ctx, dbName, 1); err != nil || len(reverted) != 1 || reverted[0].Version != "003" {
+		t.Fatalf("down = %+v, %v", reverted, err)
+	}
+	// 002 没有 down 文件，视为不可回滚。
+	if _, err := MigrateDown(ctx, dbName, 1); !errors.Is(err, ErrIrreversibleMigration) {
+		t.Fatalf("down irreversible err = %v", err)
+	}
+	if got := migrationSummary(t, dbName); got != "001:file:batch1 002:file:batch2 003:go:pending" {
+		t.Fatalf("status after down = %s", got)
+	}
+
+	// 已执行的迁移文件被删除后仍在状态中显示为 Missing。
+	if err := os.Remove(filepath.Join(migrationDir(dbName), "001_create_note.up.sql")); err != nil {
+		t.Fatal(err)
+	}
+	if got := migrationSummary(t, dbName); !strings.HasPrefix(got, "001::batch1-missing") {
+		t.Fatalf("status after removing file = %s", got)
+	}
+}
+
+func TestMigrationFailureRollsBackAndReportsStatement(t *testing.T) {
+	const dbName = "migration_failure"
+	db := setupMigrationDatabase(t, dbName)
+	writeMigrationFile(t, dbName, "001_broken.up.sql", `CREATE TABLE broken (id INTEGER);
+INSERT INTO missing_table VALUES (1);`)
+
+	_, err := MigrateUp(context.Background(), dbName, 0)
+	if err == nil || !strings.Contains(err.Error(), "statement 2/2") {
+		t.Fatalf("err = %v, want failing statement position", err)
+	}
+	// sqlite 的 DDL 随事务回滚，执行记录也不会写入。
+	exists, err := tableExists(context.Background(), db, "sqlite", "broken")
+	if err != nil || exists {
+		t.Fatalf("broken table exists = %v, %v", exists, err)
+	}
+	if got := migrationSummary(t, dbName); got != "001:file:pending" {
+		t.Fatalf("status = %s", got)
+	}
+}
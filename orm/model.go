@@ -3,6 +3,7 @@ package orm
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strings"
 	"sync"
 
@@ -34,11 +35,16 @@ type modelCore struct {
 	quotedTable  string
 	versionOnce  sync.Once
 	hasVersion   bool
+	hooks        modelHooks
+	cache        *queryCache
+	audit        bool
+	secrets      fieldSecrets
 }
 
 // Model 是泛型模型封装，Find/Select 返回结构体指针。
 type Model[T any] struct {
 	*modelCore
+	with []string
 }
 
 var (
@@ -58,14 +64,24 @@ func EnsureCachedSchemas(ctx context.Context) error {
 	modelCacheMu.RLock()
 	cached := make([]*modelCore, 0, len(modelCache))
 	for _, m := range modelCache {
-		cached = append(cached, m)
+		if m != nil && m.schema != nil {
+			cached = append(cached, m)
+		}
 	}
 	modelCacheMu.RUnlock()
 
-	for _, m := range cached {
-		if m == nil || m.schema == nil {
-			continue
-		}
+	// 按外键依赖排序，被引用的表先创建。
+	sort.Slice(cached, func(i, j int) bool {
+		return cached[i].table < cached[j].table
+	})
+	schemas := make([]*tableSchema, len(cached))
+	bySchema := make(map[*tableSchema]*modelCore, len(cached))
+	for i, m := range cached {
+		schemas[i] = m.schema
+		bySchema[m.schema] = m
+	}
+	for _, schema := range sortSchemasByDependency(schemas) {
+		m := bySchema[schema]
 		if err := m.ensureSchema(ctx); err != nil {
 			return err
 		}
@@ -100,8 +116,9 @@ func newModelCore(name, table string, config ModelConfig) (*modelCore, error) {
 	m.table = table
 
 	options := schemaOptions{
-		indexes: indexModels,
-		seeds:   seedRows,
+		indexes:  indexModels,
+		seeds:    seedRows,
+		database: m.dbName,
 	}
 	if err := registerSchemaOnce(table, schemaModel, options); err != nil {
 		return nil, err
@@ -114,6 +131,21 @@ func newModelCore(name, table string, config ModelConfig) (*modelCore, error) {
 		return nil, fmt.Errorf("orm: table %s schema not registered, please call orm.RegisterModel", table)
 	}
 	m.schema = schema
+	secrets, err := resolveFieldSecrets(config.Fields, schema)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkSecretJSONTags(schemaModel, table, secrets); err != nil {
+		return nil, err
+	}
+	m.secrets = secrets
+	registerTableSecrets(m.dbName, m.table, secrets)
+	m.hooks = discoverHooks(schemaModel)
+	m.cache = newQueryCache(config.Cache)
+	registerTableCache(m.dbName, m.table, m.cache)
+	if m.audit = auditEnabled(config); m.audit {
+		AuditLogModel(m.dbName)
+	}
 	m.config = m.config.withRuntimeMeta(m.name, m.table, m.dbName, m.defaultOrder, schema.labels())
 
 	if autoMigrateEnabled() {
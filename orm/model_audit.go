@@ -0,0 +1,286 @@
+package orm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shemic/dever/observe"
+	"github.com/shemic/dever/util"
+)
+
+// 审计日志：ModelConfig.Options["audit"] = true 的模型在 Insert/InsertMany/Upsert/Update/Delete 时写入 audit_log 表。
+// Update/Delete 先在同一事务内读取受影响的行作为修改前镜像，执行后按主键重新读取修改后镜像；
+// Upsert 按冲突字段读取修改前后的行，原本不存在的记为 insert，已存在且有变化的记为 update；
+// 业务修改与审计记录在同一事务中提交，任一失败都会回滚。
+
+const (
+	auditTable  = "audit_log"
+	auditInsert = "insert"
+	auditUpdate = "update"
+	auditDelete = "delete"
+)
+
+// AuditLog 是 audit_log 表的一条记录，Before/After 为整行 JSON，插入时 Before 为空，物理删除时 After 为空。
+type AuditLog struct {
+	ID        uint64          `dorm:"primaryKey;autoIncrement"`
+	Table     string          `dorm:"column:table_name;type:varchar(128);comment:表名"`
+	Action    string          `dorm:"type:varchar(16);comment:insert/update/delete"`
+	RecordID  string          `dorm:"type:varchar(64);comment:记录主键"`
+	Before    json.RawMessage `dorm:"null;comment:修改前"`
+	After     json.RawMessage `dorm:"null;comment:修改后"`
+	Actor     string          `dorm:"type:varchar(128);comment:操作人"`
+	Tenant    string          `dorm:"type:varchar(64);comment:租户"`
+	TraceID   string          `dorm:"type:varchar(64);comment:trace ID"`
+	CreatedAt time.Time       `dorm:"autoCreateTime"`
+}
+
+type auditLogIndex struct {
+	Record struct{} `index:"table_name,record_id"`
+}
+
+type auditActorContextKey struct{}
+
+var (
+	auditActorMu       sync.RWMutex
+	auditActorResolver func(context.Context) string
+)
+
+// RegisterAuditActor 设置从上下文解析操作人的函数，使用 JWT 时可调用 jwt.RegisterAuditActor。
+func RegisterAuditActor(resolver func(context.Context) string) {
+	auditActorMu.Lock()
+	defer auditActorMu.Unlock()
+	auditActorResolver = resolver
+}
+
+// WithAuditActor 为上下文显式指定操作人，优先于 RegisterAuditActor，适用于后台任务。
+func WithAuditActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(normalizeContext(ctx), auditActorContextKey{}, actor)
+}
+
+func auditActorFrom(ctx context.Context) string {
+	if actor, ok := ctx.Value(auditActorContextKey{}).(string); ok {
+		return actor
+	}
+	auditActorMu.RLock()
+	resolver := auditActorResolver
+	auditActorMu.RUnlock()
+	if resolver == nil {
+		return ""
+	}
+	return resolver(ctx)
+}
+
+// AuditLogModel 返回指定数据库（默认库可传空）的审计日志模型，用于查询审计记录。
+func AuditLogModel(database ...string) *Model[AuditLog] {
+	name := currentDefaultDatabase()
+	if len(database) > 0 && database[0] != "" {
+		name = database[0]
+	}
+	return LoadModel[AuditLog](auditTable, auditTable, ModelConfig{
+		Index:    auditLogIndex{},
+		Database: name,
+		Order:    "main.id desc",
+	})
+}
+
+func auditEnabled(config ModelConfig) bool {
+	enabled, _ := toBool(config.Options["audit"])
+	return enabled
+}
+
+// auditTx 在事务中执行 fn，已处于事务中时使用 SAVEPOINT。
+func (m *modelCore) auditTx(ctx context.Context, fn func(context.Context)) {
+	_, err := m.db()
+	panicOnError(err)
+	panicOnError(Transaction(ctx, func(ctx context.Context) error {
+		fn(ctx)
+		return nil
+	}, m.dbName))
+}
+
+func (m *modelCore) auditInsert(ctx context.Context, data map[string]any) int64 {
+	var id int64
+	m.auditTx(ctx, func(ctx context.Context) {
+		var inserted map[string]any
+		id, inserted = m.insertRow(ctx, data)
+		if id != 0 {
+			if rows := m.auditRows(WithTrashed(ctx), map[string]any{m.primaryKey: id}, false); len(rows) > 0 {
+				inserted = rows[0]
+			}
+		}
+		m.writeAudit(ctx, auditInsert, []map[string]any{nil}, []map[string]any{inserted})
+	})
+	return id
+}
+
+func (m *modelCore) auditInsertMany(ctx context.Context, rows []map[string]any, chunkSize int) []int64 {
+	var ids []int64
+	m.auditTx(ctx, func(ctx context.Context) {
+		var inserted []map[string]any
+		ids, inserted = m.insertRows(ctx, rows, chunkSize)
+		keys := make([]any, 0, len(ids))
+		for _, id := range ids {
+			if id != 0 {
+				keys = append(keys, id)
+			}
+		}
+		byKey := map[string]map[string]any{}
+		if len(keys) > 0 {
+			for _, row := range m.auditRows(WithTrashed(ctx), map[string]any{m.primaryKey: keys}, false) {
+				byKey[util.ToKeyString(row[m.primaryKey])] = row
+			}
+		}
+		before := make([]map[string]any, len(inserted))
+		after := make([]map[string]any, len(inserted))
+		for i, row := range inserted {
+			after[i] = row
+			if reloaded, ok := byKey[util.ToKeyString(ids[i])]; ok && ids[i] != 0 {
+				after[i] = reloaded
+			}
+		}
+		m.writeAudit(ctx, auditInsert, before, after)
+	})
+	return ids
+}
+
+// auditUpsert 按冲突字段读取 Upsert 前后的行：执行前不存在的记为 insert，存在且内容变化的记为 update。
+func (m *modelCore) auditUpsert(ctx context.Context, rows []map[string]any, conflict []string, run func(context.Context) int64) int64 {
+	var affected int64
+	m.auditTx(ctx, func(ctx context.Context) {
+		ctx = WithTrashed(ctx)
+		keys := make([]map[string]any, 0, len(rows))
+		seen := map[string]struct{}{}
+		for _, row := range rows {
+			values := m.fillInsertTenant(ctx, m.normalizeColumns(row))
+			key := make(map[string]any, len(conflict))
+			for _, column := range conflict {
+				key[column] = values[column]
+			}
+			if signature := conflictSignature(key, conflict); signature != "" {
+				if _, ok := seen[signature]; !ok {
+					seen[signature] = struct{}{}
+					keys = append(keys, key)
+				}
+			}
+		}
+		filters := map[string]any{"or": keys}
+		beforeByKey := map[string]map[string]any{}
+		if len(keys) > 0 {
+			for _, row := range m.auditRows(ctx, filters, m.driverName != "sqlite") {
+				beforeByKey[conflictSignature(row, conflict)] = row
+			}
+		}
+		affected = run(ctx)
+		if len(keys) == 0 {
+			return
+		}
+		var insertedAfter, updatedBefore, updatedAfter []map[string]any
+		for _, row := range m.auditRows(ctx, filters, false) {
+			previous, ok := beforeByKey[conflictSignature(row, conflict)]
+			switch {
+			case !ok:
+				insertedAfter = append(insertedAfter, row)
+			case auditImage(previous) != auditImage(row):
+				updatedBefore = append(updatedBefore, previous)
+				updatedAfter = append(updatedAfter, row)
+			}
+		}
+		if len(insertedAfter) > 0 {
+			m.writeAudit(ctx, auditInsert, make([]map[string]any, len(insertedAfter)), insertedAfter)
+		}
+		if len(updatedAfter) > 0 {
+			m.writeAudit(ctx, auditUpdate, updatedBefore, updatedAfter)
+		}
+	})
+	return affected
+}
+
+// conflictSignature 拼接冲突字段的值用于匹配修改前后的行，任一字段为空时返回空串。
+func conflictSignature(row map[string]any, conflict []string) string {
+	parts := make([]string, 0, len(conflict))
+	for _, column := range conflict {
+		value, ok := row[column]
+		if !ok || value == nil {
+			return ""
+		}
+		parts = append(parts, util.ToKeyString(value))
+	}
+	return strings.Join(parts, "\x00")
+}
+
+// auditChange 记录 Update/Delete：reload 为 false 时（物理删除）不读取修改后镜像。
+func (m *modelCore) auditChange(ctx context.Context, action string, filters any, reload bool, run func(context.Context) int64) int64 {
+	if isEmptyFilters(m.normalizeFilters(filters)) {
+		return run(ctx)
+	}
+	var affected int64
+	m.auditTx(ctx, func(ctx context.Context) {
+		before := m.auditRows(ctx, filters, m.driverName != "sqlite")
+		affected = run(ctx)
+		if affected == 0 || len(before) == 0 {
+			return
+		}
+		after := make([]map[string]any, len(before))
+		if reload {
+			keys := make([]any, 0, len(before))
+			for _, row := range before {
+				keys = append(keys, row[m.primaryKey])
+			}
+			byKey := map[string]map[string]any{}
+			for _, row := range m.auditRows(WithTrashed(ctx), map[string]any{m.primaryKey: keys}, false) {
+				byKey[util.ToKeyString(row[m.primaryKey])] = row
+			}
+			for i, row := range before {
+				after[i] = byKey[util.ToKeyString(row[m.primaryKey])]
+			}
+		}
+		m.writeAudit(ctx, action, before, after)
+	})
+	return affected
+}
+
+func (m *modelCore) auditRows(ctx context.Context, filters any, lock bool) []map[string]any {
+	return m.queryMaps(ctx, filters, map[string]any{"order": "main." + m.primaryKey}, true, lock)
+}
+
+func (m *modelCore) writeAudit(ctx context.Context, action string, before, after []map[string]any) {
+	actor := auditActorFrom(ctx)
+	traceID := observe.TraceID(ctx)
+	tenant := ""
+	if id, ok := TenantFrom(ctx); ok {
+		tenant = util.ToKeyString(id)
+	}
+	entries := make([]map[string]any, 0, len(before))
+	for i := range before {
+		row := before[i]
+		if row == nil {
+			row = after[i]
+		}
+		entries = append(entries, map[string]any{
+			"table_name": m.table,
+			"action":     action,
+			"record_id":  util.ToKeyString(row[m.primaryKey]),
+			"before":     auditImage(m.maskSecrets(before[i])),
+			"after":      auditImage(m.maskSecrets(after[i])),
+			"actor":      actor,
+			"tenant":     tenant,
+			"trace_id":   traceID,
+		})
+	}
+	AuditLogModel(m.dbName).InsertMany(ctx, entries, 0)
+}
+
+func auditImage(row map[string]any) any {
+	if row == nil {
+		return nil
+	}
+	data, err := json.Marshal(row)
+	if err != nil {
+		panic(fmt.Errorf("orm: encode audit image: %w", err))
+	}
+	return string(data)
+}
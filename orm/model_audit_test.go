@@ -0,0 +1,110 @@
+package orm_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/shemic/dever/orm"
+	"github.com/shemic/dever/orm/ormtest"
+)
+
+type auditAccount struct {
+	ID       uint64 `dorm:"primaryKey;autoIncrement"`
+	Code     string `dorm:"type:varchar(32);unique"`
+	Name     string `dorm:"type:varchar(32)"`
+	Password string `dorm:"type:varchar(128)" json:"-"`
+}
+
+var auditAccounts = ormtest.Register[auditAccount]("audit_account", orm.ModelConfig{
+	Options: map[string]any{"audit": true},
+	Fields:  map[string]orm.FieldConfig{"password": {Type: orm.FieldTypePassword}},
+})
+
+func auditLogs(ctx context.Context) []*orm.AuditLog {
+	return orm.AuditLogModel().Select(ctx, map[string]any{"table_name": "audit_account"}, map[string]any{"order": "main.id asc"})
+}
+
+func auditActions(logs []*orm.AuditLog) string {
+	actions := make([]string, 0, len(logs))
+	for _, log := range logs {
+		actions = append(actions, log.Action+":"+log.RecordID)
+	}
+	return strings.Join(actions, " ")
+}
+
+func TestAuditRecordsChanges(t *testing.T) {
+	ctx := orm.WithAuditActor(ormtest.Setup(t), "tester")
+	accounts := auditAccounts()
+	id := accounts.Insert(ctx, map[string]any{"code": "a", "name": "A", "password": "secret"})
+	accounts.Update(ctx, map[string]any{"id": id}, map[string]any{"name": "A2"})
+	accounts.Delete(ctx, map[string]any{"id": id})
+
+	logs := auditLogs(ctx)
+	if got := auditActions(logs); got != "insert:1 update:1 delete:1" {
+		t.Fatalf("actions = %q", got)
+	}
+	if logs[0].Before != nil || !strings.Contains(string(logs[0].After), `"password":"******"`) {
+		t.Fatalf("insert image before=%s after=%s", logs[0].Before, logs[0].After)
+	}
+	for _, log := range logs {
+		if strings.Contains(string(log.Before)+string(log.After), "secret") {
+			t.Fatalf("%s image leaks password: before=%s after=%s", log.Action, log.Before, log.After)
+		}
+	}
+	if !strings.Contains(string(logs[1].Before), `"name":"A"`) || !strings.Contains(string(logs[1].After), `"name":"A2"`) {
+		t.Fatalf("update image before=%s after=%s", logs[1].Before, logs[1].After)
+	}
+	if !strings.Contains(string(logs[2].Before), `"name":"A2"`) || logs[2].After != nil {
+		t.Fatalf("hard delete image before=%s after=%s", logs[2].Before, logs[2].After)
+	}
+	for _, log := range logs {
+		if log.Actor != "tester" {
+			t.Fatalf("actor = %q, want tester", log.Actor)
+		}
+	}
+}
+
+func TestAuditRecordsBatchWrites(t *testing.T) {
+	ctx := ormtest.Setup(t)
+	accounts := auditAccounts()
+	accounts.InsertMany(ctx, []map[string]any{{"code": "a", "name": "A"}, {"code": "b", "name": "B"}}, 0)
+	accounts.Upsert(ctx, []map[string]any{
+		{"code": "a", "name": "A2"},
+		{"code": "b", "name": "B"},
+		{"code": "c", "name": "C"},
+	}, []string{"code"}, []string{"name"})
+
+	logs := auditLogs(ctx)
+	// b 未变化不记录；c 为新插入（冲突行会消耗自增值，主键不固定），a 为更新并带修改前镜像。
+	if got := auditActions(logs); len(logs) != 4 || !strings.HasPrefix(got, "insert:1 insert:2 insert:") || !strings.HasSuffix(got, " update:1") {
+		t.Fatalf("actions = %q", got)
+	}
+	if !strings.Contains(string(logs[3].Before), `"name":"A"`) || !strings.Contains(string(logs[3].After), `"name":"A2"`) {
+		t.Fatalf("upsert image before=%s after=%s", logs[3].Before, logs[3].After)
+	}
+	if logs[0].Actor != "" {
+		t.Fatalf("actor without resolver = %q, want empty", logs[0].Actor)
+	}
+}
+
+func TestAuditRolledBackWithBusinessWrite(t *testing.T) {
+	ctx := ormtest.Setup(t)
+	accounts := auditAccounts()
+	accounts.Insert(ctx, map[string]any{"code": "a"})
+	err := savepoint(ctx, func(ctx context.Context) error {
+		_, err := accounts.E().Insert(ctx, map[string]any{"code": "a"})
+		return err
+	})
+	if err == nil {
+		t.Fatal("duplicate insert succeeded")
+	}
+	ormtest.AssertCount(t, ctx, "audit_log", map[string]any{"table_name": "audit_account"}, 1)
+
+	// 业务写入成功但事务回滚时，审计记录随之回滚。
+	_ = savepoint(ctx, func(ctx context.Context) error {
+		accounts.Insert(ctx, map[string]any{"code": "b"})
+		return context.Canceled
+	})
+	ormtest.AssertCount(t, ctx, "audit_log", map[string]any{"table_name": "audit_account"}, 1)
+}
@@ -0,0 +1,350 @@
+package orm
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// 批量写入：InsertMany 与 Upsert，按驱动拼装多行 VALUES。
+
+const (
+	defaultBatchSize = 500
+	// maxBatchParams 控制单条语句的占位符数量，低于 mysql/postgres 的 65535 与 sqlite 的 32766 上限。
+	maxBatchParams = 30000
+)
+
+// InsertMany 批量插入数据，按 chunkSize 分批执行（<=0 时默认 500），返回每行的主键。
+// postgres 通过 RETURNING 获取主键；mysql/sqlite 根据 LastInsertId 推算连续自增主键。
+// 多批次写入或模型定义了写入钩子时自动包裹在事务中，已处于事务中时直接复用。
+func (m *modelCore) InsertMany(ctx context.Context, rows []map[string]any, chunkSize int) []int64 {
+	if len(rows) == 0 {
+		return []int64{}
+	}
+	if m.audit {
+		return m.auditInsertMany(ctx, rows, chunkSize)
+	}
+	ids, _ := m.insertRows(ctx, rows, chunkSize)
+	return ids
+}
+
+// insertRows 执行批量插入，返回主键与实际写入的数据（与 rows 顺序一致）。
+func (m *modelCore) insertRows(ctx context.Context, rows []map[string]any, chunkSize int) ([]int64, []map[string]any) {
+	ids := make([]int64, 0, len(rows))
+	inserted := make([]map[string]any, 0, len(rows))
+	m.runBatch(ctx, rows, chunkSize, func(ctx context.Context, groups []batchGroup) {
+		ids, inserted = ids[:0], inserted[:0]
+		for _, group := range groups {
+			for _, chunk := range chunkBatchRows(group.rows, chunkSize, len(group.columns)) {
+				chunkIDs := m.insertBatch(ctx, group.columns, chunk, "")
+				for i, row := range chunk {
+					m.runAfterInsert(ctx, chunkIDs[i], row)
+				}
+				ids = append(ids, chunkIDs...)
+				inserted = append(inserted, chunk...)
+			}
+		}
+	})
+	return ids, inserted
+}
+
+// Upsert 批量插入，冲突时更新指定字段，返回影响行数。
+// conflictColumns 为空时默认取 UniqueIndexes() 的第一个唯一索引，没有唯一索引则使用主键；
+// updateColumns 为空时更新除冲突字段与主键外的全部写入字段。
+// postgres/sqlite 生成 ON CONFLICT ... DO UPDATE，mysql 生成 ON DUPLICATE KEY UPDATE。
+// 带租户列的表冲突字段必须包含租户列，避免覆盖其他租户的记录。
+func (m *modelCore) Upsert(ctx context.Context, rows []map[string]any, conflictColumns []string, updateColumns []string) int64 {
+	if len(rows) == 0 {
+		return 0
+	}
+	_, err := m.db()
+	panicOnError(err)
+	conflict := m.resolveConflictColumns(conflictColumns)
+	m.checkUpsertTenant(ctx, conflict)
+	if m.audit {
+		return m.auditUpsert(ctx, rows, conflict, func(ctx context.Context) int64 {
+			return m.upsertRows(ctx, rows, conflict, updateColumns)
+		})
+	}
+	return m.upsertRows(ctx, rows, conflict, updateColumns)
+}
+
+func (m *modelCore) upsertRows(ctx context.Context, rows []map[string]any, conflict, updateColumns []string) int64 {
+	var affected int64
+	m.runBatch(ctx, rows, 0, func(ctx context.Context, groups []batchGroup) {
+		affected = 0
+		for _, group := range groups {
+			suffix := m.upsertClause(group.columns, conflict, updateColumns)
+			for _, chunk := range chunkBatchRows(group.rows, 0, len(group.columns)) {
+				affected += m.execBatch(ctx, group.columns, chunk, suffix)
+			}
+		}
+	})
+	return affected
+}
+
+type batchGroup struct {
+	columns []string
+	rows    []map[string]any
+}
+
+// groupBatchRows 将字段集合相同的相邻行归为一组，保持原始顺序以便主键一一对应。
+func (m *modelCore) groupBatchRows(ctx context.Context, rows []map[string]any) []batchGroup {
+	ctx = normalizeContext(ctx)
+	var groups []batchGroup
+	lastSignature := ""
+	now := time.Now()
+	for _, row := range rows {
+		if len(row) == 0 {
+			panic(fmt.Errorf("orm: insert %s requires at least one column", m.table))
+		}
+		normalized := m.fillInsertTimestamps(m.normalizeColumns(row), now)
+		normalized = m.fillInsertTenant(ctx, normalized)
+		normalized = m.runBeforeInsert(ctx, normalized)
+		normalized = m.hashPasswords(normalized)
+		columns := sortedColumnKeys(normalized)
+		for _, column := range columns {
+			panicOnError(ensureIdentifier(column))
+		}
+		signature := strings.Join(columns, ",")
+		if len(groups) == 0 || signature != lastSignature {
+			groups = append(groups, batchGroup{columns: columns})
+			lastSignature = signature
+		}
+		current := &groups[len(groups)-1]
+		current.rows = append(current.rows, normalized)
+	}
+	return groups
+}
+
+// runBatch 分组后执行 run：多条语句或模型定义了写入钩子时包裹在事务中（已处于事务中时直接复用），
+// 有钩子时分组（含 BeforeInsert）也在事务内进行，任一钩子失败都会回滚整批写入。
+func (m *modelCore) runBatch(ctx context.Context, rows []map[string]any, chunkSize int, run func(context.Context, []batchGroup)) {
+	ctx = normalizeContext(ctx)
+	inTx := func(fn func(context.Context)) {
+		_, err := m.db()
+		panicOnError(err)
+		panicOnError(Transaction(ctx, func(ctx context.Context) error {
+			fn(ctx)
+			return nil
+		}, m.dbName))
+	}
+	switch {
+	case txFromContext(ctx) != nil:
+		run(ctx, m.groupBatchRows(ctx, rows))
+	case m.hooks.mutating():
+		inTx(func(ctx context.Context) {
+			run(ctx, m.groupBatchRows(ctx, rows))
+		})
+	default:
+		groups := m.groupBatchRows(ctx, rows)
+		statements := 0
+		for _, group := range groups {
+			statements += len(chunkBatchRows(group.rows, chunkSize, len(group.columns)))
+		}
+		if statements <= 1 {
+			run(ctx, groups)
+		} else {
+			inTx(func(ctx context.Context) {
+				run(ctx, groups)
+			})
+		}
+	}
+	m.InvalidateCache(ctx)
+}
+
+func (m *modelCore) insertBatch(ctx context.Context, columns []string, rows []map[string]any, suffix string) []int64 {
+	ctx, exec := m.executor(ctx)
+	quoter := m.identifierQuoter()
+	query, args := m.buildBatchInsert(columns, rows, quoter)
+	ids := make([]int64, 0, len(rows))
+	if m.driverName == "postgres" {
+		panicOnError(ensureIdentifier(m.primaryKey))
+		query += suffix + " RETURNING " + quoteWith(m.primaryKey, quoter)
+		result, err := exec.queryxContext(ctx, exec.rebind(query), args...)
+		panicOnError(err)
+		defer result.Close()
+		for result.Next() {
+			var id int64
+			panicOnError(result.Scan(&id))
+			ids = append(ids, id)
+		}
+		panicOnError(result.Err())
+		return ids
+	}
+	res, err := exec.execContext(ctx, exec.rebind(query+suffix), args...)
+	panicOnError(err)
+	lastID, err := res.LastInsertId()
+	if err != nil {
+		lastID = 0
+	}
+	// mysql 返回本批第一条自增主键，sqlite 返回最后一条。
+	firstID := lastID
+	if m.driverName == "sqlite" {
+		firstID = lastID - int64(len(rows)) + 1
+	}
+	for i, row := range rows {
+		if explicit, ok := toInt64(row[m.primaryKey]); ok && row[m.primaryKey] != nil {
+			ids = append(ids, explicit)
+			continue
+		}
+		if lastID == 0 {
+			ids = append(ids, 0)
+			continue
+		}
+		ids = append(ids, firstID+int64(i))
+	}
+	return ids
+}
+
+func (m *modelCore) execBatch(ctx context.Context, columns []string, rows []map[string]any, suffix string) int64 {
+	ctx, exec := m.executor(ctx)
+	query, args := m.buildBatchInsert(columns, rows, m.identifierQuoter())
+	res, err := exec.execContext(ctx, exec.rebind(query+suffix), args...)
+	panicOnError(err)
+	affected, err := res.RowsAffected()
+	panicOnError(err)
+	return affected
+}
+
+func (m *modelCore) buildBatchInsert(columns []string, rows []map[string]any, quoter func(string) string) (string, []any) {
+	var builder strings.Builder
+	builder.WriteString("INSERT INTO ")
+	builder.WriteString(m.quotedTableName())
+	builder.WriteString(" (")
+	for i, column := range columns {
+		if i > 0 {
+			builder.WriteString(", ")
+		}
+		builder.WriteString(quoteWith(column, quoter))
+	}
+	builder.WriteString(") VALUES ")
+	placeholders := "(" + strings.TrimSuffix(strings.Repeat("?, ", len(columns)), ", ") + ")"
+	args := make([]any, 0, len(rows)*len(columns))
+	for i, row := range rows {
+		if i > 0 {
+			builder.WriteString(", ")
+		}
+		builder.WriteString(placeholders)
+		for _, column := range columns {
+			args = append(args, row[column])
+		}
+	}
+	return builder.String(), args
+}
+
+func (m *modelCore) resolveConflictColumns(columns []string) []string {
+	if len(columns) == 0 {
+		if unique := m.UniqueIndexes(); len(unique) > 0 {
+			columns = unique[0]
+		} else {
+			columns = []string{m.primaryKey}
+		}
+	}
+	resolved := make([]string, 0, len(columns))
+	for _, column := range columns {
+		column = strings.TrimSpace(column)
+		if m.schema != nil {
+			if col, ok := m.schema.resolveColumn(column); ok {
+				column = col
+			}
+		}
+		panicOnError(ensureIdentifier(column))
+		resolved = append(resolved, column)
+	}
+	return resolved
+}
+
+func (m *modelCore) upsertClause(columns, conflict, updateColumns []string) string {
+	quoter := m.identifierQuoter()
+	skip := map[string]struct{}{strings.ToLower(m.primaryKey): {}}
+	for _, column := range conflict {
+		skip[strings.ToLower(column)] = struct{}{}
+	}
+	if m.schema != nil {
+		// 冲突更新时保留原有的创建时间。
+		for _, col := range m.schema.Columns {
+			if col.AutoCreate != "" && col.AutoUpdate == "" {
+				skip[strings.ToLower(col.Name)] = struct{}{}
+			}
+		}
+	}
+	updates := make([]string, 0, len(columns))
+	if len(updateColumns) > 0 {
+		for _, column := range updateColumns {
+			column = strings.TrimSpace(column)
+			if m.schema != nil {
+				if col, ok := m.schema.resolveColumn(column); ok {
+					column = col
+				}
+			}
+			panicOnError(ensureIdentifier(column))
+			updates = append(updates, column)
+		}
+	} else {
+		for _, column := range columns {
+			if _, ok := skip[strings.ToLower(column)]; ok {
+				continue
+			}
+			updates = append(updates, column)
+		}
+	}
+
+	assignments := make([]string, 0, len(updates))
+	if m.driverName == "mysql" {
+		for _, column := range updates {
+			quoted := quoteWith(column, quoter)
+			assignments = append(assignments, fmt.Sprintf("%s = VALUES(%s)", quoted, quoted))
+		}
+		if len(assignments) == 0 {
+			quoted := quoteWith(conflict[0], quoter)
+			assignments = append(assignments, fmt.Sprintf("%s = %s", quoted, quoted))
+		}
+		return " ON DUPLICATE KEY UPDATE " + strings.Join(assignments, ", ")
+	}
+
+	quotedConflict := make([]string, 0, len(conflict))
+	for _, column := range conflict {
+		quotedConflict = append(quotedConflict, quoteWith(column, quoter))
+	}
+	target := " ON CONFLICT (" + strings.Join(quotedConflict, ", ") + ")"
+	if len(updates) == 0 {
+		return target + " DO NOTHING"
+	}
+	for _, column := range updates {
+		quoted := quoteWith(column, quoter)
+		assignments = append(assignments, fmt.Sprintf("%s = EXCLUDED.%s", quoted, quoted))
+	}
+	return target + " DO UPDATE SET " + strings.Join(assignments, ", ")
+}
+
+func chunkBatchRows(rows []map[string]any, chunkSize, columns int) [][]map[string]any {
+	chunkSize = batchChunkSize(chunkSize, columns)
+	chunks := make([][]map[string]any, 0, (len(rows)+chunkSize-1)/chunkSize)
+	for start := 0; start < len(rows); start += chunkSize {
+		chunks = append(chunks, rows[start:min(start+chunkSize, len(rows))])
+	}
+	return chunks
+}
+
+// batchChunkSize 返回每批行数：<=0 时默认 500，并保证占位符数量不超过 maxBatchParams。
+func batchChunkSize(chunkSize, columns int) int {
+	if chunkSize <= 0 {
+		chunkSize = defaultBatchSize
+	}
+	if columns > 0 && chunkSize*columns > maxBatchParams {
+		chunkSize = max(maxBatchParams/columns, 1)
+	}
+	return chunkSize
+}
+
+func sortedColumnKeys(row map[string]any) []string {
+	keys := make([]string, 0, len(row))
+	for key := range row {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,49 @@
+package orm_test
+
+import (
+	"testing"
+
+	"github.com/shemic/dever/orm"
+	"github.com/shemic/dever/orm/ormtest"
+)
+
+type batchItem struct {
+	ID   uint64 `dorm:"primaryKey;autoIncrement"`
+	Code string `dorm:"type:varchar(32);unique"`
+	Name string `dorm:"type:varchar(32)"`
+}
+
+var batchItems = ormtest.Register[batchItem]("batch_item", orm.ModelConfig{})
+
+func TestInsertManyReturnsIDsInRowOrder(t *testing.T) {
+	ctx := ormtest.Setup(t)
+	rows := []map[string]any{
+		{"code": "a", "name": "A"},
+		{"code": "b"},
+		{"code": "c", "name": "C"},
+	}
+	ids := batchItems().InsertMany(ctx, rows, 2)
+	if len(ids) != len(rows) {
+		t.Fatalf("ids = %v, want %d ids", ids, len(rows))
+	}
+	for i, id := range ids {
+		item := batchItems().Find(ctx, map[string]any{"id": id})
+		if item == nil || item.Code != rows[i]["code"] {
+			t.Fatalf("id %d = %+v, want code %v", id, item, rows[i]["code"])
+		}
+	}
+	ormtest.AssertCount(t, ctx, "batch_item", nil, 3)
+}
+
+func TestUpsertUpdatesExistingRows(t *testing.T) {
+	ctx := ormtest.Setup(t)
+	batchItems().InsertMany(ctx, []map[string]any{{"code": "a", "name": "A"}}, 0)
+	batchItems().Upsert(ctx, []map[string]any{
+		{"code": "a", "name": "A2"},
+		{"code": "b", "name": "B"},
+	}, []string{"code"}, []string{"name"})
+
+	ormtest.AssertCount(t, ctx, "batch_item", nil, 2)
+	ormtest.AssertRowExists(t, ctx, "batch_item", map[string]any{"code": "a", "name": "A2"})
+	ormtest.AssertRowExists(t, ctx, "batch_item", map[string]any{"code": "b", "name": "B"})
+}
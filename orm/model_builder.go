@@ -0,0 +1,494 @@
+package orm
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// 链式查询构造器：在构造阶段按表结构校验列名与操作符，最终转换为 filters/options 交给 Select 等方法执行。
+
+// Direction 为 OrderBy 的排序方向。
+type Direction string
+
+const (
+	Asc  Direction = "asc"
+	Desc Direction = "desc"
+)
+
+// Query 是 Model 的链式查询。Where 追加 AND 条件，OrWhere 开启新的 OR 分支，即 a AND b OR c AND d
+// 等价于 (a AND b) OR (c AND d)；需要其他组合时使用 WhereGroup。第 N 个 Join 的别名为 tN（从 t0 开始），
+// 作为子查询时自动改写为 sqD_tN，不会遮蔽外层查询的 tN。
+// 构造过程中的错误在执行时 panic，也可以通过 Err 提前检查。
+type Query[T any] struct {
+	model    *Model[T]
+	branches [][]queryNode
+	fields   []string
+	joins    []map[string]any
+	joined   []*tableSchema
+	order    []string
+	limit    int
+	page     int
+	pageSize int
+	with     []string
+	err      error
+}
+
+// subquerySource 由任意模型的 Query 实现，用于 IN (SELECT ...) 与 EXISTS。
+type subquerySource interface {
+	buildSubquery(ctx context.Context, alias string, depth int, single bool) (subQuery, string, error)
+}
+
+// queryNode 为单个条件；kind 为空表示列比较，"exists"/"not exists" 为子查询判断，"group" 为括号分组。
+type queryNode struct {
+	kind   string
+	column string
+	op     string
+	value  any
+	sub    subquerySource
+	group  [][]queryNode
+}
+
+// Query 创建链式查询。
+func (m *Model[T]) Query() *Query[T] {
+	return &Query[T]{model: m, branches: [][]queryNode{nil}}
+}
+
+// Where 追加 column op value 条件；value 为另一个 Query 时生成子查询（如 "in"），子查询不支持 between/like/is。
+// mysql 不支持带 LIMIT 的 IN 子查询（错误 1235），需要时改用 Join 或先查出结果。
+func (q *Query[T]) Where(column, op string, value any) *Query[T] {
+	return q.add(false, queryNode{column: column, op: op, value: value})
+}
+
+// OrWhere 以 OR 开启新的条件分支。
+func (q *Query[T]) OrWhere(column, op string, value any) *Query[T] {
+	return q.add(true, queryNode{column: column, op: op, value: value})
+}
+
+// WhereColumn 比较两列，other 可以引用外层查询（如关联子查询中的 "main.id"）；不支持 in/between/is。
+func (q *Query[T]) WhereColumn(column, op, other string) *Query[T] {
+	if err := ensureQualifiedIdentifier(other); err != nil {
+		return q.fail(fmt.Errorf("orm: invalid column %q: %w", other, err))
+	}
+	return q.add(false, queryNode{column: column, op: op, value: columnRef(other)})
+}
+
+// WhereExists 追加 EXISTS 子查询条件。
+func (q *Query[T]) WhereExists(sub subquerySource) *Query[T] {
+	return q.add(false, queryNode{kind: "exists", sub: sub})
+}
+
+// WhereNotExists 追加 NOT EXISTS 子查询条件。
+func (q *Query[T]) WhereNotExists(sub subquerySource) *Query[T] {
+	return q.add(false, queryNode{kind: "not exists", sub: sub})
+}
+
+// WhereGroup 将 fn 中追加的条件作为一个整体（括号）以 AND 连接。
+func (q *Query[T]) WhereGroup(fn func(*Query[T])) *Query[T] {
+	return q.addGroup(false, fn)
+}
+
+// OrWhereGroup 将 fn 中追加的条件作为一个整体开启新的 OR 分支。
+func (q *Query[T]) OrWhereGroup(fn func(*Query[T])) *Query[T] {
+	return q.addGroup(true, fn)
+}
+
+// Select 指定查询列；作为 IN 子查询时只能有一列。
+func (q *Query[T]) Select(columns ...string) *Query[T] {
+	for _, column := range columns {
+		for _, part := range strings.Split(column, ",") {
+			if part = strings.TrimSpace(part); part != "" {
+				if err := q.checkColumn(part, "main"); err != nil {
+					return q.fail(err)
+				}
+				q.fields = append(q.fields, part)
+			}
+		}
+	}
+	return q
+}
+
+// Join 内连接 table，on 形如 "t0.user_id = main.id"。
+func (q *Query[T]) Join(table, on string) *Query[T] {
+	return q.join("INNER JOIN", table, on)
+}
+
+// LeftJoin 左连接 table。
+func (q *Query[T]) LeftJoin(table, on string) *Query[T] {
+	return q.join("LEFT JOIN", table, on)
+}
+
+// OrderBy 追加排序。
+func (q *Query[T]) OrderBy(column string, direction Direction) *Query[T] {
+	dir := strings.ToLower(strings.TrimSpace(string(direction)))
+	if dir != string(Asc) && dir != string(Desc) {
+		return q.fail(fmt.Errorf("orm: invalid order direction %q", direction))
+	}
+	if err := q.checkColumn(column, "main"); err != nil {
+		return q.fail(err)
+	}
+	q.order = append(q.order, strings.TrimSpace(column)+" "+dir)
+	return q
+}
+
+// Limit 限制返回行数。
+func (q *Query[T]) Limit(limit int) *Query[T] {
+	if limit <= 0 {
+		return q.fail(fmt.Errorf("orm: limit must be positive, got %d", limit))
+	}
+	q.limit = limit
+	return q
+}
+
+// Page 按页码与每页数量分页，与 Limit 同时设置时以 Limit 为准。
+func (q *Query[T]) Page(page, size int) *Query[T] {
+	if page <= 0 || size <= 0 {
+		return q.fail(fmt.Errorf("orm: invalid page %d/%d", page, size))
+	}
+	q.page, q.pageSize = page, size
+	return q
+}
+
+// With 预加载关联，等同于 options["with"]。
+func (q *Query[T]) With(relations ...string) *Query[T] {
+	q.with = append(q.with, relations...)
+	return q
+}
+
+// Err 返回构造过程中的第一个错误。
+func (q *Query[T]) Err() error {
+	return q.err
+}
+
+// All 执行查询并返回全部结果。
+func (q *Query[T]) All(ctx context.Context) []*T {
+	filters, options := q.mustCompile(ctx)
+	return q.model.Select(ctx, filters, options)
+}
+
+// First 返回第一条结果，没有结果时返回 nil。
+func (q *Query[T]) First(ctx context.Context) *T {
+	filters, options := q.mustCompile(ctx)
+	return q.model.Find(ctx, filters, options)
+}
+
+// Maps 执行查询并返回 map 结果。
+func (q *Query[T]) Maps(ctx context.Context) []map[string]any {
+	filters, options := q.mustCompile(ctx)
+	return q.model.SelectMap(ctx, filters, options)
+}
+
+// Count 统计满足条件的行数。
+func (q *Query[T]) Count(ctx context.Context) int64 {
+	filters, options := q.mustCompile(ctx)
+	return q.model.Count(ctx, filters, map[string]any{"join": options["join"]})
+}
+
+// ToSQL 返回将要执行的 SQL 与参数（占位符已按驱动转换），不执行查询。
+func (q *Query[T]) ToSQL(ctx context.Context) (string, []any, error) {
+	ctx = normalizeContext(ctx)
+	filters, options, err := q.compile(ctx)
+	if err != nil {
+		return "", nil, err
+	}
+	db, err := q.model.db()
+	if err != nil {
+		return "", nil, err
+	}
+	query, args, _ := q.model.prepareSelect(ctx, filters, q.model.withOptions([]map[string]any{options})[0], false)
+	return db.Rebind(query), args, nil
+}
+
+func (q *Query[T]) add(or bool, node queryNode) *Query[T] {
+	if node.sub == nil {
+		if sub, ok := node.value.(subquerySource); ok {
+			node.sub, node.value = sub, nil
+		}
+	}
+	if node.kind == "" {
+		op := normalizeComparisonOperator(node.op)
+		if op == "" {
+			return q.fail(fmt.Errorf("orm: unsupported operator %q on %s", node.op, node.column))
+		}
+		node.op = op
+		var operand any = node.value
+		if node.sub != nil {
+			operand = node.sub
+		}
+		if err := checkOperandOperator(node.column, op, operand); err != nil {
+			return q.fail(err)
+		}
+		if err := q.checkColumn(node.column, "main"); err != nil {
+			return q.fail(err)
+		}
+	}
+	if or && len(q.branches[len(q.branches)-1]) > 0 {
+		q.branches = append(q.branches, nil)
+	}
+	last := len(q.branches) - 1
+	q.branches[last] = append(q.branches[last], node)
+	return q
+}
+
+func (q *Query[T]) addGroup(or bool, fn func(*Query[T])) *Query[T] {
+	inner := &Query[T]{model: q.model, branches: [][]queryNode{nil}, joined: q.joined}
+	fn(inner)
+	if inner.err != nil {
+		return q.fail(inner.err)
+	}
+	if len(inner.branches[0]) == 0 {
+		return q
+	}
+	return q.add(or, queryNode{kind: "group", group: inner.branches})
+}
+
+func (q *Query[T]) join(kind, table, on string) *Query[T] {
+	table = strings.TrimSpace(table)
+	if err := ensureIdentifier(table); err != nil {
+		return q.fail(fmt.Errorf("orm: invalid join table %q: %w", table, err))
+	}
+	if safeJoinOnClause(on) == "" {
+		return q.fail(fmt.Errorf("orm: invalid join condition %q", on))
+	}
+	schema, ok := getRegisteredSchema(applyTablePrefix(table, q.model.dbName))
+	if !ok {
+		schema, _ = getRegisteredSchema(table)
+	}
+	q.joins = append(q.joins, map[string]any{"table": table, "on": on, "type": kind})
+	q.joined = append(q.joined, schema)
+	return q
+}
+
+func (q *Query[T]) fail(err error) *Query[T] {
+	if q.err == nil {
+		q.err = err
+	}
+	return q
+}
+
+// checkColumn 校验列名：未限定或以本查询别名限定的列必须存在于表结构中，tN 限定的列按已注册的 join 表校验，
+// 其他别名（如子查询引用外层 main）只校验标识符。
+func (q *Query[T]) checkColumn(column, alias string) error {
+	column = strings.TrimSpace(column)
+	if err := ensureQualifiedIdentifier(column); err != nil {
+		return fmt.Errorf("orm: invalid column %q: %w", column, err)
+	}
+	prefix, name := "", column
+	if idx := strings.LastIndex(column, "."); idx >= 0 {
+		prefix, name = column[:idx], column[idx+1:]
+	}
+	schema := q.model.schema
+	if prefix != "" && prefix != alias {
+		index, err := strconv.Atoi(strings.TrimPrefix(prefix, "t"))
+		if !strings.HasPrefix(prefix, "t") || err != nil || index < 0 || index >= len(q.joined) {
+			return nil
+		}
+		schema = q.joined[index]
+	}
+	if schema == nil {
+		return nil
+	}
+	if _, ok := schema.resolveColumn(name); !ok {
+		return fmt.Errorf("orm: unknown column %q on %s", column, schema.Table)
+	}
+	return nil
+}
+
+func (q *Query[T]) mustCompile(ctx context.Context) (any, map[string]any) {
+	filters, options, err := q.compile(normalizeContext(ctx))
+	panicOnError(err)
+	return filters, options
+}
+
+func (q *Query[T]) compile(ctx context.Context) (any, map[string]any, error) {
+	if q.err != nil {
+		return nil, nil, q.err
+	}
+	filters, err := q.compileBranches(ctx, q.branches, "main", 1)
+	if err != nil {
+		return nil, nil, err
+	}
+	options := map[string]any{}
+	if len(q.fields) > 0 {
+		options["field"] = q.qualify(strings.Join(q.fields, ","), "main")
+	}
+	if len(q.joins) > 0 {
+		options["join"] = q.joins
+	}
+	if len(q.order) > 0 {
+		options["order"] = q.qualify(strings.Join(q.order, ","), "main")
+	}
+	if q.limit > 0 {
+		options["limit"] = q.limit
+	} else if q.page > 0 {
+		options["page"], options["pageSize"] = q.page, q.pageSize
+	}
+	if len(q.with) > 0 {
+		options["with"] = q.with
+	}
+	return filters, options, nil
+}
+
+// compileBranches 把条件分支转换为 filters 条件树，未限定的列加上 alias 前缀。
+func (q *Query[T]) compileBranches(ctx context.Context, branches [][]queryNode, alias string, depth int) (any, error) {
+	var ors []any
+	for _, branch := range branches {
+		if len(branch) == 0 {
+			continue
+		}
+		ands := make([]any, 0, len(branch))
+		for _, node := range branch {
+			cond, err := q.compileNode(ctx, node, alias, depth)
+			if err != nil {
+				return nil, err
+			}
+			ands = append(ands, cond)
+		}
+		ors = append(ors, map[string]any{"and": ands})
+	}
+	switch len(ors) {
+	case 0:
+		return map[string]any{}, nil
+	case 1:
+		return ors[0], nil
+	default:
+		return map[string]any{"or": ors}, nil
+	}
+}
+
+func (q *Query[T]) compileNode(ctx context.Context, node queryNode, alias string, depth int) (any, error) {
+	if node.kind == "group" {
+		return q.compileBranches(ctx, node.group, alias, depth)
+	}
+	var value any = node.value
+	if node.sub != nil {
+		sub, database, err := node.sub.buildSubquery(ctx, "sq"+strconv.Itoa(depth), depth+1, node.kind == "")
+		if err != nil {
+			return nil, err
+		}
+		if database != q.model.dbName {
+			return nil, fmt.Errorf("orm: subquery on database %s cannot be used in %s", database, q.model.dbName)
+		}
+		value = sub
+	}
+	if node.kind != "" {
+		return map[string]any{node.kind: value}, nil
+	}
+	if ref, ok := value.(columnRef); ok {
+		value = columnRef(q.localColumn(string(ref), alias))
+	}
+	return map[string]any{q.qualify(node.column, alias): map[string]any{node.op: value}}, nil
+}
+
+// qualify 为逗号分隔列表中未限定的列加上 alias 前缀，保留排序方向。
+func (q *Query[T]) qualify(list, alias string) string {
+	parts := strings.Split(list, ",")
+	for i, part := range parts {
+		part = strings.TrimSpace(part)
+		if !strings.Contains(part, ".") {
+			part = alias + "." + part
+		} else {
+			part = q.localColumn(part, alias)
+		}
+		parts[i] = part
+	}
+	return strings.Join(parts, ", ")
+}
+
+// localColumn 将子查询中引用本查询 join 的 tN. 前缀改写为 <alias>_tN.，避免遮蔽外层查询的同名别名；
+// 顶层查询（alias 为 main）与超出本查询 join 数量的 tN 保持不变。
+func (q *Query[T]) localColumn(column, alias string) string {
+	idx := strings.Index(column, ".")
+	if alias == "main" || idx <= 0 {
+		return column
+	}
+	prefix := column[:idx]
+	index, err := strconv.Atoi(strings.TrimPrefix(prefix, "t"))
+	if !strings.HasPrefix(prefix, "t") || err != nil || index < 0 || index >= len(q.joins) {
+		return column
+	}
+	return alias + "_" + column
+}
+
+// subqueryJoins 为子查询的 join 生成 <alias>_tN 别名，并同步改写 on 条件中的 tN 引用。
+func (q *Query[T]) subqueryJoins(alias string) []map[string]any {
+	if len(q.joins) == 0 {
+		return nil
+	}
+	joins := make([]map[string]any, 0, len(q.joins))
+	for _, join := range q.joins {
+		on, _ := join["on"].(string)
+		terms := strings.Fields(on)
+		for i, term := range terms {
+			terms[i] = q.localColumn(term, alias)
+		}
+		joins = append(joins, map[string]any{"table": join["table"], "type": join["type"], "on": strings.Join(terms, " ")})
+	}
+	return joins
+}
+
+// buildSubquery 以 alias 生成子查询 SQL，列名按本模型的表结构校验，同样追加软删除条件。
+func (q *Query[T]) buildSubquery(ctx context.Context, alias string, depth int, single bool) (subQuery, string, error) {
+	if q.err != nil {
+		return subQuery{}, "", q.err
+	}
+	if single && len(q.fields) != 1 {
+		return subQuery{}, "", fmt.Errorf("orm: subquery on %s must select exactly one column", q.model.table)
+	}
+	m := q.model
+	if _, err := m.db(); err != nil {
+		return subQuery{}, "", err
+	}
+	for _, branch := range q.branches {
+		for _, node := range branch {
+			if err := q.recheckAlias(node, alias); err != nil {
+				return subQuery{}, "", err
+			}
+		}
+	}
+	filters, err := q.compileBranches(ctx, q.branches, alias, depth)
+	if err != nil {
+		return subQuery{}, "", err
+	}
+	filters = scopeSchemaFilters(ctx, m.schema, filters, alias+".")
+	fields := "1"
+	if len(q.fields) > 0 {
+		fields = q.qualify(strings.Join(q.fields, ","), alias)
+	}
+	options := map[string]any{}
+	if q.limit > 0 {
+		options["limit"] = q.limit
+	}
+	query, args := m.buildSelectQuery(filters, selectQueryConfig{
+		alias:        alias,
+		fields:       fields,
+		joinRaw:      q.subqueryJoins(alias),
+		joinAlias:    alias + "_t",
+		order:        q.qualify(strings.Join(q.order, ","), alias),
+		applyOrder:   len(q.order) > 0,
+		limitOptions: options,
+	})
+	return subQuery{query: query, args: args}, m.dbName, nil
+}
+
+// recheckAlias 拒绝在子查询中用 main 限定本表的列：子查询中的 main 指向外层查询。
+func (q *Query[T]) recheckAlias(node queryNode, alias string) error {
+	if node.kind == "group" {
+		for _, branch := range node.group {
+			for _, inner := range branch {
+				if err := q.recheckAlias(inner, alias); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+	if node.kind != "" {
+		return nil
+	}
+	if strings.HasPrefix(strings.TrimSpace(node.column), "main.") {
+		return fmt.Errorf("orm: subquery column %q must not be qualified with main (main refers to the outer query)", node.column)
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package orm_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/shemic/dever/orm"
+	"github.com/shemic/dever/orm/ormtest"
+)
+
+type builderUser struct {
+	ID   uint64 `dorm:"primaryKey;autoIncrement"`
+	Name string `dorm:"type:varchar(32)"`
+}
+
+type builderTag struct {
+	ID            uint64 `dorm:"primaryKey;autoIncrement"`
+	BuilderUserID uint64
+	Label         string `dorm:"type:varchar(32)"`
+}
+
+type builderOrder struct {
+	ID            uint64 `dorm:"primaryKey;autoIncrement"`
+	BuilderUserID uint64
+	Kind          string `dorm:"type:varchar(32)"`
+	Amount        int
+}
+
+var (
+	builderUsers  = ormtest.Register[builderUser]("builder_user", orm.ModelConfig{})
+	builderTags   = ormtest.Register[builderTag]("builder_tag", orm.ModelConfig{})
+	builderOrders = ormtest.Register[builderOrder]("builder_order", orm.ModelConfig{})
+)
+
+func seedBuilder(t *testing.T) context.Context {
+	ctx := ormtest.Setup(t)
+	alice := builderUsers().Insert(ctx, map[string]any{"name": "alice"})
+	bob := builderUsers().Insert(ctx, map[string]any{"name": "bob"})
+	builderUsers().Insert(ctx, map[string]any{"name": "carol"})
+	builderTags().InsertMany(ctx, []map[string]any{
+		{"builder_user_id": alice, "label": "vip"},
+		{"builder_user_id": bob, "label": "vip"},
+	}, 0)
+	builderOrders().InsertMany(ctx, []map[string]any{
+		{"builder_user_id": alice, "kind": "vip", "amount": 100},
+		{"builder_user_id": bob, "kind": "normal", "amount": 50},
+	}, 0)
+	return ctx
+}
+
+func builderNames(users []*builderUser) string {
+	names := make([]string, 0, len(users))
+	for _, user := range users {
+		names = append(names, user.Name)
+	}
+	return strings.Join(names, ",")
+}
+
+func TestQueryRejectsUnsupportedOperandOperators(t *testing.T) {
+	ormtest.Setup(t)
+	sub := builderOrders().Query().Select("builder_user_id")
+	cases := map[string]*orm.Query[builderUser]{
+		"subquery between": builderUsers().Query().Where("id", "between", sub),
+		"subquery like":    builderUsers().Query().Where("name", "like", sub),
+		"subquery is":      builderUsers().Query().Where("id", "is", sub),
+		"column in":        builderUsers().Query().WhereColumn("id", "in", "main.name"),
+		"column between":   builderUsers().Query().WhereColumn("id", "between", "main.name"),
+		"column is not":    builderUsers().Query().WhereColumn("id", "is not", "main.name"),
+	}
+	for name, query := range cases {
+		if query.Err() == nil || !strings.Contains(query.Err().Error(), "does not support") {
+			t.Errorf("%s: err = %v, want unsupported operator error", name, query.Err())
+		}
+	}
+}
+
+func TestSubqueryJoinAliasesDoNotShadowOuterJoins(t *testing.T) {
+	ctx := seedBuilder(t)
+	// 外层 t0 为 builder_tag；子查询的 t0 为其自身 join 的 builder_user，改写为 sq1_t0。
+	sub := builderOrders().Query().
+		Select("builder_user_id").
+		Join("builder_user", "t0.id = builder_user_id").
+		Where("t0.name", "=", "alice").
+		Where("kind", "=", "vip")
+	query := builderUsers().Query().
+		Select("id", "name").
+		Join("builder_tag", "t0.builder_user_id = main.id").
+		Where("t0.label", "=", "vip").
+		Where("id", "in", sub).
+		OrderBy("id", orm.Asc)
+
+	statement, _, err := query.ToSQL(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, fragment := range []string{"builder_tag AS t0", "builder_user AS sq1_t0 ON sq1_t0.id = builder_user_id", `"sq1_t0"."name"`} {
+		if !strings.Contains(statement, fragment) {
+			t.Errorf("SQL missing %q:\n%s", fragment, statement)
+		}
+	}
+	if got := builderNames(query.All(ctx)); got != "alice" {
+		t.Fatalf("users = %s, want alice", got)
+	}
+
+	// 子查询没有 join 时 tN 仍指向外层查询的 join。
+	correlated := builderUsers().Query().
+		Join("builder_tag", "t0.builder_user_id = main.id").
+		WhereExists(builderOrders().Query().WhereColumn("kind", "=", "t0.label")).
+		OrderBy("id", orm.Asc)
+	if got := builderNames(correlated.All(ctx)); got != "alice,bob" {
+		t.Fatalf("correlated users = %s, want alice,bob", got)
+	}
+}
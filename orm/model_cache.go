@@ -0,0 +1,186 @@
+package orm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/shemic/dever/cache"
+	"github.com/shemic/dever/observe"
+	"github.com/shemic/dever/util"
+)
+
+// 查询缓存：ModelConfig.Cache 开启后，Find/Select/Count/Sum 的结果按过滤条件与选项缓存，
+// 任意写操作递增缓存 epoch 使其整体失效。缓存按 数据库+表 登记，任一模型实例写入该表都会使同表的全部缓存失效；
+// 带 with/join 的查询结果依赖其他表，不进入缓存。事务内与 UsePrimary 的读取不走缓存，事务内的写入在提交后才失效。
+
+// CacheConfig 描述模型查询缓存，TTL 为 0 表示只依赖写操作失效。
+type CacheConfig struct {
+	TTL        time.Duration
+	MaxEntries int
+}
+
+// CacheStats 是模型查询缓存的命中统计。
+type CacheStats struct {
+	Hits    uint64 `json:"hits"`
+	Misses  uint64 `json:"misses"`
+	Entries int    `json:"entries"`
+	Epoch   uint64 `json:"epoch"`
+}
+
+var (
+	tableCachesMu sync.RWMutex
+	tableCaches   = map[string][]*queryCache{}
+)
+
+type queryCache struct {
+	store  *cache.VersionedCache[string, any]
+	hits   atomic.Uint64
+	misses atomic.Uint64
+}
+
+func newQueryCache(cfg *CacheConfig) *queryCache {
+	if cfg == nil {
+		return nil
+	}
+	var opts []cache.Option
+	if cfg.TTL > 0 {
+		opts = append(opts, cache.WithTTL(cfg.TTL))
+	}
+	if cfg.MaxEntries > 0 {
+		opts = append(opts, cache.WithMaxEntries(cfg.MaxEntries))
+	}
+	return &queryCache{store: cache.New[string, any](opts...)}
+}
+
+// CacheStats 返回模型查询缓存的命中统计，未开启缓存时返回零值。
+func (m *modelCore) CacheStats() CacheStats {
+	if m.cache == nil {
+		return CacheStats{}
+	}
+	return CacheStats{
+		Hits:    m.cache.hits.Load(),
+		Misses:  m.cache.misses.Load(),
+		Entries: m.cache.store.Len(),
+		Epoch:   m.cache.store.Epoch(),
+	}
+}
+
+// InvalidateCache 使该表（同一数据库）上全部模型的查询缓存失效，适用于绕过 ORM 直接执行 SQL 修改数据的场景；
+// 事务内在提交后生效。
+func (m *modelCore) InvalidateCache(ctx context.Context) {
+	invalidateTableCache(ctx, m.dbName, m.table)
+}
+
+// cacheKey 生成缓存键；未开启缓存、处于事务、强制读主库、带 with/join 或条件无法序列化时返回 false。
+func (m *modelCore) cacheKey(ctx context.Context, operation string, parts ...any) (string, bool) {
+	if m.cache == nil || txFromContext(ctx) != nil || usePrimaryFromContext(ctx) {
+		return "", false
+	}
+	for _, part := range parts {
+		if options, ok := part.(map[string]any); ok && readsOtherTables(options) {
+			return "", false
+		}
+	}
+	data, err := json.Marshal(parts)
+	if err != nil {
+		return "", false
+	}
+	return fmt.Sprintf("%s:%d:%s:%s", operation, trashedModeFrom(ctx), tenantCacheKey(ctx), data), true
+}
+
+func readsOtherTables(options map[string]any) bool {
+	for _, key := range []string{"with", "join"} {
+		if value, ok := options[key]; ok && value != nil && util.ToStringTrimmed(value) != "" && util.ToStringTrimmed(value) != "[]" {
+			return true
+		}
+	}
+	return false
+}
+
+func registerTableCache(dbName, table string, c *queryCache) {
+	if c == nil {
+		return
+	}
+	key := tableCacheKey(dbName, table)
+	tableCachesMu.Lock()
+	defer tableCachesMu.Unlock()
+	tableCaches[key] = append(tableCaches[key], c)
+}
+
+// invalidateTableCache 使指定表的全部模型缓存失效，处于事务中时在提交后执行。
+func invalidateTableCache(ctx context.Context, dbName, table string) {
+	key := tableCacheKey(dbName, table)
+	tableCachesMu.RLock()
+	registered := len(tableCaches[key]) > 0
+	tableCachesMu.RUnlock()
+	if !registered {
+		return
+	}
+	AfterCommit(ctx, func(context.Context) {
+		tableCachesMu.RLock()
+		defer tableCachesMu.RUnlock()
+		for _, c := range tableCaches[key] {
+			c.store.Invalidate()
+		}
+	})
+}
+
+func tableCacheKey(dbName, table string) string {
+	if strings.TrimSpace(dbName) == "" {
+		dbName = currentDefaultDatabase()
+	}
+	return strings.ToLower(strings.TrimSpace(dbName)) + "|" + strings.ToLower(strings.TrimSpace(table))
+}
+
+// cachedQuery 读取缓存，未命中时执行 load 并写回；相同键的并发加载只执行一次。
+func cachedQuery[V any](ctx context.Context, m *modelCore, key string, load func() V, clone func(V) V) V {
+	if value, ok := m.cache.store.Load(key); ok {
+		m.cache.hits.Add(1)
+		m.observeCache(ctx, "hit")
+		return clone(value.(V))
+	}
+	m.cache.misses.Add(1)
+	m.observeCache(ctx, "miss")
+	value, err := m.cache.store.GetOrSet(key, func() (any, error) {
+		return try(load)
+	})
+	panicOnError(err)
+	return clone(value.(V))
+}
+
+func (m *modelCore) observeCache(ctx context.Context, result string) {
+	if !observe.Enabled() {
+		return
+	}
+	_, span := observe.Start(ctx, observe.KindDB, "cache", map[string]any{
+		"db.operation":    "cache",
+		"db.table":        m.table,
+		"db.cache":        result,
+		"db.cache.hits":   m.cache.hits.Load(),
+		"db.cache.misses": m.cache.misses.Load(),
+	})
+	span.End()
+}
+
+func cloneCachedRows(rows []map[string]any) []map[string]any {
+	if result := cloneMapSlice(rows); result != nil {
+		return result
+	}
+	return []map[string]any{}
+}
+
+func cloneCachedRow(row map[string]any) map[string]any {
+	if result := cloneAnyMap(row); result != nil {
+		return result
+	}
+	return map[string]any{}
+}
+
+func cloneCachedScalar[V any](value V) V {
+	return value
+}
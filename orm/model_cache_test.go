@@ -0,0 +1,102 @@
+package orm_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/shemic/dever/orm"
+	"github.com/shemic/dever/orm/ormtest"
+)
+
+type cacheItem struct {
+	ID   uint64 `dorm:"primaryKey;autoIncrement"`
+	Name string `dorm:"type:varchar(32)"`
+}
+
+var cacheItems = ormtest.Register[cacheItem]("cache_item", orm.ModelConfig{Cache: &orm.CacheConfig{}})
+
+// 查询缓存只服务事务外的读取，这里的用例使用 context.Background() 直接提交到测试库，表名不与其他用例共用。
+func TestQueryCacheHitsAndKeys(t *testing.T) {
+	ormtest.Setup(t)
+	ctx := context.Background()
+	items := cacheItems()
+	items.Insert(ctx, map[string]any{"name": "a"})
+	items.Insert(ctx, map[string]any{"name": "b"})
+
+	statements := recordQueries(t, func() {
+		items.Find(ctx, map[string]any{"name": "a"})
+		items.Find(ctx, map[string]any{"name": "a"})
+		items.Find(ctx, map[string]any{"name": "b"})
+		items.Count(ctx, map[string]any{"name": "a"})
+		items.Count(ctx, map[string]any{"name": "a"})
+	})
+	if got := countQueries(statements, `"cache_item"`); got != 3 {
+		t.Fatalf("queries = %d, want 3 (one per distinct key): %q", got, statements)
+	}
+
+	// 事务内、UsePrimary 与 join 查询不走缓存。
+	statements = recordQueries(t, func() {
+		items.Find(orm.UsePrimary(ctx), map[string]any{"name": "a"})
+		items.SelectMap(ctx, map[string]any{"main.name": "a"}, map[string]any{"join": []map[string]any{
+			{"table": "cache_item", "on": "t0.id = main.id"},
+		}})
+		_ = orm.Transaction(ctx, func(ctx context.Context) error {
+			items.Find(ctx, map[string]any{"name": "a"})
+			return nil
+		})
+	})
+	if got := countQueries(statements, `"cache_item"`); got != 3 {
+		t.Fatalf("uncached queries = %d, want 3: %q", got, statements)
+	}
+}
+
+func TestQueryCacheInvalidation(t *testing.T) {
+	ormtest.Setup(t)
+	ctx := context.Background()
+	items := cacheItems()
+	// 同表的另一个模型实例（配置不同）写入时同样使缓存失效。
+	other := orm.LoadModel[cacheItem]("cache_item_other", "cache_item", orm.ModelConfig{Order: "id asc"})
+	id := items.Insert(ctx, map[string]any{"name": "before"})
+
+	if got := items.Find(ctx, map[string]any{"id": id}).Name; got != "before" {
+		t.Fatalf("name = %s", got)
+	}
+	other.Update(ctx, map[string]any{"id": id}, map[string]any{"name": "after"})
+	if got := items.Find(ctx, map[string]any{"id": id}).Name; got != "after" {
+		t.Fatalf("name after update through another instance = %s, want after", got)
+	}
+
+	// 事务内的写入在提交后才失效，回滚不失效。
+	epoch := items.CacheStats().Epoch
+	errAbort := errors.New("abort")
+	_ = orm.Transaction(ctx, func(ctx context.Context) error {
+		items.Update(ctx, map[string]any{"id": id}, map[string]any{"name": "rolled-back"})
+		return errAbort
+	})
+	if got := items.CacheStats().Epoch; got != epoch {
+		t.Fatalf("epoch after rollback = %d, want %d", got, epoch)
+	}
+	err := orm.Transaction(ctx, func(ctx context.Context) error {
+		items.Update(ctx, map[string]any{"id": id}, map[string]any{"name": "committed"})
+		if got := items.CacheStats().Epoch; got != epoch {
+			t.Fatalf("epoch before commit = %d, want %d", got, epoch)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := items.CacheStats().Epoch; got == epoch {
+		t.Fatal("commit should invalidate the cache")
+	}
+	if got := items.Find(ctx, map[string]any{"id": id}).Name; got != "committed" {
+		t.Fatalf("name after commit = %s", got)
+	}
+
+	epoch = items.CacheStats().Epoch
+	items.InvalidateCache(ctx)
+	if got := items.CacheStats().Epoch; got == epoch {
+		t.Fatal("InvalidateCache should bump the epoch")
+	}
+}
@@ -20,12 +20,16 @@ type ModelConfig struct {
 	Options   map[string]any
 	Relations []Relation
 	Fields    map[string]FieldConfig
+	Cache     *CacheConfig
 
 	schema any
 }
 
+// FieldConfig 描述字段的业务类型；Type 为 password 时 Algorithm 指定哈希算法（bcrypt/argon2id，默认 bcrypt）。
+// password/hidden 字段在结构体结果中为零值，结构体字段必须声明 json:"-"，否则 LoadModel 返回错误。
 type FieldConfig struct {
-	Type string
+	Type      string
+	Algorithm string
 }
 
 type Relation struct {
@@ -45,6 +49,8 @@ type Relation struct {
 	Order            string
 	ThroughOrder     string
 	OptionOrder      string
+	Table            string // 预加载的关联表名，Option* 字段仍用于后台选项数据
+	TableKey         string // 多对多时关联表被 TargetField 引用的字段，默认 id
 }
 
 func (c ModelConfig) clone() ModelConfig {
@@ -54,6 +60,10 @@ func (c ModelConfig) clone() ModelConfig {
 	result.Options = cloneAnyMap(c.Options)
 	result.Relations = cloneRelations(c.Relations)
 	result.Fields = cloneFieldConfigs(c.Fields)
+	if c.Cache != nil {
+		cacheConfig := *c.Cache
+		result.Cache = &cacheConfig
+	}
 	if len(c.Indexes) > 0 {
 		result.Indexes = append([]any(nil), c.Indexes...)
 	}
@@ -75,6 +85,7 @@ func normalizeModelConfig(config ModelConfig) ModelConfig {
 				continue
 			}
 			config.Type = strings.ToLower(strings.TrimSpace(config.Type))
+			config.Algorithm = strings.ToLower(strings.TrimSpace(config.Algorithm))
 			if config.Type == "" {
 				continue
 			}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"reflect"
+	"strconv"
 	"strings"
 
 	"github.com/shemic/dever/util"
@@ -22,6 +23,9 @@ func convertValue(value any, target reflect.Type) (reflect.Value, bool) {
 	if val.Type().ConvertibleTo(target) {
 		return val.Convert(target), true
 	}
+	if scanned, ok := scanValue(value, target); ok {
+		return scanned, true
+	}
 	switch target.Kind() {
 	case reflect.String:
 		switch v := value.(type) {
@@ -60,10 +64,53 @@ func convertValue(value any, target reflect.Type) (reflect.Value, bool) {
 			out.SetBool(v)
 			return out, true
 		}
+	case reflect.Struct:
+		if record, ok := value.(map[string]any); ok {
+			return convertRecord(record, target)
+		}
+	case reflect.Slice:
+		if records, ok := value.([]map[string]any); ok {
+			return convertRecords(records, target)
+		}
 	}
 	return reflect.Value{}, false
 }
 
+// convertRecord 将预加载的关联记录映射为结构体。
+func convertRecord(record map[string]any, target reflect.Type) (reflect.Value, bool) {
+	ptr := reflect.New(target)
+	if err := mapToStruct(record, ptr.Interface()); err != nil {
+		return reflect.Value{}, false
+	}
+	return ptr.Elem(), true
+}
+
+func convertRecords(records []map[string]any, target reflect.Type) (reflect.Value, bool) {
+	elemType := target.Elem()
+	isPointer := elemType.Kind() == reflect.Pointer
+	structType := elemType
+	if isPointer {
+		structType = elemType.Elem()
+	}
+	if structType.Kind() != reflect.Struct {
+		return reflect.Value{}, false
+	}
+	out := reflect.MakeSlice(target, 0, len(records))
+	for _, record := range records {
+		item, ok := convertRecord(record, structType)
+		if !ok {
+			return reflect.Value{}, false
+		}
+		if isPointer {
+			ptr := reflect.New(structType)
+			ptr.Elem().Set(item)
+			item = ptr
+		}
+		out = reflect.Append(out, item)
+	}
+	return out, true
+}
+
 func normalizeValueByType(value any, sqlType string) any {
 	if value == nil {
 		return nil
@@ -82,7 +129,7 @@ func normalizeValueByType(value any, sqlType string) any {
 		if v, ok := toJSONValue(value); ok {
 			return v
 		}
-	case strings.Contains(typeName, "DOUBLE"), strings.Contains(typeName, "FLOAT"), strings.Contains(typeName, "DECIMAL"):
+	case strings.Contains(typeName, "DOUBLE"), strings.Contains(typeName, "FLOAT"), strings.Contains(typeName, "DECIMAL"), strings.Contains(typeName, "NUMERIC"):
 		if v, ok := toFloat64(value); ok {
 			return v
 		}
@@ -90,6 +137,38 @@ func normalizeValueByType(value any, sqlType string) any {
 	return value
 }
 
+// normalizeColumnValue 按列定义转换查询结果：dorm:"decimal" 声明的定点小数保持字符串以免丢失精度，
+// 其余列（含 dorm:"type:decimal(...)"）按 normalizeValueByType 转换，DECIMAL 为 float64。
+func normalizeColumnValue(value any, column columnDef) any {
+	if column.Exact && isDecimalSQLType(column.Type) {
+		return decimalString(value)
+	}
+	return normalizeValueByType(value, column.Type)
+}
+
+func isDecimalSQLType(sqlType string) bool {
+	typeName := strings.ToUpper(sqlType)
+	return strings.Contains(typeName, "DECIMAL") || strings.Contains(typeName, "NUMERIC")
+}
+
+// decimalString 将驱动返回的定点小数统一为字符串，sqlite 按数值亲和性返回的浮点数按最短表示转换。
+func decimalString(value any) any {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case []byte:
+		return string(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32)
+	case string:
+		return v
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
 func toInt64(value any) (int64, bool) {
 	return util.ParseInt64(value)
 }
@@ -133,11 +212,17 @@ func toJSONValue(value any) (any, bool) {
 
 func assignStructField(field reflect.Value, binding fieldBinding, value any) {
 	if value == nil {
-		if binding.isPointer {
+		if binding.isPointer || binding.json {
 			field.Set(reflect.Zero(field.Type()))
 		}
 		return
 	}
+	if binding.json {
+		if decoded, ok := decodeJSONField(value, binding.targetType); ok {
+			field.Set(decoded)
+		}
+		return
+	}
 	if binding.isPointer {
 		converted, ok := convertValue(value, binding.elemType)
 		if !ok {
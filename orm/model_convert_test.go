@@ -0,0 +1,94 @@
+package orm_test
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/shemic/dever/orm"
+	"github.com/shemic/dever/orm/ormtest"
+)
+
+// cents 自行实现数据库读写，以整数分保存金额。
+type cents struct{ value int64 }
+
+func (c *cents) Scan(value any) error {
+	_, err := fmt.Sscanf(fmt.Sprint(value), "%d", &c.value)
+	return err
+}
+
+func (c cents) Value() (driver.Value, error) { return c.value, nil }
+
+type convertProfile struct {
+	Age  int    `json:"age"`
+	City string `json:"city"`
+}
+
+type convertRecord struct {
+	ID      uint64 `dorm:"primaryKey;autoIncrement"`
+	Tags    []string
+	Meta    map[string]any
+	Profile convertProfile
+	Extra   *map[string]any
+	Data    []byte
+	Price   string `dorm:"decimal:10,2"`
+	Amount  cents  `dorm:"type:bigint"`
+}
+
+var convertRecords = ormtest.Register[convertRecord]("convert_record", orm.ModelConfig{})
+
+func TestColumnValuesRoundTrip(t *testing.T) {
+	ctx := ormtest.Setup(t)
+	id := convertRecords().Insert(ctx, map[string]any{
+		"tags":    []string{"a", "b"},
+		"meta":    map[string]any{"k": "v"},
+		"profile": convertProfile{Age: 30, City: "sh"},
+		"data":    []byte{0, 1, 2},
+		"price":   "12.50",
+		"amount":  cents{value: 1999},
+	})
+
+	got := convertRecords().Find(ctx, map[string]any{"id": id})
+	want := &convertRecord{
+		ID:      uint64(id),
+		Tags:    []string{"a", "b"},
+		Meta:    map[string]any{"k": "v"},
+		Profile: convertProfile{Age: 30, City: "sh"},
+		Data:    []byte{0, 1, 2},
+		Price:   got.Price,
+		Amount:  cents{value: 1999},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("record = %+v, want %+v", got, want)
+	}
+	// sqlite 的 DECIMAL 为数值亲和性，12.50 读回为 12.5。
+	if got.Price != "12.5" && got.Price != "12.50" {
+		t.Fatalf("price = %q", got.Price)
+	}
+
+	row := convertRecords().FindMap(ctx, map[string]any{"id": id})
+	if tags, ok := row["tags"].([]any); !ok || len(tags) != 2 || row["extra"] != nil {
+		t.Fatalf("map row = %v, want decoded JSON and nil extra", row)
+	}
+}
+
+func TestJSONColumnsFollowPointerNullability(t *testing.T) {
+	ctx := ormtest.Setup(t)
+	err := savepoint(ctx, func(ctx context.Context) error {
+		_, err := convertRecords().E().Insert(ctx, map[string]any{
+			"tags": []string{}, "meta": map[string]any{}, "profile": convertProfile{}, "price": "1", "amount": cents{},
+		})
+		return err
+	})
+	if err == nil {
+		t.Fatal("omitting the NOT NULL []byte column should fail")
+	}
+	id := convertRecords().Insert(ctx, map[string]any{
+		"tags": []string{}, "meta": map[string]any{}, "profile": convertProfile{}, "data": []byte{}, "price": "1", "amount": cents{},
+	})
+	if got := convertRecords().Find(ctx, map[string]any{"id": id}); got.Extra != nil {
+		t.Fatalf("extra = %v, want nil for NULL", got.Extra)
+	}
+}
@@ -0,0 +1,262 @@
+package orm
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/shemic/dever/util"
+)
+
+// 分组聚合：GroupBy 与 Max/Min/Avg/CountDistinct，表达式统一经过 safeAggregateExpression 校验。
+
+// Max 返回指定字段的最大值，没有记录时返回 nil，结果按列类型转换。
+func (m *modelCore) Max(ctx context.Context, column string, filters any, options ...map[string]any) any {
+	return m.extremum(ctx, "MAX", column, filters, options...)
+}
+
+// Min 返回指定字段的最小值，没有记录时返回 nil，结果按列类型转换。
+func (m *modelCore) Min(ctx context.Context, column string, filters any, options ...map[string]any) any {
+	return m.extremum(ctx, "MIN", column, filters, options...)
+}
+
+// Avg 返回指定字段的平均值，没有记录时返回 0。
+func (m *modelCore) Avg(ctx context.Context, column string, filters any, options ...map[string]any) float64 {
+	expr := m.aggregateColumnExpression("Avg", "AVG", column, false)
+	return m.aggregateFloat(ctx, expr, filters, firstOption(options))
+}
+
+// CountDistinct 统计指定字段去重后的数量。
+func (m *modelCore) CountDistinct(ctx context.Context, column string, filters any, options ...map[string]any) int64 {
+	expr := m.aggregateColumnExpression("CountDistinct", "COUNT", column, true)
+	return m.aggregateInt(ctx, expr, filters, firstOption(options))
+}
+
+// GroupBy 按 groupColumns（"main.status, main.type" 或 []string）分组，aggregates 为 别名 => 聚合表达式，
+// 如 {"total": "SUM(amount)", "users": "COUNT(DISTINCT user_id)"}。options 支持 join、having、order、limit/page 与 into；
+// having 使用与 filters 相同的条件写法，键可以直接写聚合别名。分组列按表结构转换类型，COUNT 结果为 int64，
+// SUM/AVG 为 float64，MAX/MIN 按列类型转换。
+func (m *modelCore) GroupBy(ctx context.Context, groupColumns any, aggregates map[string]any, filters any, options ...map[string]any) []map[string]any {
+	opt := firstOption(options)
+	if _, into := opt["into"]; !into {
+		if key, ok := m.cacheKey(ctx, "group", groupColumns, aggregates, filters, opt); ok {
+			return cachedQuery(ctx, m, key, func() []map[string]any {
+				return m.queryGroups(ctx, groupColumns, aggregates, filters, opt)
+			}, cloneCachedRows)
+		}
+	}
+	return m.queryGroups(ctx, groupColumns, aggregates, filters, opt)
+}
+
+type groupAggregate struct {
+	alias string
+	expr  string
+	call  aggregateCall
+	known bool
+}
+
+func (m *modelCore) queryGroups(ctx context.Context, groupColumns any, aggregates map[string]any, filters any, options map[string]any) []map[string]any {
+	groups := parseGroupColumns(groupColumns)
+	items := parseGroupAggregates(aggregates)
+	if len(groups) == 0 && len(items) == 0 {
+		panic("orm: GroupBy requires group columns or aggregates")
+	}
+
+	if filters == nil {
+		filters = map[string]any{}
+	}
+	filters = m.normalizeFilters(filters)
+	filters = m.scopeFilters(ctx, filters, "main.")
+	ctx, exec := m.readExecutor(ctx, false)
+	quoter := m.identifierQuoter()
+
+	fields := append([]string(nil), groups...)
+	for _, item := range items {
+		fields = append(fields, item.expr+" AS "+quoter(item.alias))
+	}
+	query := fmt.Sprintf("SELECT %s FROM %s AS main", strings.Join(fields, ", "), m.quotedTableName())
+	if options != nil {
+		if joinClause := buildJoinClause(options["join"]); joinClause != "" {
+			query += " " + joinClause
+		}
+	}
+	whereClause, args := buildWhereClauseWithQuoter(filters, quoter)
+	if whereClause != "" {
+		query += " WHERE " + whereClause
+	}
+	if len(groups) > 0 {
+		query += " GROUP BY " + strings.Join(groups, ", ")
+	}
+	if options != nil {
+		// postgres 不允许在 HAVING 中引用输出别名，别名替换为对应的聚合表达式。
+		havingClause, havingArgs := buildWhereClauseWithQuoter(options["having"], groupHavingQuoter(items, quoter))
+		if havingClause != "" {
+			query += " HAVING " + havingClause
+			args = append(args, havingArgs...)
+		}
+		if order := safeOrderClause(options["order"], ""); order != "" {
+			query += " ORDER BY " + order
+		}
+	}
+	query = appendLimit(query, options)
+	query = exec.rebind(query)
+
+	if dest, ok := options["into"]; ok {
+		panicOnError(ensureIntoDest(dest))
+		panicOnError(exec.selectContext(ctx, dest, query, args...))
+		return []map[string]any{}
+	}
+
+	rows, err := exec.queryxContext(ctx, query, args...)
+	panicOnError(err)
+	defer rows.Close()
+
+	result := []map[string]any{}
+	for rows.Next() {
+		record := make(map[string]any)
+		panicOnError(rows.MapScan(record))
+		values := make(map[string]any, len(items))
+		for _, item := range items {
+			for key, value := range record {
+				if strings.EqualFold(key, item.alias) {
+					values[item.alias] = m.convertAggregateValue(item, value)
+					delete(record, key)
+					break
+				}
+			}
+		}
+		normalizeMapWithSchema(record, m.schema)
+		for alias, value := range values {
+			record[alias] = value
+		}
+		result = append(result, record)
+	}
+	panicOnError(rows.Err())
+	return result
+}
+
+func (m *modelCore) extremum(ctx context.Context, function, column string, filters any, options ...map[string]any) any {
+	expr := m.aggregateColumnExpression(function[:1]+strings.ToLower(function[1:]), function, column, false)
+	opt := firstOption(options)
+	load := func() any {
+		var value any
+		row := m.aggregateRow(ctx, expr, filters, opt)
+		if err := row.Scan(&value); err != nil {
+			panic(normalizeError(err))
+		}
+		call, _ := parseAggregateCall(expr)
+		return m.convertAggregateValue(groupAggregate{expr: expr, call: call, known: true}, value)
+	}
+	if key, ok := m.cacheKey(ctx, "aggregate_value", expr, filters, opt); ok {
+		return cachedQuery(ctx, m, key, load, cloneCachedScalar[any])
+	}
+	return load()
+}
+
+func (m *modelCore) aggregateColumnExpression(method, function, column string, distinct bool) string {
+	trimmed := strings.TrimSpace(column)
+	if trimmed == "" {
+		panic(fmt.Sprintf("orm: %s column cannot be empty", method))
+	}
+	if err := ensureQualifiedIdentifier(trimmed); err != nil {
+		panic(fmt.Errorf("orm: %s column %q is invalid: %w", method, trimmed, err))
+	}
+	if distinct {
+		return function + "(DISTINCT " + trimmed + ")"
+	}
+	return function + "(" + trimmed + ")"
+}
+
+// convertAggregateValue 按聚合函数转换结果类型：COUNT 为 int64，SUM/AVG 为 float64，MAX/MIN 按列类型。
+func (m *modelCore) convertAggregateValue(item groupAggregate, value any) any {
+	if b, ok := value.([]byte); ok {
+		value = string(b)
+	}
+	if !item.known {
+		return value
+	}
+	switch item.call.function {
+	case "COUNT":
+		count, _ := util.ParseInt64(value)
+		return count
+	case "SUM", "AVG":
+		if value == nil {
+			return nil
+		}
+		if number, ok := util.ParseFloat64(value); ok {
+			return number
+		}
+		return value
+	default:
+		column := item.call.column
+		if idx := strings.LastIndex(column, "."); idx >= 0 {
+			column = column[idx+1:]
+		}
+		if def, ok := m.schema.resolveColumnDef(column); ok {
+			return normalizeColumnValue(value, def)
+		}
+		return value
+	}
+}
+
+func parseGroupColumns(raw any) []string {
+	var text string
+	switch value := raw.(type) {
+	case nil:
+		return nil
+	case []string:
+		text = strings.Join(value, ",")
+	default:
+		text = util.ToStringTrimmed(value)
+	}
+	if strings.TrimSpace(text) == "" {
+		return nil
+	}
+	fields := safeFieldList(text)
+	if fields == "" || strings.Contains(fields, "*") {
+		panic(fmt.Errorf("orm: invalid group columns %q", text))
+	}
+	return strings.Split(fields, ", ")
+}
+
+func parseGroupAggregates(aggregates map[string]any) []groupAggregate {
+	aliases := make([]string, 0, len(aggregates))
+	for alias := range aggregates {
+		aliases = append(aliases, alias)
+	}
+	sort.Strings(aliases)
+	items := make([]groupAggregate, 0, len(aliases))
+	for _, alias := range aliases {
+		if err := ensureIdentifier(alias); err != nil {
+			panic(fmt.Errorf("orm: invalid aggregate alias %q: %w", alias, err))
+		}
+		expr := safeAggregateExpression(aggregates[alias], "")
+		if expr == "" {
+			panic(fmt.Errorf("orm: invalid aggregate expression for %s", alias))
+		}
+		call, known := parseAggregateCall(expr)
+		items = append(items, groupAggregate{alias: alias, expr: expr, call: call, known: known})
+	}
+	return items
+}
+
+func groupHavingQuoter(items []groupAggregate, quoter func(string) string) func(string) string {
+	expressions := make(map[string]string, len(items))
+	for _, item := range items {
+		expressions[item.alias] = item.expr
+	}
+	return func(name string) string {
+		if expr, ok := expressions[name]; ok {
+			return expr
+		}
+		return quoter(name)
+	}
+}
+
+func firstOption(options []map[string]any) map[string]any {
+	if len(options) > 0 {
+		return options[0]
+	}
+	return nil
+}
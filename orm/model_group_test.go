@@ -0,0 +1,121 @@
+package orm_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/shemic/dever/orm"
+	"github.com/shemic/dever/orm/ormtest"
+)
+
+type groupSale struct {
+	ID     uint64 `dorm:"primaryKey;autoIncrement"`
+	Region string `dorm:"type:varchar(16)"`
+	UserID int
+	Amount int
+	Price  string `dorm:"decimal:10,2"`
+}
+
+type groupStat struct {
+	Region string  `db:"region"`
+	Orders int64   `db:"orders"`
+	Amount float64 `db:"amount"`
+}
+
+var groupSales = ormtest.Register[groupSale]("group_sale", orm.ModelConfig{})
+
+func seedGroupSales(t *testing.T) context.Context {
+	ctx := ormtest.Setup(t)
+	groupSales().InsertMany(ctx, []map[string]any{
+		{"region": "east", "user_id": 1, "amount": 10, "price": "1.10"},
+		{"region": "east", "user_id": 1, "amount": 20, "price": "2.20"},
+		{"region": "east", "user_id": 2, "amount": 30, "price": "3.30"},
+		{"region": "west", "user_id": 3, "amount": 5, "price": "0.50"},
+		{"region": "north", "user_id": 4, "amount": 40, "price": "9.99"},
+	}, 0)
+	return ctx
+}
+
+func TestAggregates(t *testing.T) {
+	ctx := seedGroupSales(t)
+	sales := groupSales()
+
+	if got := sales.Sum(ctx, "amount", map[string]any{"region": "east"}); got != 60 {
+		t.Errorf("Sum = %v, want 60", got)
+	}
+	if got := sales.Max(ctx, "amount", nil); !reflect.DeepEqual(got, int64(40)) {
+		t.Errorf("Max = %#v, want int64(40)", got)
+	}
+	if got := sales.Min(ctx, "amount", map[string]any{"region": "east"}); !reflect.DeepEqual(got, int64(10)) {
+		t.Errorf("Min = %#v, want int64(10)", got)
+	}
+	if got := sales.Avg(ctx, "amount", map[string]any{"region": "east"}); got != 20 {
+		t.Errorf("Avg = %v, want 20", got)
+	}
+	if got := sales.CountDistinct(ctx, "user_id", map[string]any{"region": "east"}); got != 2 {
+		t.Errorf("CountDistinct = %d, want 2", got)
+	}
+	if got := sales.Max(ctx, "amount", map[string]any{"region": "south"}); got != nil {
+		t.Errorf("Max without rows = %#v, want nil", got)
+	}
+	if got := sales.Avg(ctx, "amount", map[string]any{"region": "south"}); got != 0 {
+		t.Errorf("Avg without rows = %v, want 0", got)
+	}
+	// decimal 标签声明的列保持字符串，避免丢失精度。
+	if got := sales.Max(ctx, "price", nil); got != "9.99" {
+		t.Errorf("Max decimal = %#v, want \"9.99\"", got)
+	}
+}
+
+func TestGroupByHavingAndOrder(t *testing.T) {
+	ctx := seedGroupSales(t)
+	rows := groupSales().GroupBy(ctx, "main.region",
+		map[string]any{"orders": "COUNT(*)", "amount": "SUM(main.amount)", "users": "COUNT(DISTINCT main.user_id)"},
+		map[string]any{"main.amount": map[string]any{">": 1}},
+		map[string]any{
+			"having": map[string]any{"orders": map[string]any{">=": 1}, "amount": map[string]any{">": 6}},
+			"order":  "amount desc",
+		},
+	)
+	want := []map[string]any{
+		{"region": "east", "orders": int64(3), "amount": float64(60), "users": int64(2)},
+		{"region": "north", "orders": int64(1), "amount": float64(40), "users": int64(1)},
+	}
+	if !reflect.DeepEqual(rows, want) {
+		t.Fatalf("GroupBy =\n%#v\nwant\n%#v", rows, want)
+	}
+
+	limited := groupSales().GroupBy(ctx, []string{"main.region"}, map[string]any{"orders": "COUNT(*)"}, nil,
+		map[string]any{"order": "main.region asc", "limit": 1})
+	if len(limited) != 1 || limited[0]["region"] != "east" {
+		t.Fatalf("GroupBy limit = %v", limited)
+	}
+
+	var stats []groupStat
+	groupSales().GroupBy(ctx, "main.region", map[string]any{"orders": "COUNT(*)", "amount": "SUM(main.amount)"}, nil,
+		map[string]any{"order": "main.region asc", "into": &stats})
+	if len(stats) != 3 || stats[0] != (groupStat{Region: "east", Orders: 3, Amount: 60}) {
+		t.Fatalf("GroupBy into = %+v", stats)
+	}
+}
+
+func TestGroupByRejectsUnsafeExpressions(t *testing.T) {
+	ctx := seedGroupSales(t)
+	cases := map[string]map[string]any{
+		"function":   {"x": "LENGTH(main.region)"},
+		"expression": {"x": "SUM(main.amount) + 1"},
+		"injection":  {"x": "SUM(main.amount); DROP TABLE group_sale"},
+		"star sum":   {"x": "SUM(*)"},
+	}
+	for name, aggregates := range cases {
+		if _, err := groupSales().E().GroupBy(ctx, "main.region", aggregates, nil); err == nil {
+			t.Errorf("%s: GroupBy(%v) accepted", name, aggregates)
+		}
+	}
+	// RawSQL 聚合不做类型转换，保留驱动返回的值。
+	rows := groupSales().GroupBy(ctx, nil, map[string]any{"total": orm.RawSQL("SUM(main.amount) * 2")}, nil)
+	if len(rows) != 1 || rows[0]["total"] != int64(210) {
+		t.Fatalf("RawSQL aggregate = %v", rows)
+	}
+}
@@ -0,0 +1,161 @@
+package orm
+
+import (
+	"context"
+	"reflect"
+)
+
+// 生命周期钩子：模型结构体实现以下接口即可，LoadModel 时一次性识别。
+// 钩子收到的 ctx 与调用方一致，处于 orm.Transaction 中时钩子内的查询共用同一事务；
+// 钩子返回 error 会中止当前操作（panic 风格 API 直接 panic，E() 视图返回该 error）；
+// 定义了写入钩子的模型，Insert/Update/Delete 等写操作在事务中执行（已处于事务中时直接复用），
+// After 钩子失败时已执行的写入随之回滚。
+// 写入钩子在 LoadModel 时绑定到一个零值接收者上，所有调用共用该实例：接收者字段始终为空，
+// 不要读取或在其上保存状态，要写入的数据通过 data、条件通过 filters 传入；
+// AfterFind 例外，它在每条映射出的记录上调用，接收者即该记录。
+
+// BeforeInsertHook 在插入前调用，可修改 data（已完成字段名归一化）；InsertMany/Upsert 逐行调用。
+type BeforeInsertHook interface {
+	BeforeInsert(ctx context.Context, data map[string]any) error
+}
+
+// AfterInsertHook 在插入后调用，id 为新记录主键；InsertMany 逐行调用，Upsert 不调用。
+type AfterInsertHook interface {
+	AfterInsert(ctx context.Context, id int64, data map[string]any) error
+}
+
+// BeforeUpdateHook 在更新前调用，可修改 data。
+type BeforeUpdateHook interface {
+	BeforeUpdate(ctx context.Context, filters any, data map[string]any) error
+}
+
+// AfterUpdateHook 在更新后调用。
+type AfterUpdateHook interface {
+	AfterUpdate(ctx context.Context, filters any, data map[string]any, affected int64) error
+}
+
+// BeforeDeleteHook 在删除（含软删除与 ForceDelete）前调用。
+type BeforeDeleteHook interface {
+	BeforeDelete(ctx context.Context, filters any) error
+}
+
+// AfterDeleteHook 在删除（含软删除与 ForceDelete）后调用。
+type AfterDeleteHook interface {
+	AfterDelete(ctx context.Context, filters any, affected int64) error
+}
+
+// AfterFindHook 在 Select/Find/Iterate/Paginate 映射出结构体后对每条记录调用。
+type AfterFindHook interface {
+	AfterFind(ctx context.Context) error
+}
+
+type modelHooks struct {
+	beforeInsert BeforeInsertHook
+	afterInsert  AfterInsertHook
+	beforeUpdate BeforeUpdateHook
+	afterUpdate  AfterUpdateHook
+	beforeDelete BeforeDeleteHook
+	afterDelete  AfterDeleteHook
+	afterFind    bool
+}
+
+func discoverHooks(schema any) modelHooks {
+	var hooks modelHooks
+	if schema == nil {
+		return hooks
+	}
+	t := reflect.TypeOf(schema)
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	instance := reflect.New(t).Interface()
+	hooks.beforeInsert, _ = instance.(BeforeInsertHook)
+	hooks.afterInsert, _ = instance.(AfterInsertHook)
+	hooks.beforeUpdate, _ = instance.(BeforeUpdateHook)
+	hooks.afterUpdate, _ = instance.(AfterUpdateHook)
+	hooks.beforeDelete, _ = instance.(BeforeDeleteHook)
+	hooks.afterDelete, _ = instance.(AfterDeleteHook)
+	_, hooks.afterFind = instance.(AfterFindHook)
+	return hooks
+}
+
+// mutating 表示定义了任一写入钩子。
+func (h modelHooks) mutating() bool {
+	return h.beforeInsert != nil || h.afterInsert != nil ||
+		h.beforeUpdate != nil || h.afterUpdate != nil ||
+		h.beforeDelete != nil || h.afterDelete != nil
+}
+
+// hookTx 在模型定义了写入钩子且上下文不在事务中时，将 fn 包裹在事务中执行。
+func (m *modelCore) hookTx(ctx context.Context, fn func(context.Context) int64) int64 {
+	ctx = normalizeContext(ctx)
+	if !m.hooks.mutating() || txStateFromContext(ctx) != nil {
+		return fn(ctx)
+	}
+	_, err := m.db()
+	panicOnError(err)
+	var result int64
+	panicOnError(Transaction(ctx, func(ctx context.Context) error {
+		result = fn(ctx)
+		return nil
+	}, m.dbName))
+	return result
+}
+
+func (m *modelCore) runBeforeInsert(ctx context.Context, data map[string]any) map[string]any {
+	if m.hooks.beforeInsert == nil {
+		return data
+	}
+	if data == nil {
+		data = map[string]any{}
+	}
+	panicOnError(m.hooks.beforeInsert.BeforeInsert(ctx, data))
+	return m.normalizeColumns(data)
+}
+
+func (m *modelCore) runAfterInsert(ctx context.Context, id int64, data map[string]any) {
+	if m.hooks.afterInsert != nil {
+		panicOnError(m.hooks.afterInsert.AfterInsert(ctx, id, data))
+	}
+}
+
+func (m *modelCore) runBeforeUpdate(ctx context.Context, filters any, data map[string]any) map[string]any {
+	if m.hooks.beforeUpdate == nil {
+		return data
+	}
+	if data == nil {
+		data = map[string]any{}
+	}
+	panicOnError(m.hooks.beforeUpdate.BeforeUpdate(ctx, filters, data))
+	return m.normalizeColumns(data)
+}
+
+func (m *modelCore) runAfterUpdate(ctx context.Context, filters any, data map[string]any, affected int64) {
+	if m.hooks.afterUpdate != nil {
+		panicOnError(m.hooks.afterUpdate.AfterUpdate(ctx, filters, data, affected))
+	}
+}
+
+func (m *modelCore) runBeforeDelete(ctx context.Context, filters any) {
+	if m.hooks.beforeDelete != nil {
+		panicOnError(m.hooks.beforeDelete.BeforeDelete(ctx, filters))
+	}
+}
+
+func (m *modelCore) runAfterDelete(ctx context.Context, filters any, affected int64) {
+	if m.hooks.afterDelete != nil {
+		panicOnError(m.hooks.afterDelete.AfterDelete(ctx, filters, affected))
+	}
+}
+
+// hydrate 将查询结果映射为结构体并执行 AfterFind 钩子。
+func (m *Model[T]) hydrate(ctx context.Context, record map[string]any) *T {
+	dest := new(T)
+	_ = mapToStruct(record, dest)
+	if m.hooks.afterFind {
+		if hook, ok := any(dest).(AfterFindHook); ok {
+			panicOnError(hook.AfterFind(ctx))
+		}
+	}
+	return dest
+}
@@ -0,0 +1,118 @@
+package orm_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/shemic/dever/orm"
+	"github.com/shemic/dever/orm/ormtest"
+)
+
+type hookNote struct {
+	ID      uint64 `dorm:"primaryKey;autoIncrement"`
+	Title   string `dorm:"type:varchar(32)"`
+	Display string `dorm:"-"`
+}
+
+var (
+	hookNotes  = ormtest.Register[hookNote]("hook_note", orm.ModelConfig{})
+	hookEvents []string
+	errHookNo  = errors.New("hook rejected")
+)
+
+func (n *hookNote) BeforeInsert(ctx context.Context, data map[string]any) error {
+	hookEvents = append(hookEvents, fmt.Sprintf("before-insert:%v:%q", data["title"], n.Title))
+	if data["title"] == "reject-before" {
+		return errHookNo
+	}
+	data["title"] = fmt.Sprint(data["title"], "!")
+	return nil
+}
+
+func (n *hookNote) AfterInsert(ctx context.Context, id int64, data map[string]any) error {
+	hookEvents = append(hookEvents, fmt.Sprintf("after-insert:%v", data["title"]))
+	if data["title"] == "reject-after!" {
+		return errHookNo
+	}
+	return nil
+}
+
+func (n *hookNote) BeforeUpdate(ctx context.Context, filters any, data map[string]any) error {
+	hookEvents = append(hookEvents, "before-update")
+	return nil
+}
+
+func (n *hookNote) AfterUpdate(ctx context.Context, filters any, data map[string]any, affected int64) error {
+	hookEvents = append(hookEvents, fmt.Sprintf("after-update:%d", affected))
+	return nil
+}
+
+func (n *hookNote) BeforeDelete(ctx context.Context, filters any) error {
+	hookEvents = append(hookEvents, "before-delete")
+	return nil
+}
+
+func (n *hookNote) AfterDelete(ctx context.Context, filters any, affected int64) error {
+	hookEvents = append(hookEvents, fmt.Sprintf("after-delete:%d", affected))
+	return nil
+}
+
+func (n *hookNote) AfterFind(ctx context.Context) error {
+	n.Display = "#" + n.Title
+	return nil
+}
+
+func TestHooksRunInOrder(t *testing.T) {
+	ctx := ormtest.Setup(t)
+	hookEvents = nil
+	id := hookNotes().Insert(ctx, map[string]any{"title": "a"})
+	hookNotes().Update(ctx, map[string]any{"id": id}, map[string]any{"title": "b"})
+	hookNotes().Delete(ctx, map[string]any{"id": id})
+
+	// 写入钩子的接收者始终是零值，数据只通过 data 传入。
+	want := `[before-insert:a:"" after-insert:a! before-update after-update:1 before-delete after-delete:1]`
+	if got := fmt.Sprint(hookEvents); got != want {
+		t.Fatalf("events = %s, want %s", got, want)
+	}
+}
+
+func TestHookErrorsAbortAndRollBack(t *testing.T) {
+	ctx := ormtest.Setup(t)
+	hookEvents = nil
+
+	if _, err := hookNotes().E().Insert(ctx, map[string]any{"title": "reject-before"}); !errors.Is(err, errHookNo) {
+		t.Fatalf("before hook err = %v, want %v", err, errHookNo)
+	}
+	err := orm.Transaction(ctx, func(ctx context.Context) error {
+		_, err := hookNotes().E().Insert(ctx, map[string]any{"title": "reject-after"})
+		return err
+	})
+	if !errors.Is(err, errHookNo) {
+		t.Fatalf("after hook err = %v, want %v", err, errHookNo)
+	}
+	ormtest.AssertCount(t, ctx, "hook_note", nil, 0)
+	if len(hookEvents) != 3 {
+		t.Fatalf("events = %v, want before-insert only for the first row", hookEvents)
+	}
+}
+
+func TestAfterFindRunsPerRecord(t *testing.T) {
+	ctx := ormtest.Setup(t)
+	hookNotes().InsertMany(ctx, []map[string]any{{"title": "a"}, {"title": "b"}}, 0)
+
+	for _, note := range hookNotes().Select(ctx, nil, map[string]any{"order": "id asc"}) {
+		if note.Display != "#"+note.Title {
+			t.Fatalf("display = %q for %q", note.Display, note.Title)
+		}
+	}
+	note := hookNotes().Find(ctx, map[string]any{"title": "b!"})
+	if note == nil || note.Display != "#b!" {
+		t.Fatalf("find = %+v", note)
+	}
+	// map 结果不经过 AfterFind。
+	if row := hookNotes().FindMap(ctx, map[string]any{"title": "a!"}); row["display"] != nil {
+		t.Fatalf("find map = %v", row)
+	}
+}
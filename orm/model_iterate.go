@@ -0,0 +1,123 @@
+package orm
+
+import (
+	"context"
+	"iter"
+	"strings"
+)
+
+// 流式查询：保持游标打开逐行返回，避免大结果集一次性载入内存。
+// 迭代期间连接（或事务）被游标占用，循环体内不要在同一事务上发起其他查询；
+// with/into 选项在流式查询中不生效。
+
+// Iterate 逐行返回查询结果，提前 break 或 ctx 取消时自动关闭游标。
+func (m *Model[T]) Iterate(ctx context.Context, filters any, options ...map[string]any) iter.Seq2[*T, error] {
+	var opt map[string]any
+	if len(options) > 0 {
+		opt = options[0]
+	}
+	return func(yield func(*T, error) bool) {
+		for record, err := range m.modelCore.iterateMaps(ctx, filters, opt, true) {
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			dest, err := try(func() *T {
+				return m.hydrate(ctx, m.hideRecord(record, opt))
+			})
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			if !yield(dest, nil) {
+				return
+			}
+		}
+	}
+}
+
+// IterateMap 逐行返回 map 结果，字段处理规则与 SelectMap 一致。
+func (m *Model[T]) IterateMap(ctx context.Context, filters any, options ...map[string]any) iter.Seq2[map[string]any, error] {
+	var opt map[string]any
+	if len(options) > 0 {
+		opt = options[0]
+	}
+	normalizeKeys := true
+	if opt != nil {
+		if field, ok := opt["field"]; ok && strings.TrimSpace(safeSelectFields(field, "")) != "" {
+			normalizeKeys = false
+		}
+	}
+	return func(yield func(map[string]any, error) bool) {
+		for record, err := range m.modelCore.iterateMaps(ctx, filters, opt, normalizeKeys) {
+			if !yield(m.hideRecord(record, opt), err) {
+				return
+			}
+		}
+	}
+}
+
+func (m *modelCore) iterateMaps(ctx context.Context, filters any, options map[string]any, normalizeKeys bool) iter.Seq2[map[string]any, error] {
+	return func(yield func(map[string]any, error) bool) {
+		ctx := ctx
+		var (
+			exec  executor
+			query string
+			args  []any
+		)
+		_, err := try(func() struct{} {
+			ctx, exec = m.readExecutor(ctx, false)
+			query, args, _ = m.prepareSelect(ctx, filters, options, false)
+			query = exec.rebind(query)
+			return struct{}{}
+		})
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+
+		ctx, span := startDBObserve(ctx, "iterate", query)
+		count := 0
+		defer func() {
+			span.SetAttribute("db.rows", count)
+			finishDBObserve(span, normalizeObserveDBError(err))
+		}()
+
+		rows, err := exec.queryxContext(ctx, query, args...)
+		if err != nil {
+			err = normalizeError(err)
+			yield(nil, err)
+			return
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			if err = ctx.Err(); err != nil {
+				yield(nil, err)
+				return
+			}
+			record := make(map[string]any)
+			if err = rows.MapScan(record); err != nil {
+				err = normalizeError(err)
+				yield(nil, err)
+				return
+			}
+			if normalizeKeys {
+				normalizeMapWithSchema(record, m.schema)
+			} else {
+				normalizeMapValues(record)
+			}
+			count++
+			if !yield(record, nil) {
+				return
+			}
+		}
+		if err = rows.Err(); err == nil {
+			err = ctx.Err()
+		}
+		if err != nil {
+			err = normalizeError(err)
+			yield(nil, err)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package orm_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/shemic/dever/orm"
+	"github.com/shemic/dever/orm/ormtest"
+)
+
+type iterItem struct {
+	ID     uint64 `dorm:"primaryKey;autoIncrement"`
+	Name   string `dorm:"type:varchar(32)"`
+	Secret string `dorm:"type:varchar(32)" json:"-"`
+}
+
+var iterItems = ormtest.Register[iterItem]("iter_item", orm.ModelConfig{
+	Fields: map[string]orm.FieldConfig{"secret": {Type: orm.FieldTypeHidden}},
+})
+
+func seedIterItems(ctx context.Context) {
+	iterItems().InsertMany(ctx, []map[string]any{
+		{"name": "a", "secret": "s"},
+		{"name": "b", "secret": "s"},
+		{"name": "c", "secret": "s"},
+	}, 0)
+}
+
+func TestIterateYieldsRowsInOrder(t *testing.T) {
+	ctx := ormtest.Setup(t)
+	seedIterItems(ctx)
+
+	var names []string
+	for item, err := range iterItems().Iterate(ctx, nil, map[string]any{"order": "id desc"}) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		names = append(names, item.Name)
+	}
+	if len(names) != 3 || names[0] != "c" || names[2] != "a" {
+		t.Fatalf("names = %v", names)
+	}
+
+	for row, err := range iterItems().IterateMap(ctx, map[string]any{"name": "b"}) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, ok := row["secret"]; ok || row["name"] != "b" {
+			t.Fatalf("row = %v, hidden field should be stripped", row)
+		}
+	}
+}
+
+func TestIterateBreakClosesCursor(t *testing.T) {
+	ctx := ormtest.Setup(t)
+	seedIterItems(ctx)
+
+	seen := 0
+	for _, err := range iterItems().Iterate(ctx, nil) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		seen++
+		break
+	}
+	if seen != 1 {
+		t.Fatalf("seen = %d, want 1", seen)
+	}
+	// 游标已关闭，同一事务可以继续查询与写入。
+	iterItems().Insert(ctx, map[string]any{"name": "d"})
+	if total := iterItems().Count(ctx, nil); total != 4 {
+		t.Fatalf("count = %d, want 4", total)
+	}
+}
+
+func TestIterateStopsOnContextCancel(t *testing.T) {
+	ctx := ormtest.Setup(t)
+	seedIterItems(ctx)
+
+	cancelCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	seen := 0
+	var last error
+	for _, err := range iterItems().Iterate(cancelCtx, nil) {
+		if err != nil {
+			last = err
+			break
+		}
+		seen++
+		cancel()
+	}
+	if seen != 1 || !errors.Is(last, context.Canceled) {
+		t.Fatalf("seen = %d, err = %v, want 1 row then context.Canceled", seen, last)
+	}
+}
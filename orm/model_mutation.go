@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/jmoiron/sqlx"
 )
@@ -12,37 +13,65 @@ import (
 
 // Insert 插入数据，返回自增主键（若驱动支持）。
 func (m *modelCore) Insert(ctx context.Context, data map[string]any) int64 {
+	if m.audit {
+		return m.auditInsert(ctx, data)
+	}
+	return m.hookTx(ctx, func(ctx context.Context) int64 {
+		id, _ := m.insertRow(ctx, data)
+		return id
+	})
+}
+
+// insertRow 执行插入，返回主键与实际写入的数据。
+func (m *modelCore) insertRow(ctx context.Context, data map[string]any) (int64, map[string]any) {
 	if len(data) > 0 {
 		data = m.normalizeColumns(data)
 	}
+	data = m.fillInsertTimestamps(data, time.Now())
+	data = m.fillInsertTenant(ctx, data)
 	ctx, exec := m.executor(ctx)
+	data = m.runBeforeInsert(ctx, data)
+	data = m.hashPasswords(data)
 	quoter := m.identifierQuoter()
 	query, payload, err := buildInsertQuery(m.table, data, quoter)
 	panicOnError(err)
+	var id int64
 	if m.driverName == "postgres" {
 		panicOnError(ensureIdentifier(m.primaryKey))
 		query = query + " RETURNING " + quoteWith(m.primaryKey, quoter)
 		namedQuery, args, err := sqlx.Named(query, payload)
 		panicOnError(err)
 		namedQuery = exec.rebind(namedQuery)
-		var id int64
 		panicOnError(exec.queryRowxContext(ctx, namedQuery, args...).Scan(&id))
-		return id
-	}
-	res, err := exec.namedExecContext(ctx, query, payload)
-	panicOnError(err)
-	if id, err := res.LastInsertId(); err == nil {
-		return id
+	} else {
+		res, err := exec.namedExecContext(ctx, query, payload)
+		panicOnError(err)
+		if lastID, err := res.LastInsertId(); err == nil {
+			id = lastID
+		}
 	}
-	return 0
+	m.InvalidateCache(ctx)
+	m.runAfterInsert(ctx, id, data)
+	return id, data
 }
 
 // Update 根据条件更新数据。
 func (m *modelCore) Update(ctx context.Context, filters any, data map[string]any, optimistic ...bool) int64 {
+	useOptimistic := len(optimistic) > 0 && optimistic[0]
+	if m.audit {
+		return m.auditChange(ctx, auditUpdate, filters, true, func(ctx context.Context) int64 {
+			return m.updateRows(ctx, filters, data, useOptimistic, true)
+		})
+	}
+	return m.hookTx(ctx, func(ctx context.Context) int64 {
+		return m.updateRows(ctx, filters, data, useOptimistic, true)
+	})
+}
+
+func (m *modelCore) updateRows(ctx context.Context, filters any, data map[string]any, useOptimistic, hooks bool) int64 {
 	ctx, exec := m.executor(ctx)
 	quoter := m.identifierQuoter()
 
-	useOptimistic := len(optimistic) > 0 && optimistic[0]
 	if len(data) == 0 && !useOptimistic {
 		panic(fmt.Errorf("orm: update %s requires at least one column", m.table))
 	}
@@ -79,7 +108,13 @@ func (m *modelCore) Update(ctx context.Context, filters any, data map[string]any
 	}
 	if len(updates) > 0 {
 		updates = m.normalizeColumns(updates)
+		m.checkUpdateTenant(ctx, updates)
+	}
+	updates = m.fillUpdateTimestamps(updates, time.Now())
+	if hooks {
+		updates = m.runBeforeUpdate(ctx, filters, updates)
 	}
+	updates = m.hashPasswords(updates)
 
 	setKeys := sortedKeys(updates)
 	args := make([]any, 0, len(setKeys))
@@ -100,7 +135,11 @@ func (m *modelCore) Update(ctx context.Context, filters any, data map[string]any
 		setBuilder.WriteString("version = version + 1")
 	}
 	filters = m.normalizeFilters(filters)
-	whereClause, whereArgs := buildWhereClauseWithQuoter(filters, quoter)
+	if isEmptyFilters(filters) {
+		panic(fmt.Errorf("orm: update %s requires filter conditions", m.table))
+	}
+	scoped := m.scopeFilters(ctx, filters, "")
+	whereClause, whereArgs := buildWhereClauseWithQuoter(scoped, quoter)
 	if strings.TrimSpace(whereClause) == "" {
 		panic(fmt.Errorf("orm: update %s requires filter conditions", m.table))
 	}
@@ -114,15 +153,53 @@ func (m *modelCore) Update(ctx context.Context, filters any, data map[string]any
 	if useOptimistic && affected == 0 {
 		panic(ErrVersionConflict)
 	}
+	if affected > 0 {
+		m.InvalidateCache(ctx)
+	}
+	if hooks {
+		m.runAfterUpdate(ctx, filters, updates, affected)
+	}
 	return affected
 }
 
-// Delete 根据条件删除数据。
+// Delete 根据条件删除数据；模型存在软删除字段时改为更新该字段。
 func (m *modelCore) Delete(ctx context.Context, filters any) int64 {
+	if m.audit {
+		_, soft := m.schema.softDeleteColumn()
+		return m.auditChange(ctx, auditDelete, filters, soft, func(ctx context.Context) int64 {
+			return m.deleteScoped(ctx, filters)
+		})
+	}
+	return m.hookTx(ctx, func(ctx context.Context) int64 {
+		return m.deleteScoped(ctx, filters)
+	})
+}
+
+func (m *modelCore) deleteScoped(ctx context.Context, filters any) int64 {
+	col, ok := m.schema.softDeleteColumn()
+	if !ok {
+		return m.deleteRows(ctx, filters)
+	}
+	if isEmptyFilters(m.normalizeFilters(filters)) {
+		panic(fmt.Errorf("orm: delete %s requires filter conditions", m.table))
+	}
+	ctx = normalizeContext(ctx)
+	m.runBeforeDelete(ctx, filters)
+	affected := m.updateRows(ctx, filters, map[string]any{col.Name: softDeleteValue(col)}, false, false)
+	m.runAfterDelete(ctx, filters, affected)
+	return affected
+}
+
+func (m *modelCore) deleteRows(ctx context.Context, filters any) int64 {
 	ctx, exec := m.executor(ctx)
 	quoter := m.identifierQuoter()
 	filters = m.normalizeFilters(filters)
-	whereClause, whereArgs := buildWhereClauseWithQuoter(filters, quoter)
+	if isEmptyFilters(filters) {
+		panic(fmt.Errorf("orm: delete %s requires filter conditions", m.table))
+	}
+	m.runBeforeDelete(ctx, filters)
+	scoped := m.scopeFilters(ctx, filters, "")
+	whereClause, whereArgs := buildWhereClauseWithQuoter(scoped, quoter)
 	if strings.TrimSpace(whereClause) == "" {
 		panic(fmt.Errorf("orm: delete %s requires filter conditions", m.table))
 	}
@@ -132,5 +209,9 @@ func (m *modelCore) Delete(ctx context.Context, filters any) int64 {
 	panicOnError(err)
 	affected, err := res.RowsAffected()
 	panicOnError(err)
+	if affected > 0 {
+		m.InvalidateCache(ctx)
+	}
+	m.runAfterDelete(ctx, filters, affected)
 	return affected
 }
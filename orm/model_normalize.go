@@ -17,10 +17,11 @@ var (
 )
 
 type fieldBinding struct {
-	index      int
+	index      []int
 	targetType reflect.Type
 	elemType   reflect.Type
 	isPointer  bool
+	json       bool
 }
 
 func normalizeMap(record map[string]any) {
@@ -78,7 +79,7 @@ func normalizeMapWithSchema(record map[string]any, schema *tableSchema) {
 		if !ok {
 			continue
 		}
-		normalized := normalizeValueByType(record[k], col.Type)
+		normalized := normalizeColumnValue(record[k], col)
 		record[k] = normalized
 		if lookupKey != k {
 			if _, exists := record[lookupKey]; !exists {
@@ -96,8 +97,8 @@ func (m *modelCore) normalizeColumns(data map[string]any) map[string]any {
 	m.schema.ensureLookup()
 	normalized := make(map[string]any, len(data))
 	for key, val := range data {
-		if col, ok := m.schema.resolveColumn(key); ok {
-			normalized[col] = val
+		if col, ok := m.schema.resolveColumnDef(key); ok {
+			normalized[col.Name] = encodeColumnValue(col, val)
 		} else {
 			normalized[key] = val
 		}
@@ -118,6 +119,9 @@ func (m *modelCore) normalizeFilters(filters any) any {
 			case "and", "or", "&&", "||":
 				result[key] = m.normalizeFilters(v)
 				continue
+			case "exists", "not exists":
+				result[key] = v
+				continue
 			}
 			newKey := key
 			if !strings.Contains(key, ".") {
@@ -173,7 +177,7 @@ func mapToStruct(record map[string]any, dest any) error {
 		if !ok {
 			continue
 		}
-		field := elem.Field(binding.index)
+		field := fieldByIndexAlloc(elem, binding.index)
 		if !field.CanSet() {
 			continue
 		}
@@ -184,18 +188,15 @@ func mapToStruct(record map[string]any, dest any) error {
 
 func buildFieldBindings(t reflect.Type) map[string]fieldBinding {
 	index := make(map[string]fieldBinding, t.NumField())
-	for i := 0; i < t.NumField(); i++ {
-		field := t.Field(i)
-		if field.PkgPath != "" {
-			continue
-		}
+	for _, field := range schemaFields(t) {
 		column := fieldColumnName(field)
 		if column == "" {
 			continue
 		}
 		binding := fieldBinding{
-			index:      i,
+			index:      field.Index,
 			targetType: field.Type,
+			json:       isJSONBinding(field),
 		}
 		if field.Type.Kind() == reflect.Pointer {
 			binding.isPointer = true
@@ -229,11 +230,29 @@ func resolveFieldBinding(fieldMap map[string]fieldBinding, key string) (fieldBin
 	return fieldBinding{}, false
 }
 
+// isJSONBinding 判断字段是否按 JSON 反序列化：自动推断的 JSON 字段或声明 type:json 的非字符串字段，关联字段除外。
+func isJSONBinding(field reflect.StructField) bool {
+	options := parseDormTag(field.Tag.Get("dorm"))
+	if tagExists(options, "relation") {
+		return false
+	}
+	if custom := util.FirstNonEmpty(options["type"]...); custom != "" {
+		return isJSONColumnType(custom) && field.Type.Kind() != reflect.String
+	}
+	return isJSONFieldType(field.Type)
+}
+
 func fieldColumnName(field reflect.StructField) string {
+	tagOptions := parseDormTag(field.Tag.Get("dorm"))
+	if name, ok := tagOptions["relation"]; ok {
+		// 关联字段不参与建表，按关联名绑定预加载结果。
+		if relation := util.FirstNonEmpty(name...); relation != "" {
+			return relation
+		}
+	}
 	if dbTag := strings.TrimSpace(field.Tag.Get("db")); dbTag != "" && dbTag != "-" {
 		return dbTag
 	}
-	tagOptions := parseDormTag(field.Tag.Get("dorm"))
 	if tagExists(tagOptions, "-") {
 		return ""
 	}
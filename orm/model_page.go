@@ -0,0 +1,251 @@
+package orm
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// 分页查询：Paginate 使用 LIMIT/OFFSET 并返回总数，KeysetPaginate 使用游标避免深分页的 OFFSET 扫描。
+
+const defaultPageSize = 20
+
+// ErrInvalidCursor 表示分页游标无法解析或与当前排序不匹配。
+var ErrInvalidCursor = errors.New("orm: invalid page cursor")
+
+// PageRequest 描述分页参数，Order 为空时使用模型默认排序。
+type PageRequest struct {
+	Page   int
+	Size   int
+	Order  string
+	Cursor string
+}
+
+// Page 是分页结果。KeysetPaginate 不统计总数，Total/Pages 为 0，通过 HasMore/NextCursor 翻页。
+type Page[T any] struct {
+	Items      []T    `json:"items"`
+	Total      int64  `json:"total"`
+	Page       int    `json:"page"`
+	Size       int    `json:"size"`
+	Pages      int    `json:"pages"`
+	HasMore    bool   `json:"hasMore"`
+	NextCursor string `json:"nextCursor,omitempty"`
+}
+
+// Paginate 按页码分页查询，options 支持 field/join 等 Select 选项，总数统计复用同一组过滤条件与 join。
+func (m *Model[T]) Paginate(ctx context.Context, filters any, req PageRequest, options ...map[string]any) Page[*T] {
+	result := m.PaginateMap(ctx, filters, req, options...)
+	return m.convertPage(ctx, result)
+}
+
+// PaginateMap 按页码分页查询并返回 map 结果。
+func (m *Model[T]) PaginateMap(ctx context.Context, filters any, req PageRequest, options ...map[string]any) Page[map[string]any] {
+	options = m.withOptions(options)
+	req = normalizePageRequest(req)
+	opt := pageOptions(options, req)
+	opt["page"] = req.Page
+	opt["pageSize"] = req.Size
+
+	var countOpt map[string]any
+	if joinRaw, ok := opt["join"]; ok {
+		countOpt = map[string]any{"join": joinRaw}
+	}
+	total := m.modelCore.Count(ctx, filters, countOpt)
+	items := []map[string]any{}
+	if total > int64((req.Page-1)*req.Size) {
+		items = m.SelectMap(ctx, filters, opt)
+	}
+	pages := int(math.Ceil(float64(total) / float64(req.Size)))
+	return Page[map[string]any]{
+		Items:   items,
+		Total:   total,
+		Page:    req.Page,
+		Size:    req.Size,
+		Pages:   pages,
+		HasMore: req.Page < pages,
+	}
+}
+
+// KeysetPaginate 按游标分页查询：根据上一页最后一行的排序字段值定位下一页，不使用 OFFSET。
+// 排序字段会自动追加主键保证顺序稳定，排序字段应为非空列；游标与排序不匹配时 panic ErrInvalidCursor。
+func (m *Model[T]) KeysetPaginate(ctx context.Context, filters any, req PageRequest, options ...map[string]any) Page[*T] {
+	result := m.KeysetPaginateMap(ctx, filters, req, options...)
+	return m.convertPage(ctx, result)
+}
+
+// KeysetPaginateMap 按游标分页查询并返回 map 结果。
+func (m *Model[T]) KeysetPaginateMap(ctx context.Context, filters any, req PageRequest, options ...map[string]any) Page[map[string]any] {
+	options = m.withOptions(options)
+	req = normalizePageRequest(req)
+	opt := pageOptions(options, req)
+	keys := m.keysetOrder(req.Order)
+	opt["order"] = keysetOrderClause(keys)
+	opt["limit"] = req.Size + 1
+
+	if strings.TrimSpace(req.Cursor) != "" {
+		values := decodePageCursor(req.Cursor, keys)
+		filters = mergeFilters(filters, keysetCondition(keys, values))
+	}
+
+	items := m.SelectMap(ctx, filters, opt)
+	result := Page[map[string]any]{Items: items, Size: req.Size}
+	if len(items) > req.Size {
+		result.Items = items[:req.Size]
+		result.HasMore = true
+		result.NextCursor = encodePageCursor(keys, result.Items[req.Size-1])
+	}
+	return result
+}
+
+type keysetColumn struct {
+	column string
+	desc   bool
+}
+
+func normalizePageRequest(req PageRequest) PageRequest {
+	if req.Page <= 0 {
+		req.Page = 1
+	}
+	if req.Size <= 0 {
+		req.Size = defaultPageSize
+	}
+	req.Order = strings.TrimSpace(req.Order)
+	return req
+}
+
+func pageOptions(options []map[string]any, req PageRequest) map[string]any {
+	opt := map[string]any{}
+	if len(options) > 0 && options[0] != nil {
+		opt = cloneAnyMap(options[0])
+	}
+	delete(opt, "limit")
+	delete(opt, "page")
+	delete(opt, "pageSize")
+	delete(opt, "into")
+	if req.Order != "" {
+		opt["order"] = req.Order
+	}
+	return opt
+}
+
+func (m *modelCore) keysetOrder(order string) []keysetColumn {
+	clause := safeOrderClause(order, "")
+	if clause == "" {
+		clause = safeOrderClause(m.defaultOrder, "")
+	}
+	var keys []keysetColumn
+	hasPrimary := false
+	for _, item := range strings.Split(clause, ",") {
+		fields := strings.Fields(item)
+		if len(fields) == 0 {
+			continue
+		}
+		column := fields[0]
+		if !strings.Contains(column, ".") {
+			column = "main." + column
+		}
+		if strings.EqualFold(column, "main."+m.primaryKey) {
+			hasPrimary = true
+		}
+		keys = append(keys, keysetColumn{column: column, desc: len(fields) > 1 && fields[1] == "desc"})
+	}
+	if !hasPrimary {
+		desc := len(keys) > 0 && keys[len(keys)-1].desc
+		keys = append(keys, keysetColumn{column: "main." + m.primaryKey, desc: desc})
+	}
+	return keys
+}
+
+func keysetOrderClause(keys []keysetColumn) string {
+	parts := make([]string, 0, len(keys))
+	for _, key := range keys {
+		direction := "asc"
+		if key.desc {
+			direction = "desc"
+		}
+		parts = append(parts, key.column+" "+direction)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// keysetCondition 生成 (a > ?) OR (a = ? AND b > ?) ... 形式的条件，兼容混合排序方向。
+func keysetCondition(keys []keysetColumn, values []any) map[string]any {
+	branches := make([]any, 0, len(keys))
+	for i, key := range keys {
+		branch := make(map[string]any, i+1)
+		for j := 0; j < i; j++ {
+			branch[keys[j].column] = values[j]
+		}
+		op := ">"
+		if key.desc {
+			op = "<"
+		}
+		branch[key.column] = map[string]any{op: values[i]}
+		branches = append(branches, map[string]any{"and": branch})
+	}
+	return map[string]any{"or": branches}
+}
+
+type pageCursor struct {
+	Order  string `json:"o"`
+	Values []any  `json:"v"`
+}
+
+func encodePageCursor(keys []keysetColumn, row map[string]any) string {
+	values := make([]any, 0, len(keys))
+	for _, key := range keys {
+		column := key.column[strings.LastIndex(key.column, ".")+1:]
+		values = append(values, lookupRelationValue(row, column))
+	}
+	data, err := json.Marshal(pageCursor{Order: keysetOrderClause(keys), Values: values})
+	panicOnError(err)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodePageCursor(token string, keys []keysetColumn) []any {
+	data, err := base64.RawURLEncoding.DecodeString(strings.TrimSpace(token))
+	if err != nil {
+		panic(fmt.Errorf("%w: %w", ErrInvalidCursor, err))
+	}
+	decoder := json.NewDecoder(strings.NewReader(string(data)))
+	decoder.UseNumber()
+	var cursor pageCursor
+	if err := decoder.Decode(&cursor); err != nil {
+		panic(fmt.Errorf("%w: %w", ErrInvalidCursor, err))
+	}
+	if cursor.Order != keysetOrderClause(keys) || len(cursor.Values) != len(keys) {
+		panic(ErrInvalidCursor)
+	}
+	for i, value := range cursor.Values {
+		number, ok := value.(json.Number)
+		if !ok {
+			continue
+		}
+		if v, err := number.Int64(); err == nil {
+			cursor.Values[i] = v
+		} else if v, err := number.Float64(); err == nil {
+			cursor.Values[i] = v
+		}
+	}
+	return cursor.Values
+}
+
+func (m *Model[T]) convertPage(ctx context.Context, source Page[map[string]any]) Page[*T] {
+	items := make([]*T, 0, len(source.Items))
+	for _, record := range source.Items {
+		items = append(items, m.hydrate(ctx, record))
+	}
+	return Page[*T]{
+		Items:      items,
+		Total:      source.Total,
+		Page:       source.Page,
+		Size:       source.Size,
+		Pages:      source.Pages,
+		HasMore:    source.HasMore,
+		NextCursor: source.NextCursor,
+	}
+}
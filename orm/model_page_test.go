@@ -0,0 +1,74 @@
+package orm_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/shemic/dever/orm"
+	"github.com/shemic/dever/orm/ormtest"
+)
+
+type pageEntry struct {
+	ID    uint64 `dorm:"primaryKey;autoIncrement"`
+	Score int
+}
+
+var pageEntries = ormtest.Register[pageEntry]("page_entry", orm.ModelConfig{})
+
+func TestKeysetPaginateWalksAllRows(t *testing.T) {
+	ctx := ormtest.Setup(t)
+	// 分数有重复，翻页依赖自动追加的主键（方向与最后一个排序字段一致）保持顺序稳定。
+	rows := make([]map[string]any, 0, 7)
+	for _, score := range []int{5, 3, 5, 1, 3, 5, 2} {
+		rows = append(rows, map[string]any{"score": score})
+	}
+	pageEntries().InsertMany(ctx, rows, 0)
+
+	var got []string
+	req := orm.PageRequest{Size: 3, Order: "score desc"}
+	for pages := 0; ; pages++ {
+		if pages > 3 {
+			t.Fatalf("too many pages: %v", got)
+		}
+		page := pageEntries().KeysetPaginate(ctx, nil, req)
+		for _, item := range page.Items {
+			got = append(got, fmt.Sprintf("%d/%d", item.Score, item.ID))
+		}
+		if !page.HasMore {
+			break
+		}
+		req.Cursor = page.NextCursor
+	}
+	want := "[5/6 5/3 5/1 3/5 3/2 2/7 1/4]"
+	if fmt.Sprint(got) != want {
+		t.Fatalf("pages = %v, want %s", got, want)
+	}
+
+	req = orm.PageRequest{Size: 3, Order: "score asc", Cursor: "not-a-cursor"}
+	if _, err := pageEntries().E().KeysetPaginate(ctx, nil, req); !errors.Is(err, orm.ErrInvalidCursor) {
+		t.Fatalf("bad cursor err = %v, want ErrInvalidCursor", err)
+	}
+}
+
+func TestPaginateCountsTotal(t *testing.T) {
+	ctx := ormtest.Setup(t)
+	rows := make([]map[string]any, 0, 7)
+	for score := 1; score <= 7; score++ {
+		rows = append(rows, map[string]any{"score": score})
+	}
+	pageEntries().InsertMany(ctx, rows, 0)
+
+	page := pageEntries().Paginate(ctx, map[string]any{"score": map[string]any{">": 1}}, orm.PageRequest{Page: 2, Size: 4, Order: "score asc"})
+	if page.Total != 6 || page.Pages != 2 || page.Page != 2 || page.HasMore {
+		t.Fatalf("page = %+v, want total 6, 2 pages, last page", page)
+	}
+	if len(page.Items) != 2 || page.Items[0].Score != 6 || page.Items[1].Score != 7 {
+		t.Fatalf("items = %+v, want scores 6,7", page.Items)
+	}
+
+	first := pageEntries().PaginateMap(ctx, nil, orm.PageRequest{Page: 1, Size: 4, Order: "score desc"})
+	if !first.HasMore || len(first.Items) != 4 || first.Items[0]["score"] != int64(7) {
+		t.Fatalf("first page = %+v", first)
+	}
+}
@@ -15,25 +15,26 @@ func (m *modelCore) selectMaps(ctx context.Context, filters any, options map[str
 }
 
 func (m *modelCore) selectMapsWithOptions(ctx context.Context, filters any, options map[string]any, normalizeKeys bool, lock ...bool) []map[string]any {
-	if filters == nil {
-		filters = map[string]any{}
-	}
 	lockFlag := false
 	if len(lock) > 0 {
 		lockFlag = lock[0]
 	}
-	ctx, exec := m.executor(ctx)
-	resolved := resolveSelectOptions(options, m.defaultOrder, true)
-	query, args := m.buildSelectQuery(filters, selectQueryConfig{
-		fields:           resolved.fields,
-		joinRaw:          resolved.joinRaw,
-		order:            resolved.order,
-		applyOrder:       true,
-		limitOptions:     options,
-		lock:             lockFlag,
-		normalizeFilters: true,
-	})
+	if _, into := options["into"]; !lockFlag && !into {
+		if key, ok := m.cacheKey(ctx, "select", filters, options, normalizeKeys); ok {
+			return cachedQuery(ctx, m, key, func() []map[string]any {
+				return m.queryMaps(ctx, filters, options, normalizeKeys, false)
+			}, cloneCachedRows)
+		}
+	}
+	return m.queryMaps(ctx, filters, options, normalizeKeys, lockFlag)
+}
 
+func (m *modelCore) queryMaps(ctx context.Context, filters any, options map[string]any, normalizeKeys, lockFlag bool) []map[string]any {
+	ctx, exec := m.readExecutor(ctx, lockFlag)
+	query, args, resolved := m.prepareSelect(ctx, filters, options, lockFlag)
+	if resolved.with != nil {
+		panicOnError(m.ValidateRelations(resolved.with))
+	}
 	query = exec.rebind(query)
 	if resolved.into != nil {
 		panicOnError(ensureIntoDest(resolved.into))
@@ -60,6 +61,9 @@ func (m *modelCore) selectMapsWithOptions(ctx context.Context, filters any, opti
 	if result == nil {
 		return []map[string]any{}
 	}
+	if resolved.with != nil {
+		m.loadRelations(ctx, exec, result, resolved.with)
+	}
 	return result
 }
 
@@ -98,12 +102,25 @@ func (m *modelCore) Sum(ctx context.Context, column string, filters any, options
 }
 
 func (m *modelCore) findMap(ctx context.Context, filters any, options ...map[string]any) map[string]any {
-	ctx, exec := m.executor(ctx)
 	var opt map[string]any
 	if len(options) > 0 && options[0] != nil {
 		opt = options[0]
 	}
+	if key, ok := m.cacheKey(ctx, "find", filters, opt); ok {
+		return cachedQuery(ctx, m, key, func() map[string]any {
+			return m.queryRow(ctx, filters, opt)
+		}, cloneCachedRow)
+	}
+	return m.queryRow(ctx, filters, opt)
+}
+
+func (m *modelCore) queryRow(ctx context.Context, filters any, opt map[string]any) map[string]any {
+	ctx, exec := m.readExecutor(ctx, false)
 	resolved := resolveSelectOptions(opt, m.defaultOrder, true)
+	if resolved.with != nil {
+		panicOnError(m.ValidateRelations(resolved.with))
+	}
+	filters = m.scopeFilters(ctx, filters, "main.")
 	query, args := m.buildSelectQuery(filters, selectQueryConfig{
 		fields:     resolved.fields,
 		joinRaw:    resolved.joinRaw,
@@ -123,30 +140,33 @@ func (m *modelCore) findMap(ctx context.Context, filters any, options ...map[str
 		panic(err)
 	}
 	normalizeMapWithSchema(record, m.schema)
+	if resolved.with != nil {
+		m.loadRelations(ctx, exec, []map[string]any{record}, resolved.with)
+	}
 	return record
 }
 
 // Select 查询多条记录。
 func (m *Model[T]) Select(ctx context.Context, filters any, options ...map[string]any) []*T {
+	options = m.withOptions(options)
 	var opt map[string]any
 	if len(options) > 0 {
 		opt = options[0]
 	}
-	records := m.modelCore.selectMaps(ctx, filters, opt)
+	records := m.hideFields(m.modelCore.selectMaps(ctx, filters, opt), opt)
 	if len(records) == 0 {
 		return []*T{}
 	}
 	result := make([]*T, 0, len(records))
 	for _, record := range records {
-		dest := new(T)
-		_ = mapToStruct(record, dest)
-		result = append(result, dest)
+		result = append(result, m.hydrate(ctx, record))
 	}
 	return result
 }
 
 // SelectMap 查询多条记录并返回 map 结果。
 func (m *Model[T]) SelectMap(ctx context.Context, filters any, options ...map[string]any) []map[string]any {
+	options = m.withOptions(options)
 	var opt map[string]any
 	if len(options) > 0 {
 		opt = options[0]
@@ -157,23 +177,23 @@ func (m *Model[T]) SelectMap(ctx context.Context, filters any, options ...map[st
 			normalizeKeys = false
 		}
 	}
-	return m.modelCore.selectMapsWithOptions(ctx, filters, opt, normalizeKeys)
+	return m.hideFields(m.modelCore.selectMapsWithOptions(ctx, filters, opt, normalizeKeys), opt)
 }
 
 // Find 查询单条记录。
 func (m *Model[T]) Find(ctx context.Context, filters any, options ...map[string]any) *T {
-	record := m.modelCore.findMap(ctx, filters, options...)
+	options = m.withOptions(options)
+	record := m.hideRecord(m.modelCore.findMap(ctx, filters, options...), firstOption(options))
 	if len(record) == 0 {
 		return nil
 	}
-	dest := new(T)
-	_ = mapToStruct(record, dest)
-	return dest
+	return m.hydrate(ctx, record)
 }
 
 // FindMap 查询单条记录并返回 map 结果。
 func (m *Model[T]) FindMap(ctx context.Context, filters any, options ...map[string]any) map[string]any {
-	record := m.modelCore.findMap(ctx, filters, options...)
+	options = m.withOptions(options)
+	record := m.hideRecord(m.modelCore.findMap(ctx, filters, options...), firstOption(options))
 	if len(record) == 0 {
 		return map[string]any{}
 	}
@@ -187,7 +207,16 @@ func (m *Model[T]) FindMap(ctx context.Context, filters any, options ...map[stri
 }
 
 func (m *modelCore) aggregateInt(ctx context.Context, expr string, filters any, options map[string]any) int64 {
-	row := m.aggregateRow(ctx, expr, filters, options)
+	if key, ok := m.cacheKey(ctx, "aggregate", expr, filters, options); ok {
+		return cachedQuery(ctx, m, key, func() int64 {
+			return m.queryAggregateInt(ctx, expr, filters, options)
+		}, cloneCachedScalar[int64])
+	}
+	return m.queryAggregateInt(ctx, expr, filters, options)
+}
+
+func (m *modelCore) queryAggregateInt(ctx context.Context, expr string, filters any, options map[string]any) int64 {
+	row := m.aggregateRow(ctx, wrapAggregateExpression(expr), filters, options)
 	var result sql.NullInt64
 	if err := row.Scan(&result); err != nil {
 		err = normalizeError(err)
@@ -203,7 +232,16 @@ func (m *modelCore) aggregateInt(ctx context.Context, expr string, filters any,
 }
 
 func (m *modelCore) aggregateFloat(ctx context.Context, expr string, filters any, options map[string]any) float64 {
-	row := m.aggregateRow(ctx, expr, filters, options)
+	if key, ok := m.cacheKey(ctx, "aggregate_float", expr, filters, options); ok {
+		return cachedQuery(ctx, m, key, func() float64 {
+			return m.queryAggregateFloat(ctx, expr, filters, options)
+		}, cloneCachedScalar[float64])
+	}
+	return m.queryAggregateFloat(ctx, expr, filters, options)
+}
+
+func (m *modelCore) queryAggregateFloat(ctx context.Context, expr string, filters any, options map[string]any) float64 {
+	row := m.aggregateRow(ctx, wrapAggregateExpression(expr), filters, options)
 	var result sql.NullFloat64
 	if err := row.Scan(&result); err != nil {
 		err = normalizeError(err)
@@ -225,7 +263,8 @@ func (m *modelCore) aggregateRow(ctx context.Context, expr string, filters any,
 	if filters != nil {
 		filters = m.normalizeFilters(filters)
 	}
-	ctx, exec := m.executor(ctx)
+	filters = m.scopeFilters(ctx, filters, "main.")
+	ctx, exec := m.readExecutor(ctx, false)
 	quoter := m.identifierQuoter()
 
 	joinClause := ""
@@ -235,7 +274,7 @@ func (m *modelCore) aggregateRow(ctx context.Context, expr string, filters any,
 		}
 	}
 
-	query := fmt.Sprintf("SELECT %s FROM %s AS main", wrapAggregateExpression(expr), m.quotedTableName())
+	query := fmt.Sprintf("SELECT %s FROM %s AS main", expr, m.quotedTableName())
 	if joinClause != "" {
 		query += " " + joinClause
 	}
@@ -247,11 +286,30 @@ func (m *modelCore) aggregateRow(ctx context.Context, expr string, filters any,
 	return exec.queryRowxContext(ctx, query, args...)
 }
 
+func (m *modelCore) prepareSelect(ctx context.Context, filters any, options map[string]any, lock bool) (string, []any, resolvedSelectOptions) {
+	if filters == nil {
+		filters = map[string]any{}
+	}
+	filters = m.scopeFilters(ctx, filters, "main.")
+	resolved := resolveSelectOptions(options, m.defaultOrder, true)
+	query, args := m.buildSelectQuery(filters, selectQueryConfig{
+		fields:           resolved.fields,
+		joinRaw:          resolved.joinRaw,
+		order:            resolved.order,
+		applyOrder:       true,
+		limitOptions:     options,
+		lock:             lock,
+		normalizeFilters: true,
+	})
+	return query, args, resolved
+}
+
 type resolvedSelectOptions struct {
 	fields  string
 	joinRaw any
 	order   string
 	into    any
+	with    any
 }
 
 func resolveSelectOptions(options map[string]any, defaultOrder string, allowOrder bool) resolvedSelectOptions {
@@ -276,12 +334,17 @@ func resolveSelectOptions(options map[string]any, defaultOrder string, allowOrde
 	if dest, ok := options["into"]; ok {
 		resolved.into = dest
 	}
+	if with, ok := options["with"]; ok {
+		resolved.with = with
+	}
 	return resolved
 }
 
 type selectQueryConfig struct {
+	alias            string
 	fields           string
 	joinRaw          any
+	joinAlias        string
 	order            string
 	applyOrder       bool
 	limitOne         bool
@@ -291,17 +354,20 @@ type selectQueryConfig struct {
 }
 
 func (m *modelCore) buildSelectQuery(filters any, cfg selectQueryConfig) (string, []any) {
+	if cfg.alias == "" {
+		cfg.alias = "main"
+	}
 	if cfg.fields == "" {
-		cfg.fields = "main.*"
+		cfg.fields = cfg.alias + ".*"
 	}
 	if cfg.normalizeFilters && filters != nil {
 		filters = m.normalizeFilters(filters)
 	}
 	quoter := m.identifierQuoter()
 	tableName := m.quotedTableName()
-	query := fmt.Sprintf("SELECT %s FROM %s AS main", cfg.fields, tableName)
+	query := fmt.Sprintf("SELECT %s FROM %s AS %s", cfg.fields, tableName, cfg.alias)
 	if cfg.joinRaw != nil {
-		joinClause := buildJoinClause(cfg.joinRaw)
+		joinClause := buildJoinClauseWithAlias(cfg.joinRaw, cfg.joinAlias)
 		if joinClause != "" {
 			query += " " + joinClause
 		}
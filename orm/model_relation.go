@@ -0,0 +1,309 @@
+package orm
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/shemic/dever/util"
+)
+
+// 关联预加载：根据 ModelConfig.Relations 批量查询并挂载关联数据，避免 N+1 查询。
+//
+// Relation 字段约定：
+//   - Kind: hasOne / hasMany / manyToMany（设置 Through 时默认 manyToMany，否则默认 hasMany）
+//   - Name: 关联名，结果挂载到同名 key（为空时使用 Field）
+//   - Table: 关联表名（自动追加库前缀）
+//   - Through: 多对多中间表
+//   - RowKey: 当前表用于匹配的字段，默认主键
+//   - OwnerField: 关联表（多对多时为中间表）中指向当前表的字段
+//   - TargetField: 中间表中指向关联表的字段
+//   - TableKey: 关联表被 TargetField 引用的字段，默认 id
+//
+// 未定义的关联名返回 ErrUnknownRelation，在执行主查询前检查。
+//   - Order / ThroughOrder: 关联表、中间表的排序
+//   - EmptyValue: hasOne 未命中时的占位值
+
+const relationBatchSize = 500
+
+const (
+	RelationHasOne     = "hasOne"
+	RelationHasMany    = "hasMany"
+	RelationManyToMany = "manyToMany"
+)
+
+// With 返回预加载指定关联的模型视图，等价于查询选项 {"with": relations}。
+func (m *Model[T]) With(relations ...string) *Model[T] {
+	names := append(append([]string(nil), m.with...), relations...)
+	return &Model[T]{modelCore: m.modelCore, with: names}
+}
+
+func (m *Model[T]) withOptions(options []map[string]any) []map[string]any {
+	if len(m.with) == 0 {
+		return options
+	}
+	var opt map[string]any
+	if len(options) > 0 && options[0] != nil {
+		opt = cloneAnyMap(options[0])
+	} else {
+		opt = map[string]any{}
+	}
+	names := append([]string(nil), m.with...)
+	if raw, ok := opt["with"]; ok {
+		names = append(names, parseRelationNames(raw)...)
+	}
+	opt["with"] = names
+	return []map[string]any{opt}
+}
+
+func parseRelationNames(raw any) []string {
+	var names []string
+	switch value := raw.(type) {
+	case nil:
+		return nil
+	case string:
+		names = strings.Split(value, ",")
+	case []string:
+		names = value
+	case []any:
+		for _, item := range value {
+			names = append(names, util.ToStringTrimmed(item))
+		}
+	default:
+		names = strings.Split(util.ToStringTrimmed(value), ",")
+	}
+	result := make([]string, 0, len(names))
+	seen := map[string]struct{}{}
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if _, ok := seen[name]; ok {
+			continue
+		}
+		seen[name] = struct{}{}
+		result = append(result, name)
+	}
+	return result
+}
+
+func (m *modelCore) relation(name string) (Relation, bool) {
+	for _, relation := range m.config.Relations {
+		if relationName(relation) == name {
+			return relation, true
+		}
+	}
+	return Relation{}, false
+}
+
+func relationName(relation Relation) string {
+	return util.FirstNonEmpty(strings.TrimSpace(relation.Name), strings.TrimSpace(relation.Field))
+}
+
+func relationKind(relation Relation) string {
+	switch strings.ToLower(strings.TrimSpace(relation.Kind)) {
+	case "hasone", "one":
+		return RelationHasOne
+	case "hasmany", "many":
+		return RelationHasMany
+	case "manytomany", "belongstomany", "through":
+		return RelationManyToMany
+	case "":
+		if strings.TrimSpace(relation.Through) != "" {
+			return RelationManyToMany
+		}
+		return RelationHasMany
+	default:
+		return ""
+	}
+}
+
+func (m *modelCore) loadRelations(ctx context.Context, exec executor, records []map[string]any, raw any) {
+	names := parseRelationNames(raw)
+	if len(names) == 0 || len(records) == 0 {
+		return
+	}
+	panicOnError(m.ValidateRelations(names))
+	for _, name := range names {
+		relation, _ := m.relation(name)
+		m.loadRelation(ctx, exec, records, name, relation)
+	}
+}
+
+// ValidateRelations 检查 with 选项（逗号分隔字符串或字符串切片）中的关联名都已在 ModelConfig.Relations 中定义，
+// 未定义时返回 ErrUnknownRelation，适合在查询前校验来自请求参数的 with。
+func (m *modelCore) ValidateRelations(raw any) error {
+	for _, name := range parseRelationNames(raw) {
+		if _, ok := m.relation(name); !ok {
+			return fmt.Errorf("%w: %q on %s", ErrUnknownRelation, name, m.name)
+		}
+	}
+	return nil
+}
+
+func (m *modelCore) loadRelation(ctx context.Context, exec executor, records []map[string]any, name string, relation Relation) {
+	kind := relationKind(relation)
+	if kind == "" {
+		panic(fmt.Errorf("orm: relation %q has unsupported kind %q", name, relation.Kind))
+	}
+	target := strings.TrimSpace(relation.Table)
+	ownerField := strings.TrimSpace(relation.OwnerField)
+	if target == "" || ownerField == "" {
+		panic(fmt.Errorf("orm: relation %q requires Table and OwnerField", name))
+	}
+	rowKey := util.FirstNonEmpty(strings.TrimSpace(relation.RowKey), m.primaryKey)
+	keys := collectRelationKeys(records, rowKey)
+
+	var grouped map[string][]map[string]any
+	if kind == RelationManyToMany {
+		grouped = m.loadThroughRelation(ctx, exec, name, relation, keys)
+	} else {
+		rows := m.selectRelationRows(ctx, exec, target, ownerField, keys, relation.Order)
+		grouped = groupRelationRows(rows, ownerField)
+	}
+
+	for _, record := range records {
+		matched := grouped[util.ToKeyString(lookupRelationValue(record, rowKey))]
+		if kind == RelationHasOne {
+			if len(matched) > 0 {
+				record[name] = matched[0]
+			} else {
+				record[name] = relation.EmptyValue
+			}
+			continue
+		}
+		if matched == nil {
+			matched = []map[string]any{}
+		}
+		record[name] = matched
+	}
+}
+
+func (m *modelCore) loadThroughRelation(ctx context.Context, exec executor, name string, relation Relation, keys []any) map[string][]map[string]any {
+	through := strings.TrimSpace(relation.Through)
+	targetField := strings.TrimSpace(relation.TargetField)
+	if targetField == "" {
+		panic(fmt.Errorf("orm: relation %q requires TargetField", name))
+	}
+	ownerField := strings.TrimSpace(relation.OwnerField)
+	valueField := util.FirstNonEmpty(strings.TrimSpace(relation.TableKey), "id")
+
+	pivots := m.selectRelationRows(ctx, exec, through, ownerField, keys, relation.ThroughOrder)
+	targetKeys := collectRelationKeys(pivots, targetField)
+	targets := m.selectRelationRows(ctx, exec, relation.Table, valueField, targetKeys, relation.Order)
+	targetIndex := make(map[string]map[string]any, len(targets))
+	for _, row := range targets {
+		targetIndex[util.ToKeyString(lookupRelationValue(row, valueField))] = row
+	}
+
+	grouped := map[string][]map[string]any{}
+	if strings.TrimSpace(relation.ThroughOrder) != "" || strings.TrimSpace(relation.Order) == "" {
+		// 中间表排序优先：按中间表顺序挂载关联记录。
+		for _, pivot := range pivots {
+			row, ok := targetIndex[util.ToKeyString(lookupRelationValue(pivot, targetField))]
+			if !ok {
+				continue
+			}
+			owner := util.ToKeyString(lookupRelationValue(pivot, ownerField))
+			grouped[owner] = append(grouped[owner], row)
+		}
+		return grouped
+	}
+
+	owners := map[string][]string{}
+	for _, pivot := range pivots {
+		targetKey := util.ToKeyString(lookupRelationValue(pivot, targetField))
+		owners[targetKey] = append(owners[targetKey], util.ToKeyString(lookupRelationValue(pivot, ownerField)))
+	}
+	for _, row := range targets {
+		for _, owner := range owners[util.ToKeyString(lookupRelationValue(row, valueField))] {
+			grouped[owner] = append(grouped[owner], row)
+		}
+	}
+	return grouped
+}
+
+func (m *modelCore) selectRelationRows(ctx context.Context, exec executor, table, column string, keys []any, order string) []map[string]any {
+	if len(keys) == 0 {
+		return nil
+	}
+	table = applyTablePrefix(strings.TrimSpace(table), m.dbName)
+	panicOnError(ensureIdentifier(table))
+	panicOnError(ensureIdentifier(column))
+
+	schema, _ := getRegisteredSchema(table)
+	quoter := m.identifierQuoter()
+	orderClause := safeOrderClause(order, "")
+	var result []map[string]any
+	for start := 0; start < len(keys); start += relationBatchSize {
+		end := min(start+relationBatchSize, len(keys))
+		query := fmt.Sprintf("SELECT main.* FROM %s AS main", quoteIdentifier(m.driverName, table))
+		filters := scopeSchemaFilters(ctx, schema, map[string]any{"main." + column: keys[start:end]}, "main.")
+		whereClause, args := buildWhereClauseWithQuoter(filters, quoter)
+		query += " WHERE " + whereClause
+		if orderClause != "" {
+			query += " ORDER BY " + orderClause
+		}
+		rows, err := exec.queryxContext(ctx, exec.rebind(query), args...)
+		panicOnError(err)
+		for rows.Next() {
+			record := make(map[string]any)
+			if err := rows.MapScan(record); err != nil {
+				rows.Close()
+				panic(err)
+			}
+			if schema != nil {
+				normalizeMapWithSchema(record, schema)
+			} else {
+				normalizeMap(record)
+			}
+			result = append(result, record)
+		}
+		err = rows.Err()
+		rows.Close()
+		panicOnError(err)
+	}
+	hideTableSecrets(m.dbName, table, result)
+	return result
+}
+
+func collectRelationKeys(records []map[string]any, column string) []any {
+	keys := make([]any, 0, len(records))
+	seen := make(map[string]struct{}, len(records))
+	for _, record := range records {
+		value := lookupRelationValue(record, column)
+		key := util.ToKeyString(value)
+		if key == "" {
+			continue
+		}
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+		keys = append(keys, value)
+	}
+	return keys
+}
+
+func groupRelationRows(rows []map[string]any, column string) map[string][]map[string]any {
+	grouped := make(map[string][]map[string]any, len(rows))
+	for _, row := range rows {
+		key := util.ToKeyString(lookupRelationValue(row, column))
+		grouped[key] = append(grouped[key], row)
+	}
+	return grouped
+}
+
+func lookupRelationValue(record map[string]any, column string) any {
+	if value, ok := record[column]; ok {
+		return value
+	}
+	normalized := normalizeColumnKey(column)
+	for key, value := range record {
+		if normalizeColumnKey(key) == normalized {
+			return value
+		}
+	}
+	return nil
+}
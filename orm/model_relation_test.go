@@ -0,0 +1,142 @@
+package orm_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/shemic/dever/orm"
+	"github.com/shemic/dever/orm/ormtest"
+)
+
+type relUser struct {
+	ID   uint64 `dorm:"primaryKey;autoIncrement"`
+	Name string `dorm:"type:varchar(32)"`
+}
+
+type relPost struct {
+	ID        uint64 `dorm:"primaryKey;autoIncrement"`
+	RelUserID uint64
+	Title     string `dorm:"type:varchar(32)"`
+	Secret    string `dorm:"type:varchar(32)" json:"-"`
+}
+
+type relRole struct {
+	ID   uint64 `dorm:"primaryKey;autoIncrement"`
+	Name string `dorm:"type:varchar(32)"`
+}
+
+type relUserRole struct {
+	ID        uint64 `dorm:"primaryKey;autoIncrement"`
+	RelUserID uint64
+	RelRoleID uint64
+}
+
+var (
+	relUsers = ormtest.Register[relUser]("rel_user", orm.ModelConfig{
+		Relations: []orm.Relation{
+			{Kind: orm.RelationHasMany, Name: "posts", Table: "rel_post", OwnerField: "rel_user_id", Order: "main.id asc"},
+			{Kind: orm.RelationHasOne, Name: "first", Table: "rel_post", OwnerField: "rel_user_id", EmptyValue: "none"},
+			{Kind: orm.RelationManyToMany, Name: "roles", Table: "rel_role", Through: "rel_user_role",
+				OwnerField: "rel_user_id", TargetField: "rel_role_id", ThroughOrder: "main.id asc"},
+		},
+	})
+	relPosts = ormtest.Register[relPost]("rel_post", orm.ModelConfig{
+		Fields: map[string]orm.FieldConfig{"secret": {Type: orm.FieldTypeHidden}},
+	})
+	relRoles     = ormtest.Register[relRole]("rel_role", orm.ModelConfig{})
+	relUserRoles = ormtest.Register[relUserRole]("rel_user_role", orm.ModelConfig{})
+)
+
+func TestWithLoadsEachRelationInOneQuery(t *testing.T) {
+	ctx := ormtest.Setup(t)
+	alice := relUsers().Insert(ctx, map[string]any{"name": "alice"})
+	bob := relUsers().Insert(ctx, map[string]any{"name": "bob"})
+	relPosts().InsertMany(ctx, []map[string]any{
+		{"rel_user_id": alice, "title": "a1", "secret": "s"},
+		{"rel_user_id": alice, "title": "a2", "secret": "s"},
+	}, 0)
+	admin := relRoles().Insert(ctx, map[string]any{"name": "admin"})
+	editor := relRoles().Insert(ctx, map[string]any{"name": "editor"})
+	relUserRoles().InsertMany(ctx, []map[string]any{
+		{"rel_user_id": alice, "rel_role_id": editor},
+		{"rel_user_id": alice, "rel_role_id": admin},
+		{"rel_user_id": bob, "rel_role_id": admin},
+	}, 0)
+
+	var rows []map[string]any
+	statements := recordQueries(t, func() {
+		rows = relUsers().With("posts", "roles", "first").SelectMap(ctx, nil, map[string]any{"order": "main.id asc"})
+	})
+	if got := countQueries(statements, `FROM "rel_post"`); got != 2 {
+		t.Fatalf("post queries = %d, want 2 (posts, first): %q", got, statements)
+	}
+	if got := countQueries(statements, `FROM "rel_user_role"`) + countQueries(statements, `FROM "rel_role"`); got != 2 {
+		t.Fatalf("role queries = %d, want 2: %q", got, statements)
+	}
+
+	posts := rows[0]["posts"].([]map[string]any)
+	if len(posts) != 2 || posts[0]["title"] != "a1" || posts[1]["title"] != "a2" {
+		t.Fatalf("alice posts = %v", posts)
+	}
+	if _, ok := posts[0]["secret"]; ok {
+		t.Fatalf("hidden column returned in relation row: %v", posts[0])
+	}
+	if got := rows[1]["posts"].([]map[string]any); len(got) != 0 {
+		t.Fatalf("bob posts = %v, want empty", got)
+	}
+	if rows[1]["first"] != "none" {
+		t.Fatalf("bob first = %v, want EmptyValue", rows[1]["first"])
+	}
+	roles := rows[0]["roles"].([]map[string]any)
+	if len(roles) != 2 || roles[0]["name"] != "editor" || roles[1]["name"] != "admin" {
+		t.Fatalf("alice roles follow pivot order: %v", roles)
+	}
+}
+
+func TestWithChunksLargeKeySets(t *testing.T) {
+	ctx := ormtest.Setup(t)
+	users := make([]map[string]any, 1001)
+	for i := range users {
+		users[i] = map[string]any{"name": "u"}
+	}
+	ids := relUsers().InsertMany(ctx, users, 0)
+	posts := make([]map[string]any, len(ids))
+	for i, id := range ids {
+		posts[i] = map[string]any{"rel_user_id": id, "title": "p"}
+	}
+	relPosts().InsertMany(ctx, posts, 0)
+
+	var rows []map[string]any
+	statements := recordQueries(t, func() {
+		rows = relUsers().SelectMap(ctx, nil, map[string]any{"with": "posts"})
+	})
+	if got := countQueries(statements, `FROM "rel_post"`); got != 3 {
+		t.Fatalf("post queries = %d, want 3 batches of at most 500 keys", got)
+	}
+	for _, row := range rows {
+		if got := row["posts"].([]map[string]any); len(got) != 1 {
+			t.Fatalf("user %v posts = %v", row["id"], got)
+		}
+	}
+}
+
+func TestUnknownRelationReturnsError(t *testing.T) {
+	ctx := ormtest.Setup(t)
+	relUsers().Insert(ctx, map[string]any{"name": "alice"})
+	var statements []string
+	statements = recordQueries(t, func() {
+		_, err := relUsers().E().SelectMap(ctx, nil, map[string]any{"with": "posts,missing"})
+		if !errors.Is(err, orm.ErrUnknownRelation) {
+			t.Fatalf("err = %v, want ErrUnknownRelation", err)
+		}
+	})
+	if len(statements) != 0 {
+		t.Fatalf("queries ran before relation check: %q", statements)
+	}
+	if err := relUsers().ValidateRelations("posts, roles"); err != nil {
+		t.Fatalf("ValidateRelations = %v", err)
+	}
+	if _, err := relUsers().With("missing").E().Find(ctx, nil); !errors.Is(err, orm.ErrUnknownRelation) {
+		t.Fatalf("With err = %v, want ErrUnknownRelation", err)
+	}
+}
@@ -198,18 +198,56 @@ func safeAggregateIdentifier(value string) string {
 }
 
 func safeGeneratedAggregate(value string) string {
-	compact := strings.Join(strings.Fields(strings.TrimSpace(value)), "")
-	normalized := strings.ToUpper(compact)
-	switch {
-	case normalized == "COUNT(*)":
-		return "COUNT(*)"
-	case strings.HasPrefix(normalized, "SUM(") && strings.HasSuffix(normalized, ")"):
-		inner := strings.TrimSuffix(compact[4:], ")")
-		if field := safeAggregateIdentifier(inner); field != "" {
-			return "SUM(" + field + ")"
+	call, ok := parseAggregateCall(value)
+	if !ok {
+		return ""
+	}
+	if call.distinct {
+		return call.function + "(DISTINCT " + call.column + ")"
+	}
+	return call.function + "(" + call.column + ")"
+}
+
+// aggregateCall 是解析后的聚合调用，column 为 * 或限定标识符。
+type aggregateCall struct {
+	function string
+	column   string
+	distinct bool
+}
+
+// parseAggregateCall 只接受 COUNT/SUM/AVG/MAX/MIN 作用于单个字段（可带 DISTINCT），COUNT 额外允许 *。
+func parseAggregateCall(value string) (aggregateCall, bool) {
+	value = strings.TrimSpace(value)
+	open := strings.Index(value, "(")
+	if open <= 0 || !strings.HasSuffix(value, ")") {
+		return aggregateCall{}, false
+	}
+	call := aggregateCall{function: strings.ToUpper(strings.TrimSpace(value[:open]))}
+	switch call.function {
+	case "COUNT", "SUM", "AVG", "MAX", "MIN":
+	default:
+		return aggregateCall{}, false
+	}
+	inner := strings.Fields(value[open+1 : len(value)-1])
+	if len(inner) == 2 && strings.EqualFold(inner[0], "DISTINCT") {
+		call.distinct = true
+		inner = inner[1:]
+	}
+	if len(inner) != 1 {
+		return aggregateCall{}, false
+	}
+	if inner[0] == "*" {
+		if call.function != "COUNT" || call.distinct {
+			return aggregateCall{}, false
 		}
+		call.column = "*"
+		return call, true
+	}
+	if err := ensureQualifiedIdentifier(inner[0]); err != nil {
+		return aggregateCall{}, false
 	}
-	return ""
+	call.column = inner[0]
+	return call, true
 }
 
 func safeLimitText(value string) string {
@@ -0,0 +1,48 @@
+package orm
+
+import "testing"
+
+func TestSafeSQLFragments(t *testing.T) {
+	cases := []struct {
+		name string
+		got  string
+		want string
+	}{
+		{"fields", safeSelectFields("main.id, t0.name", "*"), "main.id, t0.name"},
+		{"fields star", safeSelectFields("main.*, t0.name", "*"), "main.*, t0.name"},
+		{"fields injection", safeSelectFields("id; DROP TABLE user", "*"), "*"},
+		{"fields expression", safeSelectFields("COUNT(id)", "*"), "*"},
+		{"fields raw", safeSelectFields(RawSQL(" COUNT(id) AS total "), "*"), "COUNT(id) AS total"},
+		{"order", safeOrderClause("main.id DESC, name", "id"), "main.id desc, name"},
+		{"order empty", safeOrderClause("", "id"), ""},
+		{"order direction", safeOrderClause("id sideways", "id"), "id"},
+		{"order injection", safeOrderClause("id; DELETE FROM user", "id"), "id"},
+		{"order raw", safeOrderClause(RawSQL("FIELD(id, 3, 1)"), "id"), "FIELD(id, 3, 1)"},
+		{"aggregate", safeAggregateExpression("count( distinct main.user_id )", ""), "COUNT(DISTINCT main.user_id)"},
+		{"aggregate star", safeAggregateExpression("COUNT(*)", ""), "COUNT(*)"},
+		{"aggregate column", safeAggregateExpression("main.amount", ""), "main.amount"},
+		{"aggregate star sum", safeAggregateExpression("SUM(*)", ""), ""},
+		{"aggregate function", safeAggregateExpression("LENGTH(name)", ""), ""},
+		{"aggregate nested", safeAggregateExpression("SUM(amount) + 1", ""), ""},
+		{"limit", safeLimitClause(" 20 "), "20"},
+		{"limit int", safeLimitClause(5), "5"},
+		{"limit negative", safeLimitClause(-1), ""},
+		{"limit injection", safeLimitClause("1; DROP TABLE user"), ""},
+		{"is null", safeISValue(nil), "NULL"},
+		{"is bool", safeISValue(false), "FALSE"},
+		{"is text", safeISValue(" unknown "), "UNKNOWN"},
+		{"is invalid", safeISValue("1 OR 1=1"), ""},
+		{"join type", safeJoinType("inner"), "INNER JOIN"},
+		{"join default", safeJoinType(""), "LEFT JOIN"},
+		{"join invalid", safeJoinType("NATURAL; DROP"), ""},
+		{"join on", safeJoinOnClause("t0.user_id = main.id and t0.kind = main.kind"), "t0.user_id = main.id AND t0.kind = main.kind"},
+		{"join on literal", safeJoinOnClause("t0.user_id = 1"), ""},
+		{"join on operator", safeJoinOnClause("t0.user_id > main.id"), ""},
+		{"join on or", safeJoinOnClause("t0.a = main.a OR 1 = 1"), ""},
+	}
+	for _, tc := range cases {
+		if tc.got != tc.want {
+			t.Errorf("%s = %q, want %q", tc.name, tc.got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,217 @@
+package orm
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 软删除：`dorm:"softdelete"` 标记的字段会为查询自动追加过滤条件，Delete 改为 UPDATE。
+//
+// 标签取值：
+//   - softdelete：时间类型字段删除时写入当前时间，未删除为 NULL；其他类型删除时写入 1
+//   - softdelete:unix / softdelete:milli：整型时间戳字段，未删除为 0
+//   - softdelete:<value>：删除时写入指定值，如 status 字段的 softdelete:2
+
+const (
+	softDeleteTime  = "time"
+	softDeleteUnix  = "unix"
+	softDeleteMilli = "milli"
+)
+
+type trashedMode int
+
+const (
+	trashedExclude trashedMode = iota
+	trashedInclude
+	trashedOnly
+)
+
+type trashedContextKey struct{}
+
+// WithTrashed 返回包含已软删除记录的查询上下文。
+func WithTrashed(ctx context.Context) context.Context {
+	return context.WithValue(normalizeContext(ctx), trashedContextKey{}, trashedInclude)
+}
+
+// OnlyTrashed 返回仅查询已软删除记录的上下文。
+func OnlyTrashed(ctx context.Context) context.Context {
+	return context.WithValue(normalizeContext(ctx), trashedContextKey{}, trashedOnly)
+}
+
+func trashedModeFrom(ctx context.Context) trashedMode {
+	if ctx == nil {
+		return trashedExclude
+	}
+	if mode, ok := ctx.Value(trashedContextKey{}).(trashedMode); ok {
+		return mode
+	}
+	return trashedExclude
+}
+
+func normalizeSoftDeleteMode(value, sqlType string) string {
+	value = strings.TrimSpace(value)
+	switch strings.ToLower(value) {
+	case softDeleteUnix, softDeleteMilli, softDeleteTime:
+		return strings.ToLower(value)
+	case "":
+		if isTimeSQLType(sqlType) {
+			return softDeleteTime
+		}
+		return "1"
+	}
+	return value
+}
+
+func isTimeSQLType(sqlType string) bool {
+	upper := strings.ToUpper(sqlType)
+	return strings.Contains(upper, "TIME") || strings.Contains(upper, "DATE")
+}
+
+func (s *tableSchema) softDeleteColumn() (columnDef, bool) {
+	if s == nil {
+		return columnDef{}, false
+	}
+	for _, col := range s.Columns {
+		if col.SoftDelete != "" {
+			return col, true
+		}
+	}
+	return columnDef{}, false
+}
+
+// scopeFilters 为查询追加软删除与租户条件，alias 为 "main." 或空（UPDATE/DELETE 不带别名）。
+func (m *modelCore) scopeFilters(ctx context.Context, filters any, alias string) any {
+	return scopeSchemaFilters(ctx, m.schema, filters, alias)
+}
+
+func scopeSchemaFilters(ctx context.Context, schema *tableSchema, filters any, alias string) any {
+	return tenantFilters(ctx, schema, softDeleteFilters(ctx, schema, filters, alias), alias)
+}
+
+func softDeleteFilters(ctx context.Context, schema *tableSchema, filters any, alias string) any {
+	col, ok := schema.softDeleteColumn()
+	if !ok {
+		return filters
+	}
+	key := alias + col.Name
+	switch trashedModeFrom(ctx) {
+	case trashedInclude:
+		return filters
+	case trashedOnly:
+		return mergeFilters(filters, softDeleteTrashedCondition(col, key))
+	default:
+		return mergeFilters(filters, softDeleteAliveCondition(col, key))
+	}
+}
+
+func softDeleteAliveCondition(col columnDef, key string) map[string]any {
+	var cond map[string]any
+	switch col.SoftDelete {
+	case softDeleteTime:
+		return map[string]any{key: nil}
+	case softDeleteUnix, softDeleteMilli:
+		cond = map[string]any{key: 0}
+	default:
+		cond = map[string]any{key: map[string]any{"!=": softDeleteLiteral(col.SoftDelete)}}
+	}
+	if col.NotNull {
+		return cond
+	}
+	return map[string]any{"or": []any{map[string]any{key: nil}, cond}}
+}
+
+func softDeleteTrashedCondition(col columnDef, key string) map[string]any {
+	switch col.SoftDelete {
+	case softDeleteTime:
+		return map[string]any{key: map[string]any{"!=": nil}}
+	case softDeleteUnix, softDeleteMilli:
+		return map[string]any{key: map[string]any{">": 0}}
+	default:
+		return map[string]any{key: softDeleteLiteral(col.SoftDelete)}
+	}
+}
+
+func softDeleteValue(col columnDef) any {
+	now := time.Now()
+	switch col.SoftDelete {
+	case softDeleteTime:
+		return now
+	case softDeleteUnix:
+		return now.Unix()
+	case softDeleteMilli:
+		return now.UnixMilli()
+	default:
+		return softDeleteLiteral(col.SoftDelete)
+	}
+}
+
+func softDeleteRestoreValue(col columnDef) any {
+	if col.SoftDelete == softDeleteTime {
+		return nil
+	}
+	if col.SoftDelete != softDeleteUnix && col.SoftDelete != softDeleteMilli {
+		if col.DefaultValue != nil && !col.DefaultIsRaw {
+			return softDeleteLiteral(*col.DefaultValue)
+		}
+	}
+	if col.NotNull {
+		return 0
+	}
+	return nil
+}
+
+func softDeleteLiteral(value string) any {
+	if parsed, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64); err == nil {
+		return parsed
+	}
+	return value
+}
+
+// mergeFilters 以 AND 组合原始条件与附加条件，兼容 map/slice 形式的条件树。
+func mergeFilters(filters any, extra map[string]any) any {
+	if isEmptyFilters(filters) {
+		return extra
+	}
+	return map[string]any{"and": []any{filters, extra}}
+}
+
+func isEmptyFilters(filters any) bool {
+	switch value := filters.(type) {
+	case nil:
+		return true
+	case map[string]any:
+		return len(value) == 0
+	case []map[string]any:
+		return len(value) == 0
+	case []any:
+		return len(value) == 0
+	default:
+		return false
+	}
+}
+
+// Restore 恢复软删除的记录，返回影响行数。
+func (m *modelCore) Restore(ctx context.Context, filters any) int64 {
+	col, ok := m.schema.softDeleteColumn()
+	if !ok {
+		panic(fmt.Errorf("orm: table %s has no softdelete column", m.table))
+	}
+	ctx = OnlyTrashed(ctx)
+	return m.Update(ctx, filters, map[string]any{col.Name: softDeleteRestoreValue(col)})
+}
+
+// ForceDelete 物理删除记录（忽略软删除状态），返回影响行数。
+func (m *modelCore) ForceDelete(ctx context.Context, filters any) int64 {
+	ctx = WithTrashed(ctx)
+	if m.audit {
+		return m.auditChange(ctx, auditDelete, filters, false, func(ctx context.Context) int64 {
+			return m.deleteRows(ctx, filters)
+		})
+	}
+	return m.hookTx(ctx, func(ctx context.Context) int64 {
+		return m.deleteRows(ctx, filters)
+	})
+}
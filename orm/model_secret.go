@@ -0,0 +1,387 @@
+package orm
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"maps"
+	"reflect"
+	"strings"
+	"sync"
+	"unicode"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/shemic/dever/util"
+)
+
+// 敏感字段：ModelConfig.Fields 中 Type 为 password 的字段在 Insert/InsertMany/Upsert/Update 时自动哈希，
+// 已有的哈希值需以 PasswordHash 包装才会原样写入。password 与 hidden 字段只有在 field 选项中按列名显式指定时
+// 才出现在查询结果中（main.* 等通配不算，map 结果删除该键，结构体保持零值）；结构体字段必须声明 json:"-"，
+// LoadModel 时校验。作为关联表被预加载时同样去掉这些字段（按同库同表已加载模型的配置合并）。
+
+// 密码哈希算法，FieldConfig.Algorithm 为空时使用 bcrypt。
+const (
+	PasswordBcrypt   = "bcrypt"
+	PasswordArgon2id = "argon2id"
+)
+
+const (
+	argon2Time    = 1
+	argon2Memory  = 64 * 1024
+	argon2Threads = 4
+	argon2KeyLen  = 32
+	argon2SaltLen = 16
+)
+
+// PasswordHash 标记已经哈希过的密码（如从旧系统迁移的 bcrypt/argon2id 值），写入 password 字段时原样保存。
+type PasswordHash string
+
+type fieldSecrets struct {
+	hidden    map[string]struct{}
+	passwords map[string]string
+}
+
+var (
+	tableSecretsMu sync.RWMutex
+	tableSecrets   = map[string]map[string]struct{}{}
+)
+
+// registerTableSecrets 记录表的 hidden/password 列，供关联预加载时去除。
+func registerTableSecrets(dbName, table string, secrets fieldSecrets) {
+	if len(secrets.hidden) == 0 {
+		return
+	}
+	key := tableCacheKey(dbName, table)
+	tableSecretsMu.Lock()
+	defer tableSecretsMu.Unlock()
+	columns := tableSecrets[key]
+	if columns == nil {
+		columns = map[string]struct{}{}
+		tableSecrets[key] = columns
+	}
+	for column := range secrets.hidden {
+		columns[column] = struct{}{}
+	}
+}
+
+// hideTableSecrets 删除关联记录中该表的 hidden/password 列。
+func hideTableSecrets(dbName, table string, records []map[string]any) {
+	tableSecretsMu.RLock()
+	defer tableSecretsMu.RUnlock()
+	columns := tableSecrets[tableCacheKey(dbName, table)]
+	if len(columns) == 0 {
+		return
+	}
+	for _, record := range records {
+		for column := range columns {
+			delete(record, column)
+		}
+	}
+}
+
+// resolveFieldSecrets 将 Fields 的键（字段名或列名）解析为列名。
+func resolveFieldSecrets(fields map[string]FieldConfig, schema *tableSchema) (fieldSecrets, error) {
+	var secrets fieldSecrets
+	for name, config := range fields {
+		if config.Type != FieldTypeHidden && config.Type != FieldTypePassword {
+			continue
+		}
+		column := name
+		if resolved, ok := schema.resolveColumn(name); ok {
+			column = resolved
+		} else if resolved, ok := schema.resolveColumn(util.ToSnake(name)); ok {
+			column = resolved
+		} else if config.Type == FieldTypePassword {
+			return secrets, fmt.Errorf("orm: password field %s of table %s not found", name, schema.Table)
+		} else {
+			continue
+		}
+		if secrets.hidden == nil {
+			secrets.hidden = map[string]struct{}{}
+		}
+		secrets.hidden[column] = struct{}{}
+		if config.Type != FieldTypePassword {
+			continue
+		}
+		algorithm := config.Algorithm
+		if algorithm == "" {
+			algorithm = PasswordBcrypt
+		}
+		if algorithm != PasswordBcrypt && algorithm != PasswordArgon2id {
+			return secrets, fmt.Errorf("orm: unsupported password algorithm %q for %s.%s", config.Algorithm, schema.Table, column)
+		}
+		if secrets.passwords == nil {
+			secrets.passwords = map[string]string{}
+		}
+		secrets.passwords[column] = algorithm
+	}
+	return secrets, nil
+}
+
+// hashPasswords 对写入数据中的密码字段做哈希，PasswordHash 原样保存，空串保持不变；不修改调用方传入的 map。
+func (m *modelCore) hashPasswords(data map[string]any) map[string]any {
+	copied := false
+	for column, algorithm := range m.secrets.passwords {
+		value, ok := data[column]
+		if !ok {
+			continue
+		}
+		var hashed string
+		if existing, ok := value.(PasswordHash); ok {
+			hashed = string(existing)
+		} else {
+			plaintext, ok := passwordText(value)
+			if !ok || plaintext == "" {
+				continue
+			}
+			var err error
+			hashed, err = HashPassword(plaintext, algorithm)
+			panicOnError(err)
+		}
+		if !copied {
+			data = maps.Clone(data)
+			copied = true
+		}
+		data[column] = hashed
+	}
+	return data
+}
+
+// hideFields 删除结果中的 hidden/password 字段；只有 field 选项按列名显式指定的字段才保留，main.* 等通配不算。
+func (m *modelCore) hideFields(records []map[string]any, options map[string]any) []map[string]any {
+	if len(m.secrets.hidden) == 0 {
+		return records
+	}
+	named := requestedColumns(options)
+	for column := range m.secrets.hidden {
+		if _, ok := named[strings.ToLower(column)]; ok {
+			continue
+		}
+		for _, record := range records {
+			delete(record, column)
+		}
+	}
+	return records
+}
+
+func (m *modelCore) hideRecord(record map[string]any, options map[string]any) map[string]any {
+	if len(record) > 0 {
+		m.hideFields([]map[string]any{record}, options)
+	}
+	return record
+}
+
+// maskSecrets 返回把 hidden/password 字段替换为掩码的副本，用于审计日志等不应保存原值的场景。
+func (m *modelCore) maskSecrets(record map[string]any) map[string]any {
+	if record == nil || len(m.secrets.hidden) == 0 {
+		return record
+	}
+	masked := maps.Clone(record)
+	for column := range m.secrets.hidden {
+		if _, ok := masked[column]; ok {
+			masked[column] = "******"
+		}
+	}
+	return masked
+}
+
+// requestedColumns 返回 field 选项中按名称列出的列（小写，去掉表别名）；RawSQL 按其中出现的标识符计算。
+func requestedColumns(options map[string]any) map[string]struct{} {
+	if options == nil {
+		return nil
+	}
+	var items []string
+	switch field := options["field"].(type) {
+	case nil:
+		return nil
+	case SQLRaw:
+		items = strings.FieldsFunc(string(field), func(r rune) bool {
+			return r != '_' && r != '.' && r != '*' && !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		})
+	default:
+		items = strings.Split(safeSelectFields(field, ""), ",")
+	}
+	named := make(map[string]struct{}, len(items))
+	for _, item := range items {
+		item = strings.TrimSpace(item)
+		if idx := strings.LastIndex(item, "."); idx >= 0 {
+			item = item[idx+1:]
+		}
+		if item != "" && item != "*" {
+			named[strings.ToLower(item)] = struct{}{}
+		}
+	}
+	return named
+}
+
+// checkSecretJSONTags 要求 hidden/password 列对应的结构体字段声明 json:"-"，避免零值键出现在对外输出中。
+func checkSecretJSONTags(model any, table string, secrets fieldSecrets) error {
+	if len(secrets.hidden) == 0 || model == nil {
+		return nil
+	}
+	rt := reflect.TypeOf(model)
+	for rt.Kind() == reflect.Pointer {
+		rt = rt.Elem()
+	}
+	if rt.Kind() != reflect.Struct {
+		return nil
+	}
+	return checkSecretStructFields(rt, table, secrets.hidden)
+}
+
+func checkSecretStructFields(rt reflect.Type, table string, hidden map[string]struct{}) error {
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		if field.Anonymous && field.Tag.Get("db") == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				if err := checkSecretStructFields(embedded, table, hidden); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+		column := fieldColumnName(field)
+		for name := range hidden {
+			if strings.EqualFold(name, column) && field.Tag.Get("json") != "-" {
+				return fmt.Errorf("orm: hidden field %s.%s must be tagged json:\"-\"", table, field.Name)
+			}
+		}
+	}
+	return nil
+}
+
+// HashPassword 按算法（bcrypt/argon2id，空为 bcrypt）生成密码哈希。
+func HashPassword(plaintext, algorithm string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(algorithm)) {
+	case "", PasswordBcrypt:
+		hashed, err := bcrypt.GenerateFromPassword([]byte(plaintext), bcrypt.DefaultCost)
+		if err != nil {
+			return "", err
+		}
+		return string(hashed), nil
+	case PasswordArgon2id:
+		salt := make([]byte, argon2SaltLen)
+		if _, err := rand.Read(salt); err != nil {
+			return "", err
+		}
+		key := argon2.IDKey([]byte(plaintext), salt, argon2Time, argon2Memory, argon2Threads, argon2KeyLen)
+		return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s", argon2.Version, argon2Memory, argon2Time, argon2Threads,
+			base64.RawStdEncoding.EncodeToString(salt), base64.RawStdEncoding.EncodeToString(key)), nil
+	default:
+		return "", fmt.Errorf("orm: unsupported password algorithm %q", algorithm)
+	}
+}
+
+// VerifyPassword 校验 row（map 或结构体）中 field 字段的密码哈希是否与 plaintext 匹配，算法按哈希格式识别。
+// 查询时密码字段默认不返回，需要通过 field 选项显式查询，如 {"field": "main.id, main.password"}。
+func VerifyPassword(row any, field, plaintext string) bool {
+	value, ok := lookupPasswordValue(row, field)
+	if !ok {
+		return false
+	}
+	hashed, ok := passwordText(value)
+	if !ok || hashed == "" {
+		return false
+	}
+	if strings.HasPrefix(hashed, "$argon2id$") {
+		return verifyArgon2id(hashed, plaintext)
+	}
+	return bcrypt.CompareHashAndPassword([]byte(hashed), []byte(plaintext)) == nil
+}
+
+func verifyArgon2id(hashed, plaintext string) bool {
+	params, ok := parseArgon2id(hashed)
+	if !ok {
+		return false
+	}
+	key := argon2.IDKey([]byte(plaintext), params.salt, params.time, params.memory, params.threads, uint32(len(params.key)))
+	return subtle.ConstantTimeCompare(key, params.key) == 1
+}
+
+type argon2Params struct {
+	memory  uint32
+	time    uint32
+	threads uint8
+	salt    []byte
+	key     []byte
+}
+
+func parseArgon2id(hashed string) (argon2Params, bool) {
+	var params argon2Params
+	parts := strings.Split(hashed, "$")
+	if len(parts) != 6 || parts[1] != "argon2id" {
+		return params, false
+	}
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return params, false
+	}
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.memory, &params.time, &params.threads); err != nil {
+		return params, false
+	}
+	var err error
+	if params.salt, err = base64.RawStdEncoding.DecodeString(parts[4]); err != nil {
+		return params, false
+	}
+	if params.key, err = base64.RawStdEncoding.DecodeString(parts[5]); err != nil || len(params.key) == 0 {
+		return params, false
+	}
+	return params, true
+}
+
+func passwordText(value any) (string, bool) {
+	switch typed := value.(type) {
+	case string:
+		return typed, true
+	case *string:
+		if typed == nil {
+			return "", false
+		}
+		return *typed, true
+	case []byte:
+		return string(typed), true
+	default:
+		return "", false
+	}
+}
+
+func lookupPasswordValue(row any, field string) (any, bool) {
+	if record, ok := row.(map[string]any); ok {
+		if value, ok := record[field]; ok {
+			return value, true
+		}
+		value, ok := record[util.ToSnake(field)]
+		return value, ok
+	}
+	rv := reflect.ValueOf(row)
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return nil, false
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil, false
+	}
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		item := rt.Field(i)
+		if !item.IsExported() {
+			continue
+		}
+		if item.Name == field || util.ToSnake(item.Name) == field || item.Tag.Get("db") == field {
+			return rv.Field(i).Interface(), true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,140 @@
+package orm_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/shemic/dever/orm"
+	"github.com/shemic/dever/orm/ormtest"
+)
+
+type secretUser struct {
+	ID       uint64 `dorm:"primaryKey;autoIncrement"`
+	Name     string `dorm:"type:varchar(32)"`
+	Password string `dorm:"type:varchar(128)" json:"-"`
+	Pin      string `dorm:"type:varchar(128)" json:"-"`
+}
+
+type secretToken struct {
+	ID           uint64 `dorm:"primaryKey;autoIncrement"`
+	SecretUserID uint64
+	Token        string `dorm:"type:varchar(64)" json:"-"`
+}
+
+type secretLeaky struct {
+	ID    uint64 `dorm:"primaryKey;autoIncrement"`
+	Token string `dorm:"type:varchar(64)" json:"token,omitempty"`
+}
+
+var (
+	secretUsers = ormtest.Register[secretUser]("secret_user", orm.ModelConfig{
+		Fields: map[string]orm.FieldConfig{
+			"password": {Type: orm.FieldTypePassword},
+			"pin":      {Type: orm.FieldTypePassword, Algorithm: orm.PasswordArgon2id},
+		},
+		Relations: []orm.Relation{
+			{Kind: orm.RelationHasMany, Name: "tokens", Table: "secret_token", OwnerField: "secret_user_id"},
+		},
+	})
+	secretTokens = ormtest.Register[secretToken]("secret_token", orm.ModelConfig{
+		Fields: map[string]orm.FieldConfig{"token": {Type: orm.FieldTypeHidden}},
+	})
+)
+
+func TestPasswordHashing(t *testing.T) {
+	ctx := ormtest.Setup(t)
+	users := secretUsers()
+	id := users.Insert(ctx, map[string]any{"name": "alice", "password": "secret", "pin": "1234"})
+
+	row := users.FindMap(ctx, map[string]any{"id": id}, map[string]any{"field": "main.id, main.password, main.pin"})
+	hashed, _ := row["password"].(string)
+	if hashed == "" || hashed == "secret" {
+		t.Fatalf("password stored as %q", hashed)
+	}
+	if pin, _ := row["pin"].(string); !strings.HasPrefix(pin, "$argon2id$") {
+		t.Fatalf("pin stored as %q", pin)
+	}
+	if !orm.VerifyPassword(row, "password", "secret") || orm.VerifyPassword(row, "password", "wrong") {
+		t.Fatal("bcrypt verify mismatch")
+	}
+	if !orm.VerifyPassword(row, "pin", "1234") {
+		t.Fatal("argon2id verify mismatch")
+	}
+
+	// 形如哈希的明文同样会被哈希，已有哈希需显式包装为 PasswordHash。
+	users.Update(ctx, map[string]any{"id": id}, map[string]any{"password": hashed})
+	rehashed := users.FindMap(ctx, map[string]any{"id": id}, map[string]any{"field": "main.password"})
+	if rehashed["password"] == hashed || !orm.VerifyPassword(rehashed, "password", hashed) {
+		t.Fatalf("hash-like plaintext stored as %v", rehashed["password"])
+	}
+	users.Update(ctx, map[string]any{"id": id}, map[string]any{"password": orm.PasswordHash(hashed)})
+	ormtest.AssertRowExists(t, ctx, "secret_user", map[string]any{"id": id, "password": hashed})
+
+	users.Update(ctx, map[string]any{"id": id}, map[string]any{"password": ""})
+	ormtest.AssertRowExists(t, ctx, "secret_user", map[string]any{"id": id, "password": ""})
+}
+
+func TestHiddenFieldsRequireExplicitColumns(t *testing.T) {
+	ctx := ormtest.Setup(t)
+	users := secretUsers()
+	id := users.Insert(ctx, map[string]any{"name": "alice", "password": "secret"})
+
+	cases := map[string]map[string]any{
+		"no field":      nil,
+		"main star":     {"field": "main.*"},
+		"star and name": {"field": "main.*, main.name"},
+		"other columns": {"field": "main.id, main.name"},
+	}
+	for name, options := range cases {
+		var row map[string]any
+		if options == nil {
+			row = users.FindMap(ctx, map[string]any{"id": id})
+		} else {
+			row = users.FindMap(ctx, map[string]any{"id": id}, options)
+		}
+		if _, ok := row["password"]; ok || row == nil {
+			t.Errorf("%s: row = %v, want password stripped", name, row)
+		}
+	}
+	if row := users.FindMap(ctx, map[string]any{"id": id}, map[string]any{"field": "main.*, main.password"}); row["password"] == nil {
+		t.Errorf("explicit password column stripped: %v", row)
+	}
+	if row := users.FindMap(ctx, map[string]any{"id": id}, map[string]any{"field": orm.RawSQL("main.id, main.password AS password")}); row["password"] == nil {
+		t.Errorf("raw password column stripped: %v", row)
+	}
+	if user := users.Find(ctx, map[string]any{"id": id}); user == nil || user.Password != "" {
+		t.Fatalf("struct password = %+v", user)
+	}
+}
+
+func TestHiddenFieldsStrippedFromRelations(t *testing.T) {
+	ctx := ormtest.Setup(t)
+	users := secretUsers()
+	id := users.Insert(ctx, map[string]any{"name": "alice", "password": "secret"})
+	secretTokens().Insert(ctx, map[string]any{"secret_user_id": id, "token": "t0ken"})
+
+	rows := users.With("tokens").SelectMap(ctx, map[string]any{"id": id})
+	if len(rows) != 1 {
+		t.Fatalf("rows = %v", rows)
+	}
+	tokens, _ := rows[0]["tokens"].([]map[string]any)
+	if len(tokens) != 1 {
+		t.Fatalf("tokens = %#v", rows[0]["tokens"])
+	}
+	if _, ok := tokens[0]["token"]; ok {
+		t.Fatalf("hidden column returned in relation: %v", tokens[0])
+	}
+}
+
+func TestHiddenFieldRequiresJSONDash(t *testing.T) {
+	ormtest.Setup(t)
+	defer func() {
+		err, _ := recover().(error)
+		if err == nil || !strings.Contains(err.Error(), `json:"-"`) {
+			t.Fatalf("LoadModel err = %v, want json tag error", err)
+		}
+	}()
+	orm.LoadModel[secretLeaky]("secret_leaky", "secret_leaky", orm.ModelConfig{
+		Fields: map[string]orm.FieldConfig{"token": {Type: orm.FieldTypeHidden}},
+	})
+}
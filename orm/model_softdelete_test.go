@@ -0,0 +1,47 @@
+package orm_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shemic/dever/orm"
+	"github.com/shemic/dever/orm/ormtest"
+)
+
+type softArticle struct {
+	ID        uint64     `dorm:"primaryKey;autoIncrement"`
+	Title     string     `dorm:"type:varchar(64)"`
+	DeletedAt *time.Time `dorm:"softdelete"`
+}
+
+var softArticles = ormtest.Register[softArticle]("soft_article", orm.ModelConfig{})
+
+func TestSoftDeleteAndRestore(t *testing.T) {
+	ctx := ormtest.Setup(t)
+	id := softArticles().Insert(ctx, map[string]any{"title": "hello"})
+	softArticles().Insert(ctx, map[string]any{"title": "other"})
+
+	if affected := softArticles().Delete(ctx, map[string]any{"id": id}); affected != 1 {
+		t.Fatalf("delete affected = %d, want 1", affected)
+	}
+	ormtest.AssertCount(t, ctx, "soft_article", nil, 2)
+	if got := softArticles().Count(ctx, nil); got != 1 {
+		t.Fatalf("count = %d, want 1", got)
+	}
+	if softArticles().Find(ctx, map[string]any{"id": id}) != nil {
+		t.Fatal("soft-deleted row is visible")
+	}
+	if got := softArticles().Count(orm.WithTrashed(ctx), nil); got != 2 {
+		t.Fatalf("count with trashed = %d, want 2", got)
+	}
+	if got := softArticles().Count(orm.OnlyTrashed(ctx), nil); got != 1 {
+		t.Fatalf("count only trashed = %d, want 1", got)
+	}
+	if affected := softArticles().Restore(ctx, map[string]any{"id": id}); affected != 1 {
+		t.Fatalf("restore affected = %d, want 1", affected)
+	}
+	ormtest.AssertRowExists(t, ctx, "soft_article", map[string]any{"id": id, "deleted_at": nil})
+
+	softArticles().ForceDelete(ctx, map[string]any{"id": id})
+	ormtest.AssertCount(t, ctx, "soft_article", nil, 1)
+}
@@ -0,0 +1,145 @@
+package orm
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"strings"
+)
+
+// 多租户：`dorm:"tenant"` 标记的字段为租户列，查询、更新、删除自动追加 租户列 = 当前租户 条件，
+// 插入时自动写入当前租户。上下文没有租户时直接 panic（ErrTenantRequired），
+// 后台任务等需要跨租户访问的场景使用 SkipTenant 显式跳过。
+
+type tenantContextKey struct{}
+
+type tenantScope struct {
+	id   any
+	skip bool
+}
+
+// WithTenant 返回绑定租户的上下文，id 会按租户列类型转换。
+func WithTenant(ctx context.Context, id any) context.Context {
+	return context.WithValue(normalizeContext(ctx), tenantContextKey{}, tenantScope{id: id})
+}
+
+// SkipTenant 返回不追加租户条件的上下文，用于跨租户的后台任务与运维脚本。
+func SkipTenant(ctx context.Context) context.Context {
+	return context.WithValue(normalizeContext(ctx), tenantContextKey{}, tenantScope{skip: true})
+}
+
+// TenantFrom 返回上下文中的租户；未设置或已 SkipTenant 时返回 false。
+func TenantFrom(ctx context.Context) (any, bool) {
+	scope := tenantScopeFrom(ctx)
+	if scope.skip || scope.id == nil {
+		return nil, false
+	}
+	return scope.id, true
+}
+
+func tenantScopeFrom(ctx context.Context) tenantScope {
+	if ctx == nil {
+		return tenantScope{}
+	}
+	if scope, ok := ctx.Value(tenantContextKey{}).(tenantScope); ok {
+		return scope
+	}
+	return tenantScope{}
+}
+
+// tenantCacheKey 为缓存键区分租户，避免不同租户命中同一份结果。
+func tenantCacheKey(ctx context.Context) string {
+	scope := tenantScopeFrom(ctx)
+	if scope.skip {
+		return "*"
+	}
+	if scope.id == nil {
+		return ""
+	}
+	return fmt.Sprintf("%T=%v", scope.id, scope.id)
+}
+
+func (s *tableSchema) tenantColumn() (columnDef, bool) {
+	if s == nil {
+		return columnDef{}, false
+	}
+	for _, col := range s.Columns {
+		if col.Tenant {
+			return col, true
+		}
+	}
+	return columnDef{}, false
+}
+
+// tenantValue 返回当前租户值；表没有租户列或已跳过时 ok 为 false，缺少租户时 panic。
+func tenantValue(ctx context.Context, schema *tableSchema) (columnDef, any, bool) {
+	col, ok := schema.tenantColumn()
+	if !ok {
+		return columnDef{}, nil, false
+	}
+	scope := tenantScopeFrom(ctx)
+	if scope.skip {
+		return columnDef{}, nil, false
+	}
+	if scope.id == nil {
+		panic(fmt.Errorf("%w: table %s", ErrTenantRequired, schema.Table))
+	}
+	return col, normalizeValueByType(scope.id, col.Type), true
+}
+
+func tenantFilters(ctx context.Context, schema *tableSchema, filters any, alias string) any {
+	col, value, ok := tenantValue(ctx, schema)
+	if !ok {
+		return filters
+	}
+	return mergeFilters(filters, map[string]any{alias + col.Name: value})
+}
+
+// fillInsertTenant 为插入数据写入当前租户，data 需已完成字段名归一化；显式传入其他租户时 panic。
+func (m *modelCore) fillInsertTenant(ctx context.Context, data map[string]any) map[string]any {
+	col, value, ok := tenantValue(ctx, m.schema)
+	if !ok {
+		return data
+	}
+	if current, exists := data[col.Name]; exists && current != nil {
+		if !sameTenant(current, value, col) {
+			panic(fmt.Errorf("%w: insert %s with tenant %v", ErrTenantMismatch, m.table, current))
+		}
+		return data
+	}
+	data = maps.Clone(data)
+	if data == nil {
+		data = map[string]any{}
+	}
+	data[col.Name] = value
+	return data
+}
+
+// checkUpdateTenant 禁止通过 Update 把记录改到其他租户。
+func (m *modelCore) checkUpdateTenant(ctx context.Context, data map[string]any) {
+	col, value, ok := tenantValue(ctx, m.schema)
+	if !ok {
+		return
+	}
+	if current, exists := data[col.Name]; exists && !sameTenant(current, value, col) {
+		panic(fmt.Errorf("%w: update %s to tenant %v", ErrTenantMismatch, m.table, current))
+	}
+}
+
+// checkUpsertTenant 要求冲突字段包含租户列：否则冲突可能命中其他租户的记录并被覆盖。
+func (m *modelCore) checkUpsertTenant(ctx context.Context, conflict []string) {
+	col, _, ok := tenantValue(ctx, m.schema)
+	if !ok {
+		return
+	}
+	for _, column := range conflict {
+		if strings.EqualFold(column, col.Name) {
+			return
+		}
+	}
+	panic(fmt.Errorf("%w: upsert %s conflict columns must include %s", ErrTenantMismatch, m.table, col.Name))
+}
+
+func sameTenant(value, tenant any, col columnDef) bool {
+	return fmt.Sprint(normalizeValueByType(value, col.Type)) == fmt.Sprint(tenant)
+}
@@ -0,0 +1,56 @@
+package orm_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/shemic/dever/orm"
+	"github.com/shemic/dever/orm/ormtest"
+)
+
+type tenantOrder struct {
+	ID       uint64 `dorm:"primaryKey;autoIncrement"`
+	TenantID uint64 `dorm:"tenant"`
+	No       string `dorm:"type:varchar(32)"`
+}
+
+var tenantOrders = ormtest.Register[tenantOrder]("tenant_order", orm.ModelConfig{})
+
+func TestTenantScoping(t *testing.T) {
+	ctx := ormtest.Setup(t)
+	orders := tenantOrders()
+	tenantA := orm.WithTenant(ctx, 1)
+	tenantB := orm.WithTenant(ctx, 2)
+	orders.Insert(tenantA, map[string]any{"no": "A001"})
+	orders.Insert(tenantB, map[string]any{"no": "B001"})
+	orders.Insert(tenantB, map[string]any{"no": "B002"})
+
+	ormtest.AssertRowExists(t, ctx, "tenant_order", map[string]any{"tenant_id": 1, "no": "A001"})
+	if got := orders.Count(tenantA, nil); got != 1 {
+		t.Fatalf("tenant 1 count = %d, want 1", got)
+	}
+	if got := orders.Count(tenantB, nil); got != 2 {
+		t.Fatalf("tenant 2 count = %d, want 2", got)
+	}
+	if got := orders.Count(orm.SkipTenant(ctx), nil); got != 3 {
+		t.Fatalf("skip tenant count = %d, want 3", got)
+	}
+	if got := orders.Query().Where("no", "like", "B%").Count(tenantA); got != 0 {
+		t.Fatalf("query builder cross-tenant count = %d, want 0", got)
+	}
+	if affected := orders.Update(tenantA, map[string]any{"no": "B001"}, map[string]any{"no": "X"}); affected != 0 {
+		t.Fatalf("cross-tenant update affected = %d, want 0", affected)
+	}
+	if affected := orders.Delete(tenantA, map[string]any{"no": "B002"}); affected != 0 {
+		t.Fatalf("cross-tenant delete affected = %d, want 0", affected)
+	}
+	if affected := orders.Update(tenantB, map[string]any{"no": "B001"}, map[string]any{"no": "B003"}); affected != 1 {
+		t.Fatalf("same-tenant update affected = %d, want 1", affected)
+	}
+	if _, err := orders.E().Find(ctx, map[string]any{"no": "A001"}); !errors.Is(err, orm.ErrTenantRequired) {
+		t.Fatalf("find without tenant err = %v, want ErrTenantRequired", err)
+	}
+	if _, err := orders.E().Insert(tenantA, map[string]any{"no": "A002", "tenant_id": 2}); !errors.Is(err, orm.ErrTenantMismatch) {
+		t.Fatalf("insert other tenant err = %v, want ErrTenantMismatch", err)
+	}
+}
@@ -0,0 +1,83 @@
+package orm
+
+import (
+	"maps"
+	"strings"
+	"time"
+)
+
+// 自动时间戳：`dorm:"autoCreateTime"` / `dorm:"autoUpdateTime"`。
+// 时间类型字段写入 time.Time，整型字段默认写入秒级时间戳，可用 autoCreateTime:milli 指定毫秒。
+// 调用方显式传入非空值时不覆盖。
+
+const (
+	autoTimeTime  = "time"
+	autoTimeUnix  = "unix"
+	autoTimeMilli = "milli"
+)
+
+func normalizeAutoTimeMode(value, sqlType string) string {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case autoTimeUnix, "second", "seconds":
+		return autoTimeUnix
+	case autoTimeMilli, "millis", "millisecond", "milliseconds":
+		return autoTimeMilli
+	}
+	if strings.Contains(strings.ToUpper(sqlType), "INT") {
+		return autoTimeUnix
+	}
+	return autoTimeTime
+}
+
+func autoTimeValue(mode string, now time.Time) any {
+	switch mode {
+	case autoTimeUnix:
+		return now.Unix()
+	case autoTimeMilli:
+		return now.UnixMilli()
+	default:
+		return now
+	}
+}
+
+// autoTimeMode 返回插入时需要填充的时间模式，autoUpdateTime 字段插入时同样填充。
+func (c columnDef) autoTimeMode() string {
+	if c.AutoCreate != "" {
+		return c.AutoCreate
+	}
+	return c.AutoUpdate
+}
+
+// fillInsertTimestamps 为插入数据补齐自动时间字段，data 需已完成字段名归一化；不修改调用方传入的 map。
+func (m *modelCore) fillInsertTimestamps(data map[string]any, now time.Time) map[string]any {
+	return m.fillTimestamps(data, now, columnDef.autoTimeMode)
+}
+
+// fillUpdateTimestamps 为更新数据补齐 autoUpdateTime 字段。
+func (m *modelCore) fillUpdateTimestamps(data map[string]any, now time.Time) map[string]any {
+	return m.fillTimestamps(data, now, func(c columnDef) string {
+		return c.AutoUpdate
+	})
+}
+
+func (m *modelCore) fillTimestamps(data map[string]any, now time.Time, modeOf func(columnDef) string) map[string]any {
+	if m.schema == nil {
+		return data
+	}
+	copied := false
+	for _, col := range m.schema.Columns {
+		mode := modeOf(col)
+		if mode == "" || hasSeedInsertValue(data[col.Name]) {
+			continue
+		}
+		if !copied {
+			data = maps.Clone(data)
+			if data == nil {
+				data = map[string]any{}
+			}
+			copied = true
+		}
+		data[col.Name] = autoTimeValue(mode, now)
+	}
+	return data
+}
@@ -0,0 +1,67 @@
+package orm_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shemic/dever/orm"
+	"github.com/shemic/dever/orm/ormtest"
+)
+
+type stampNote struct {
+	ID        uint64    `dorm:"primaryKey;autoIncrement"`
+	Code      string    `dorm:"type:varchar(32);unique"`
+	Title     string    `dorm:"type:varchar(32)"`
+	CreatedAt time.Time `dorm:"autoCreateTime"`
+	UpdatedAt time.Time `dorm:"autoUpdateTime"`
+	CreatedTs int64     `dorm:"autoCreateTime"`
+	UpdatedMs int64     `dorm:"autoUpdateTime:milli"`
+}
+
+var stampNotes = ormtest.Register[stampNote]("stamp_note", orm.ModelConfig{})
+
+func TestAutoTimestampsOnInsert(t *testing.T) {
+	ctx := ormtest.Setup(t)
+	before := time.Now().Add(-time.Second)
+	stampNotes().Insert(ctx, map[string]any{"code": "a", "title": "a"})
+	stampNotes().InsertMany(ctx, []map[string]any{{"code": "b"}}, 0)
+	stampNotes().Upsert(ctx, []map[string]any{{"code": "c"}}, []string{"code"}, []string{"title"})
+	after := time.Now().Add(time.Second)
+
+	for _, code := range []string{"a", "b", "c"} {
+		note := stampNotes().Find(ctx, map[string]any{"code": code})
+		if note.CreatedAt.Before(before) || note.CreatedAt.After(after) || note.UpdatedAt.Before(before) {
+			t.Fatalf("%s time columns = %v / %v, want now", code, note.CreatedAt, note.UpdatedAt)
+		}
+		if note.CreatedTs < before.Unix() || note.CreatedTs > after.Unix() {
+			t.Fatalf("%s created_ts = %d, want unix seconds", code, note.CreatedTs)
+		}
+		if note.UpdatedMs < before.UnixMilli() || note.UpdatedMs > after.UnixMilli() {
+			t.Fatalf("%s updated_ms = %d, want unix millis", code, note.UpdatedMs)
+		}
+	}
+
+	fixed := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	explicit := stampNotes().Insert(ctx, map[string]any{"code": "d", "created_at": fixed, "created_ts": 7})
+	note := stampNotes().Find(ctx, map[string]any{"id": explicit})
+	if !note.CreatedAt.Equal(fixed) || note.CreatedTs != 7 {
+		t.Fatalf("explicit values overwritten: %v / %d", note.CreatedAt, note.CreatedTs)
+	}
+}
+
+func TestAutoUpdateTimeRefreshesOnUpdate(t *testing.T) {
+	ctx := ormtest.Setup(t)
+	old := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	id := stampNotes().Insert(ctx, map[string]any{
+		"code": "a", "created_at": old, "updated_at": old, "created_ts": 1, "updated_ms": 1,
+	})
+
+	stampNotes().Update(ctx, map[string]any{"id": id}, map[string]any{"title": "changed"})
+	note := stampNotes().Find(ctx, map[string]any{"id": id})
+	if !note.CreatedAt.Equal(old) || note.CreatedTs != 1 {
+		t.Fatalf("autoCreateTime changed on update: %v / %d", note.CreatedAt, note.CreatedTs)
+	}
+	if !note.UpdatedAt.After(old) || note.UpdatedMs <= 1 {
+		t.Fatalf("autoUpdateTime not refreshed: %v / %d", note.UpdatedAt, note.UpdatedMs)
+	}
+}
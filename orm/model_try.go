@@ -0,0 +1,365 @@
+package orm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"runtime"
+)
+
+// 返回 error 的 API：与 Model 共用同一套语句拼装，只是把 panic 转为 error 返回，
+// 适合后台任务等不经过 middleware.Recover 的场景。
+
+// ErrorModel 是 Model 的 error 返回视图，通过 Model.E() 获取。
+type ErrorModel[T any] struct {
+	model *Model[T]
+}
+
+// E 返回不 panic 的模型视图，错误经过归一化，可用 errors.Is 判断 ErrNotFound、ErrUniqueViolation 等。
+func (m *Model[T]) E() *ErrorModel[T] {
+	return &ErrorModel[T]{model: m}
+}
+
+// Select 查询多条记录。
+func (e *ErrorModel[T]) Select(ctx context.Context, filters any, options ...map[string]any) ([]*T, error) {
+	return try(func() []*T {
+		return e.model.Select(ctx, filters, options...)
+	})
+}
+
+// SelectMap 查询多条记录并返回 map 结果。
+func (e *ErrorModel[T]) SelectMap(ctx context.Context, filters any, options ...map[string]any) ([]map[string]any, error) {
+	return try(func() []map[string]any {
+		return e.model.SelectMap(ctx, filters, options...)
+	})
+}
+
+// Find 查询单条记录，未命中时返回 ErrNotFound。
+func (e *ErrorModel[T]) Find(ctx context.Context, filters any, options ...map[string]any) (*T, error) {
+	result, err := try(func() *T {
+		return e.model.Find(ctx, filters, options...)
+	})
+	if err == nil && result == nil {
+		return nil, ErrNotFound
+	}
+	return result, err
+}
+
+// FindMap 查询单条记录并返回 map 结果，未命中时返回 ErrNotFound。
+func (e *ErrorModel[T]) FindMap(ctx context.Context, filters any, options ...map[string]any) (map[string]any, error) {
+	result, err := try(func() map[string]any {
+		return e.model.FindMap(ctx, filters, options...)
+	})
+	if err == nil && len(result) == 0 {
+		return nil, ErrNotFound
+	}
+	return result, err
+}
+
+// Paginate 按页码分页查询。
+func (e *ErrorModel[T]) Paginate(ctx context.Context, filters any, req PageRequest, options ...map[string]any) (Page[*T], error) {
+	return try(func() Page[*T] {
+		return e.model.Paginate(ctx, filters, req, options...)
+	})
+}
+
+// KeysetPaginate 按游标分页查询，游标无效时返回 ErrInvalidCursor。
+func (e *ErrorModel[T]) KeysetPaginate(ctx context.Context, filters any, req PageRequest, options ...map[string]any) (Page[*T], error) {
+	return try(func() Page[*T] {
+		return e.model.KeysetPaginate(ctx, filters, req, options...)
+	})
+}
+
+// PaginateMap 按页码分页查询并返回 map 结果。
+func (e *ErrorModel[T]) PaginateMap(ctx context.Context, filters any, req PageRequest, options ...map[string]any) (Page[map[string]any], error) {
+	return try(func() Page[map[string]any] {
+		return e.model.PaginateMap(ctx, filters, req, options...)
+	})
+}
+
+// KeysetPaginateMap 按游标分页查询并返回 map 结果。
+func (e *ErrorModel[T]) KeysetPaginateMap(ctx context.Context, filters any, req PageRequest, options ...map[string]any) (Page[map[string]any], error) {
+	return try(func() Page[map[string]any] {
+		return e.model.KeysetPaginateMap(ctx, filters, req, options...)
+	})
+}
+
+// Iterate 逐行返回查询结果，Model.Iterate 本身即通过 error 返回错误。
+func (e *ErrorModel[T]) Iterate(ctx context.Context, filters any, options ...map[string]any) iter.Seq2[*T, error] {
+	return e.model.Iterate(ctx, filters, options...)
+}
+
+// IterateMap 逐行返回 map 结果。
+func (e *ErrorModel[T]) IterateMap(ctx context.Context, filters any, options ...map[string]any) iter.Seq2[map[string]any, error] {
+	return e.model.IterateMap(ctx, filters, options...)
+}
+
+// Count 统计满足条件的行数。
+func (e *ErrorModel[T]) Count(ctx context.Context, filters any, options ...map[string]any) (int64, error) {
+	return try(func() int64 {
+		return e.model.Count(ctx, filters, options...)
+	})
+}
+
+// Sum 返回指定字段的求和结果。
+func (e *ErrorModel[T]) Sum(ctx context.Context, column string, filters any, options ...map[string]any) (float64, error) {
+	return try(func() float64 {
+		return e.model.Sum(ctx, column, filters, options...)
+	})
+}
+
+// Max 返回指定字段的最大值，没有记录时返回 nil。
+func (e *ErrorModel[T]) Max(ctx context.Context, column string, filters any, options ...map[string]any) (any, error) {
+	return try(func() any {
+		return e.model.Max(ctx, column, filters, options...)
+	})
+}
+
+// Min 返回指定字段的最小值，没有记录时返回 nil。
+func (e *ErrorModel[T]) Min(ctx context.Context, column string, filters any, options ...map[string]any) (any, error) {
+	return try(func() any {
+		return e.model.Min(ctx, column, filters, options...)
+	})
+}
+
+// Avg 返回指定字段的平均值。
+func (e *ErrorModel[T]) Avg(ctx context.Context, column string, filters any, options ...map[string]any) (float64, error) {
+	return try(func() float64 {
+		return e.model.Avg(ctx, column, filters, options...)
+	})
+}
+
+// CountDistinct 统计指定字段去重后的数量。
+func (e *ErrorModel[T]) CountDistinct(ctx context.Context, column string, filters any, options ...map[string]any) (int64, error) {
+	return try(func() int64 {
+		return e.model.CountDistinct(ctx, column, filters, options...)
+	})
+}
+
+// GroupBy 分组聚合查询，参数与 Model.GroupBy 一致。
+func (e *ErrorModel[T]) GroupBy(ctx context.Context, groupColumns any, aggregates map[string]any, filters any, options ...map[string]any) ([]map[string]any, error) {
+	return try(func() []map[string]any {
+		return e.model.GroupBy(ctx, groupColumns, aggregates, filters, options...)
+	})
+}
+
+// Query 创建链式查询，执行方法返回 error。
+func (e *ErrorModel[T]) Query() *ErrorQuery[T] {
+	return e.model.Query().E()
+}
+
+// Insert 插入数据，返回自增主键。
+func (e *ErrorModel[T]) Insert(ctx context.Context, data map[string]any) (int64, error) {
+	return try(func() int64 {
+		return e.model.Insert(ctx, data)
+	})
+}
+
+// InsertMany 批量插入数据，返回每行的主键。
+func (e *ErrorModel[T]) InsertMany(ctx context.Context, rows []map[string]any, chunkSize int) ([]int64, error) {
+	return try(func() []int64 {
+		return e.model.InsertMany(ctx, rows, chunkSize)
+	})
+}
+
+// Upsert 批量插入，冲突时更新指定字段。
+func (e *ErrorModel[T]) Upsert(ctx context.Context, rows []map[string]any, conflictColumns []string, updateColumns []string) (int64, error) {
+	return try(func() int64 {
+		return e.model.Upsert(ctx, rows, conflictColumns, updateColumns)
+	})
+}
+
+// Update 根据条件更新数据，乐观锁冲突时返回 ErrVersionConflict。
+func (e *ErrorModel[T]) Update(ctx context.Context, filters any, data map[string]any, optimistic ...bool) (int64, error) {
+	return try(func() int64 {
+		return e.model.Update(ctx, filters, data, optimistic...)
+	})
+}
+
+// Delete 根据条件删除数据。
+func (e *ErrorModel[T]) Delete(ctx context.Context, filters any) (int64, error) {
+	return try(func() int64 {
+		return e.model.Delete(ctx, filters)
+	})
+}
+
+// Restore 恢复软删除的记录。
+func (e *ErrorModel[T]) Restore(ctx context.Context, filters any) (int64, error) {
+	return try(func() int64 {
+		return e.model.Restore(ctx, filters)
+	})
+}
+
+// ForceDelete 物理删除记录。
+func (e *ErrorModel[T]) ForceDelete(ctx context.Context, filters any) (int64, error) {
+	return try(func() int64 {
+		return e.model.ForceDelete(ctx, filters)
+	})
+}
+
+// ErrorQuery 是 Query 的 error 返回视图，通过 Query.E() 或 ErrorModel.Query() 获取，提供与 Query 相同的方法；
+// 构造错误在执行时返回，也可作为另一个查询的子查询。
+type ErrorQuery[T any] struct {
+	query *Query[T]
+}
+
+// E 返回不 panic 的查询视图。
+func (q *Query[T]) E() *ErrorQuery[T] {
+	return &ErrorQuery[T]{query: q}
+}
+
+// Where 追加 column op value 条件。
+func (e *ErrorQuery[T]) Where(column, op string, value any) *ErrorQuery[T] {
+	e.query.Where(column, op, value)
+	return e
+}
+
+// OrWhere 开启新的 OR 分支并追加条件。
+func (e *ErrorQuery[T]) OrWhere(column, op string, value any) *ErrorQuery[T] {
+	e.query.OrWhere(column, op, value)
+	return e
+}
+
+// WhereColumn 比较两列。
+func (e *ErrorQuery[T]) WhereColumn(column, op, other string) *ErrorQuery[T] {
+	e.query.WhereColumn(column, op, other)
+	return e
+}
+
+// WhereExists 追加 EXISTS 子查询条件。
+func (e *ErrorQuery[T]) WhereExists(sub subquerySource) *ErrorQuery[T] {
+	e.query.WhereExists(sub)
+	return e
+}
+
+// WhereNotExists 追加 NOT EXISTS 子查询条件。
+func (e *ErrorQuery[T]) WhereNotExists(sub subquerySource) *ErrorQuery[T] {
+	e.query.WhereNotExists(sub)
+	return e
+}
+
+// WhereGroup 将 fn 中追加的条件作为一个整体以 AND 连接。
+func (e *ErrorQuery[T]) WhereGroup(fn func(*Query[T])) *ErrorQuery[T] {
+	e.query.WhereGroup(fn)
+	return e
+}
+
+// OrWhereGroup 将 fn 中追加的条件作为一个整体开启新的 OR 分支。
+func (e *ErrorQuery[T]) OrWhereGroup(fn func(*Query[T])) *ErrorQuery[T] {
+	e.query.OrWhereGroup(fn)
+	return e
+}
+
+// Select 指定查询列。
+func (e *ErrorQuery[T]) Select(columns ...string) *ErrorQuery[T] {
+	e.query.Select(columns...)
+	return e
+}
+
+// Join 内连接 table。
+func (e *ErrorQuery[T]) Join(table, on string) *ErrorQuery[T] {
+	e.query.Join(table, on)
+	return e
+}
+
+// LeftJoin 左连接 table。
+func (e *ErrorQuery[T]) LeftJoin(table, on string) *ErrorQuery[T] {
+	e.query.LeftJoin(table, on)
+	return e
+}
+
+// OrderBy 追加排序。
+func (e *ErrorQuery[T]) OrderBy(column string, direction Direction) *ErrorQuery[T] {
+	e.query.OrderBy(column, direction)
+	return e
+}
+
+// Limit 限制返回行数。
+func (e *ErrorQuery[T]) Limit(limit int) *ErrorQuery[T] {
+	e.query.Limit(limit)
+	return e
+}
+
+// Page 按页码与每页数量分页。
+func (e *ErrorQuery[T]) Page(page, size int) *ErrorQuery[T] {
+	e.query.Page(page, size)
+	return e
+}
+
+// With 预加载关联。
+func (e *ErrorQuery[T]) With(relations ...string) *ErrorQuery[T] {
+	e.query.With(relations...)
+	return e
+}
+
+// Err 返回构造过程中的第一个错误。
+func (e *ErrorQuery[T]) Err() error {
+	return e.query.Err()
+}
+
+func (e *ErrorQuery[T]) buildSubquery(ctx context.Context, alias string, depth int, single bool) (subQuery, string, error) {
+	return e.query.buildSubquery(ctx, alias, depth, single)
+}
+
+// All 执行查询并返回全部结果。
+func (e *ErrorQuery[T]) All(ctx context.Context) ([]*T, error) {
+	return try(func() []*T {
+		return e.query.All(ctx)
+	})
+}
+
+// First 返回第一条结果，没有结果时返回 ErrNotFound。
+func (e *ErrorQuery[T]) First(ctx context.Context) (*T, error) {
+	result, err := try(func() *T {
+		return e.query.First(ctx)
+	})
+	if err == nil && result == nil {
+		return nil, ErrNotFound
+	}
+	return result, err
+}
+
+// Maps 执行查询并返回 map 结果。
+func (e *ErrorQuery[T]) Maps(ctx context.Context) ([]map[string]any, error) {
+	return try(func() []map[string]any {
+		return e.query.Maps(ctx)
+	})
+}
+
+// Count 统计满足条件的行数。
+func (e *ErrorQuery[T]) Count(ctx context.Context) (int64, error) {
+	return try(func() int64 {
+		return e.query.Count(ctx)
+	})
+}
+
+// ToSQL 返回将要执行的 SQL 与参数。
+func (e *ErrorQuery[T]) ToSQL(ctx context.Context) (string, []any, error) {
+	return e.query.ToSQL(ctx)
+}
+
+// try 执行会 panic 的 ORM 调用并将 panic 转为归一化后的 error；运行时错误（空指针等）继续向上抛出。
+func try[R any](fn func() R) (result R, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = recoveredError(r)
+		}
+	}()
+	return fn(), nil
+}
+
+func recoveredError(r any) error {
+	var runtimeErr runtime.Error
+	switch value := r.(type) {
+	case error:
+		if errors.As(value, &runtimeErr) {
+			panic(r)
+		}
+		return normalizeError(value)
+	case string:
+		return errors.New(value)
+	default:
+		return fmt.Errorf("orm: %v", value)
+	}
+}
@@ -0,0 +1,100 @@
+package orm_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/shemic/dever/orm"
+	"github.com/shemic/dever/orm/ormtest"
+)
+
+type tryAccount struct {
+	ID   uint64 `dorm:"primaryKey;autoIncrement"`
+	Code string `dorm:"type:varchar(32);unique"`
+}
+
+type tryOrder struct {
+	ID           uint64 `dorm:"primaryKey;autoIncrement"`
+	TryAccountID uint64 `dorm:"fk:try_account.id"`
+	Amount       int
+}
+
+var (
+	tryAccounts = ormtest.Register[tryAccount]("try_account", orm.ModelConfig{})
+	tryOrders   = ormtest.Register[tryOrder]("try_order", orm.ModelConfig{})
+)
+
+// savepoint 在嵌套事务中执行 fn，失败的语句不会中止 Setup 的外层事务。
+func savepoint(ctx context.Context, fn func(context.Context) error) error {
+	return orm.Transaction(ctx, fn)
+}
+
+func TestConstraintViolationsMapToTypedErrors(t *testing.T) {
+	ctx := ormtest.Setup(t)
+	account := tryAccounts().Insert(ctx, map[string]any{"code": "a"})
+
+	err := savepoint(ctx, func(ctx context.Context) error {
+		_, err := tryAccounts().E().Insert(ctx, map[string]any{"code": "a"})
+		return err
+	})
+	if !errors.Is(err, orm.ErrUniqueViolation) {
+		t.Fatalf("duplicate insert err = %v, want ErrUniqueViolation", err)
+	}
+
+	err = savepoint(ctx, func(ctx context.Context) error {
+		_, err := tryOrders().E().Insert(ctx, map[string]any{"try_account_id": account + 100, "amount": 1})
+		return err
+	})
+	if !errors.Is(err, orm.ErrForeignKeyViolation) {
+		t.Fatalf("dangling insert err = %v, want ErrForeignKeyViolation", err)
+	}
+
+	tryOrders().Insert(ctx, map[string]any{"try_account_id": account, "amount": 1})
+	err = savepoint(ctx, func(ctx context.Context) error {
+		_, err := tryAccounts().E().Delete(ctx, map[string]any{"id": account})
+		return err
+	})
+	if !errors.Is(err, orm.ErrForeignKeyViolation) {
+		t.Fatalf("delete referenced err = %v, want ErrForeignKeyViolation", err)
+	}
+}
+
+func TestErrorQueryReturnsErrors(t *testing.T) {
+	ctx := ormtest.Setup(t)
+	account := tryAccounts().Insert(ctx, map[string]any{"code": "b"})
+	tryOrders().InsertMany(ctx, []map[string]any{
+		{"try_account_id": account, "amount": 3},
+		{"try_account_id": account, "amount": 7},
+	}, 0)
+
+	rows, err := tryOrders().E().Query().
+		Where("try_account_id", "=", account).
+		OrderBy("amount", orm.Desc).
+		Limit(1).
+		All(ctx)
+	if err != nil || len(rows) != 1 || rows[0].Amount != 7 {
+		t.Fatalf("All = %v, %v", rows, err)
+	}
+
+	accounts := tryAccounts().Query().E().Select("id").Where("code", "=", "b")
+	total, err := tryOrders().E().Query().Where("try_account_id", "in", accounts).Count(ctx)
+	if err != nil || total != 2 {
+		t.Fatalf("Count with ErrorQuery subquery = %d, %v", total, err)
+	}
+
+	if _, err := tryOrders().E().Query().Where("amount", ">", 100).First(ctx); !errors.Is(err, orm.ErrNotFound) {
+		t.Fatalf("First err = %v, want ErrNotFound", err)
+	}
+
+	query := tryOrders().E().Query().Where("amount", "~~", 1)
+	if query.Err() == nil {
+		t.Fatal("invalid operator should be recorded")
+	}
+	if _, err := query.All(ctx); err == nil {
+		t.Fatal("All should return the builder error")
+	}
+	if _, err := tryOrders().E().Query().OrderBy("amount;drop", orm.Asc).Maps(ctx); err == nil {
+		t.Fatal("Maps should return the order error")
+	}
+}
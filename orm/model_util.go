@@ -20,6 +20,9 @@ import (
 type executor struct {
 	db *sqlx.DB
 	tx *sqlx.Tx
+	// replica/primary 在读操作选中副本时设置，副本连接失败时回退主库重试一次。
+	replica *replicaNode
+	primary *sqlx.DB
 }
 
 type observedRow struct {
@@ -64,6 +67,9 @@ func (e executor) selectContext(ctx context.Context, dest any, query string, arg
 		return err
 	}
 	err = e.db.SelectContext(ctx, dest, query, args...)
+	if primary, ok := e.fallback(ctx, err); ok {
+		err = primary.SelectContext(ctx, dest, query, args...)
+	}
 	return err
 }
 
@@ -77,6 +83,9 @@ func (e executor) queryxContext(ctx context.Context, query string, args ...any)
 		return rows, err
 	}
 	rows, err = e.db.QueryxContext(ctx, query, args...)
+	if primary, ok := e.fallback(ctx, err); ok {
+		rows, err = primary.QueryxContext(ctx, query, args...)
+	}
 	return rows, err
 }
 
@@ -87,6 +96,9 @@ func (e executor) queryRowxContext(ctx context.Context, query string, args ...an
 		row = e.tx.QueryRowxContext(ctx, query, args...)
 	} else {
 		row = e.db.QueryRowxContext(ctx, query, args...)
+		if primary, ok := e.fallback(ctx, row.Err()); ok {
+			row = primary.QueryRowxContext(ctx, query, args...)
+		}
 	}
 	return &observedRow{row: row, span: span}
 }
@@ -207,6 +219,14 @@ func ensureIntoDest(dest any) error {
 }
 
 func buildJoinClause(raw any) string {
+	return buildJoinClauseWithAlias(raw, "")
+}
+
+// buildJoinClauseWithAlias 以 aliasPrefix + 序号作为 join 别名，为空时使用 t。
+func buildJoinClauseWithAlias(raw any, aliasPrefix string) string {
+	if aliasPrefix == "" {
+		aliasPrefix = "t"
+	}
 	switch v := raw.(type) {
 	case []any:
 		var builder strings.Builder
@@ -216,14 +236,14 @@ func buildJoinClause(raw any) string {
 			if !ok {
 				continue
 			}
-			written = appendJoinClause(&builder, written, idx, m)
+			written = appendJoinClause(&builder, written, aliasPrefix, idx, m)
 		}
 		return builder.String()
 	case []map[string]any:
 		var builder strings.Builder
 		written := 0
 		for idx := range v {
-			written = appendJoinClause(&builder, written, idx, v[idx])
+			written = appendJoinClause(&builder, written, aliasPrefix, idx, v[idx])
 		}
 		return builder.String()
 	default:
@@ -235,7 +255,7 @@ func normalizeJoinType(raw string) string {
 	return safeJoinType(raw)
 }
 
-func appendJoinClause(builder *strings.Builder, written, idx int, m map[string]any) int {
+func appendJoinClause(builder *strings.Builder, written int, aliasPrefix string, idx int, m map[string]any) int {
 	table := strings.TrimSpace(firstNonEmptyString(m, "table", "name"))
 	if table == "" || ensureIdentifier(table) != nil {
 		return written
@@ -254,7 +274,8 @@ func appendJoinClause(builder *strings.Builder, written, idx int, m map[string]a
 	builder.WriteString(joinType)
 	builder.WriteByte(' ')
 	builder.WriteString(table)
-	builder.WriteString(" AS t")
+	builder.WriteString(" AS ")
+	builder.WriteString(aliasPrefix)
 	builder.WriteString(strconv.Itoa(idx))
 	builder.WriteString(" ON ")
 	builder.WriteString(onClause)
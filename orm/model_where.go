@@ -1,6 +1,7 @@
 package orm
 
 import (
+	"errors"
 	"fmt"
 	"reflect"
 	"sort"
@@ -82,6 +83,8 @@ func parseConditionMap(m map[string]any, glue string, quoter func(string) string
 				return parseCondition(value, "AND", quoter)
 			case "or", "||":
 				return parseCondition(value, "OR", quoter)
+			case "exists", "not exists":
+				return buildExistsClause(lower, value)
 			default:
 				return parseFieldCondition(key, value, quoter)
 			}
@@ -110,6 +113,12 @@ func parseConditionMap(m map[string]any, glue string, quoter func(string) string
 				clauses = append(clauses, fmt.Sprintf("(%s)", clause))
 				args = append(args, subArgs...)
 			}
+		case "exists", "not exists":
+			clause, subArgs := buildExistsClause(lower, value)
+			if clause != "" {
+				clauses = append(clauses, clause)
+				args = append(args, subArgs...)
+			}
 		default:
 			clause, subArgs := parseFieldCondition(key, value, quoter)
 			if clause == "" {
@@ -189,6 +198,13 @@ func buildComparisonClause(field, op string, value any, quoter func(string) stri
 		op = "="
 	}
 	quotedField := quoteQualified(field, quoter)
+	panicOnError(checkOperandOperator(field, op, value))
+	switch v := value.(type) {
+	case subQuery:
+		return fmt.Sprintf("%s %s (%s)", quotedField, op, v.query), v.args
+	case columnRef:
+		return fmt.Sprintf("%s %s %s", quotedField, op, quoteQualified(string(v), quoter)), nil
+	}
 	switch op {
 	case "IN", "NOT IN":
 		slice, ok := valueSlice(value)
@@ -225,6 +241,48 @@ func buildComparisonClause(field, op string, value any, quoter func(string) stri
 	}
 }
 
+// checkOperandOperator 拒绝子查询与列引用不支持的运算符，避免条件被静默丢弃：
+// 子查询不能用于 BETWEEN/LIKE/IS，列引用不能用于 IN/BETWEEN/IS。
+func checkOperandOperator(field, op string, value any) error {
+	switch v := value.(type) {
+	case subQuery, subquerySource:
+		switch op {
+		case "BETWEEN", "LIKE", "NOT LIKE", "IS", "IS NOT":
+			return fmt.Errorf("orm: operator %s on %s does not support a subquery", op, field)
+		}
+	case columnRef:
+		switch op {
+		case "IN", "NOT IN", "BETWEEN", "IS", "IS NOT":
+			return fmt.Errorf("orm: operator %s on %s does not support column %s", op, field, string(v))
+		}
+	}
+	return nil
+}
+
+// subQuery 是由 Query 构造器生成的子查询，只能由 orm 内部创建，不接受外部拼接的 SQL。
+type subQuery struct {
+	query string
+	args  []any
+}
+
+// MarshalJSON 始终失败：结果依赖其他表，含子查询的查询不参与模型缓存。
+func (subQuery) MarshalJSON() ([]byte, error) {
+	return nil, errSubQueryNotCacheable
+}
+
+var errSubQueryNotCacheable = errors.New("orm: subquery is not cacheable")
+
+// columnRef 表示与另一列比较（如关联子查询中的 main.id），按标识符引用而非参数绑定。
+type columnRef string
+
+func buildExistsClause(op string, value any) (string, []any) {
+	sub, ok := value.(subQuery)
+	if !ok {
+		return "", nil
+	}
+	return fmt.Sprintf("%s (%s)", strings.ToUpper(op), sub.query), sub.args
+}
+
 func normalizeComparisonOperator(op string) string {
 	normalized := strings.TrimSpace(strings.ToUpper(op))
 	switch normalized {
@@ -0,0 +1,62 @@
+package ormtest
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// AssertRowExists 断言表中至少存在一行满足 where（列 = 值，nil 表示 IS NULL）。
+func AssertRowExists(t testing.TB, ctx context.Context, table string, where map[string]any) {
+	t.Helper()
+	if count(t, ctx, table, where) == 0 {
+		t.Errorf("ormtest: no row in %s matches %v", table, where)
+	}
+}
+
+// AssertCount 断言表中满足 where 的行数等于 want，where 为空时统计全表。
+func AssertCount(t testing.TB, ctx context.Context, table string, where map[string]any, want int64) {
+	t.Helper()
+	if got := count(t, ctx, table, where); got != want {
+		t.Errorf("ormtest: %s rows matching %v = %d, want %d", table, where, got, want)
+	}
+}
+
+// count 直接在 Setup 的事务中查询，不受软删除、租户等模型作用域影响。
+func count(t testing.TB, ctx context.Context, table string, where map[string]any) int64 {
+	t.Helper()
+	tx := txFrom(t, ctx)
+	if err := ensureIdentifier(table); err != nil {
+		t.Fatalf("ormtest: %v", err)
+	}
+	columns := make([]string, 0, len(where))
+	for column := range where {
+		if err := ensureIdentifier(column); err != nil {
+			t.Fatalf("ormtest: %v", err)
+		}
+		columns = append(columns, column)
+	}
+	sort.Strings(columns)
+	conditions := make([]string, 0, len(columns))
+	args := make([]any, 0, len(columns))
+	for _, column := range columns {
+		value := where[column]
+		if value == nil {
+			conditions = append(conditions, quote(tx, column)+" IS NULL")
+			continue
+		}
+		conditions = append(conditions, quote(tx, column)+" = ?")
+		args = append(args, value)
+	}
+	query := fmt.Sprintf("SELECT COUNT(*) FROM %s", quote(tx, table))
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	var total int64
+	if err := tx.GetContext(ctx, &total, tx.Rebind(query), args...); err != nil {
+		t.Fatalf("ormtest: count %s: %v", table, err)
+	}
+	return total
+}
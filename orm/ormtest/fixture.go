@@ -0,0 +1,128 @@
+package ormtest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+	"gopkg.in/yaml.v3"
+)
+
+// LoadFixtures 在 Setup 返回的事务中写入 fixture 文件（.yaml/.yml/.json），按文件及表出现的顺序插入：
+//
+//	user:
+//	  - {id: 1, name: alice}
+//	order:
+//	  - {id: 1, user_id: 1, items: [{sku: a1}]}
+//
+// 键为完整表名（含前缀），map/数组类型的值按 JSON 写入；数据直接以 SQL 插入，不经过模型钩子、密码哈希与租户校验。
+func LoadFixtures(t testing.TB, ctx context.Context, paths ...string) {
+	t.Helper()
+	if len(paths) == 0 {
+		return
+	}
+	tx := txFrom(t, ctx)
+	for _, path := range paths {
+		tables, err := readFixture(path)
+		if err != nil {
+			t.Fatalf("ormtest: fixture %s: %v", path, err)
+		}
+		for _, table := range tables {
+			for _, row := range table.rows {
+				if err := insertRow(ctx, tx, table.name, row); err != nil {
+					t.Fatalf("ormtest: fixture %s: insert %s: %v", path, table.name, err)
+				}
+			}
+		}
+	}
+}
+
+type fixtureTable struct {
+	name string
+	rows []map[string]any
+}
+
+func readFixture(path string) ([]fixtureTable, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml", ".json":
+	default:
+		return nil, fmt.Errorf("unsupported fixture format")
+	}
+	// JSON 是 YAML 的子集，统一按 YAML 节点解析以保留表的书写顺序。
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	if len(doc.Content) == 0 {
+		return nil, nil
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("fixture must be a mapping of table to rows")
+	}
+	tables := make([]fixtureTable, 0, len(root.Content)/2)
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		name := root.Content[i].Value
+		if err := ensureIdentifier(name); err != nil {
+			return nil, err
+		}
+		var rows []map[string]any
+		if err := root.Content[i+1].Decode(&rows); err != nil {
+			return nil, fmt.Errorf("table %s: %w", name, err)
+		}
+		tables = append(tables, fixtureTable{name: name, rows: rows})
+	}
+	return tables, nil
+}
+
+func insertRow(ctx context.Context, tx *sqlx.Tx, table string, row map[string]any) error {
+	if len(row) == 0 {
+		return nil
+	}
+	columns := make([]string, 0, len(row))
+	for column := range row {
+		if err := ensureIdentifier(column); err != nil {
+			return err
+		}
+		columns = append(columns, column)
+	}
+	sort.Strings(columns)
+	quoted := make([]string, len(columns))
+	holders := make([]string, len(columns))
+	args := make([]any, len(columns))
+	for i, column := range columns {
+		quoted[i] = quote(tx, column)
+		holders[i] = "?"
+		value, err := fixtureValue(row[column])
+		if err != nil {
+			return fmt.Errorf("column %s: %w", column, err)
+		}
+		args[i] = value
+	}
+	query := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)", quote(tx, table), strings.Join(quoted, ", "), strings.Join(holders, ", "))
+	_, err := tx.ExecContext(ctx, tx.Rebind(query), args...)
+	return err
+}
+
+func fixtureValue(value any) (any, error) {
+	switch value.(type) {
+	case map[string]any, []any:
+		data, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+		return string(data), nil
+	default:
+		return value, nil
+	}
+}
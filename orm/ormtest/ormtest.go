@@ -0,0 +1,198 @@
+// Package ormtest 为使用 orm/load.Model 的业务代码提供测试环境：内存 SQLite（或通过 DSN 指定的 mysql/postgres）、
+// 自动建表与种子数据、fixture 加载、每个测试独立事务并在结束时回滚，以及常用断言。
+//
+//	import _ "example.com/app/data/load" // 注册项目模型
+//
+//	func TestCreateOrder(t *testing.T) {
+//		ctx := ormtest.Setup(t, ormtest.Options{Fixtures: []string{"testdata/user.yaml"}})
+//		service.CreateOrder(ctx, 1)
+//		ormtest.AssertCount(t, ctx, "order", map[string]any{"user_id": 1}, 1)
+//	}
+package ormtest
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/shemic/dever/config"
+	"github.com/shemic/dever/load"
+	"github.com/shemic/dever/orm"
+)
+
+// Options 配置测试数据库。Driver/DSN 为空时读取环境变量 DEVER_TEST_DRIVER / DEVER_TEST_DSN，
+// 仍为空则使用内存 SQLite；Config 不为空时优先使用，适用于需要自动建库的 mysql/postgres。
+type Options struct {
+	Driver   string
+	DSN      string
+	Config   *orm.Config
+	Fixtures []string
+}
+
+type txContextKey struct{}
+
+var (
+	initOnce    sync.Once
+	initErr     error
+	defaultName string
+)
+
+// Setup 初始化测试数据库（整个测试进程只执行一次，需早于任何模型加载），开启事务并在 t.Cleanup 中回滚，
+// 返回绑定该事务的上下文；被测代码需使用该上下文访问数据库，数据才会随测试回滚。
+// 进程内只有第一次调用的连接选项生效，Fixtures 每次调用都会在当前事务中加载。
+// 内存 SQLite 同一时间只允许一个写事务，使用 Setup 的测试不要调用 t.Parallel。
+func Setup(t testing.TB, options ...Options) context.Context {
+	t.Helper()
+	var opt Options
+	if len(options) > 0 {
+		opt = options[0]
+	}
+	initOnce.Do(func() {
+		initErr = initialize(opt)
+	})
+	if initErr != nil {
+		t.Fatalf("ormtest: %v", initErr)
+	}
+
+	ctx, tx, err := orm.BeginTx(context.Background(), defaultName)
+	if err != nil {
+		t.Fatalf("ormtest: begin transaction: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = tx.Rollback()
+	})
+	ctx = context.WithValue(ctx, txContextKey{}, tx)
+	LoadFixtures(t, ctx, opt.Fixtures...)
+	return ctx
+}
+
+func initialize(opt Options) error {
+	name, extra := configuredDatabases()
+	orm.DisableConfigInit()
+	orm.SetDefaultDatabase(name)
+	orm.EnableAutoMigrate(true)
+	orm.EnableSchemaPersistence(false)
+	orm.EnableMigrationLog(false)
+	defaultName = name
+
+	cfg := resolveConfig(opt, name)
+	if _, err := orm.Init(name, cfg); err != nil {
+		return err
+	}
+	// 其他配置的连接各自使用独立的内存库，不参与事务回滚。
+	for _, other := range extra {
+		if _, err := orm.Init(other, memoryConfig(other)); err != nil {
+			return err
+		}
+	}
+	return loadModels()
+}
+
+// configuredDatabases 从项目配置（向上查找 config/setting.json）读取默认连接名与其他连接名。
+func configuredDatabases() (string, []string) {
+	dir, err := os.Getwd()
+	if err != nil {
+		return "default", nil
+	}
+	for {
+		path := filepath.Join(dir, config.DefaultPath)
+		if cfg, err := config.Load(path); err == nil {
+			name := strings.TrimSpace(cfg.Database.Default)
+			if name == "" {
+				name = "default"
+			}
+			var extra []string
+			for other := range cfg.Database.Connections {
+				if other = strings.TrimSpace(other); other != "" && other != name {
+					extra = append(extra, other)
+				}
+			}
+			sort.Strings(extra)
+			return name, extra
+		}
+		if _, err := os.Stat(filepath.Join(dir, "go.mod")); err == nil {
+			return "default", nil
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "default", nil
+		}
+		dir = parent
+	}
+}
+
+func resolveConfig(opt Options, name string) orm.Config {
+	if opt.Config != nil {
+		return *opt.Config
+	}
+	driver := strings.TrimSpace(opt.Driver)
+	dsn := strings.TrimSpace(opt.DSN)
+	if driver == "" && dsn == "" {
+		driver = strings.TrimSpace(os.Getenv("DEVER_TEST_DRIVER"))
+		dsn = strings.TrimSpace(os.Getenv("DEVER_TEST_DSN"))
+	}
+	if dsn == "" {
+		return memoryConfig(name)
+	}
+	if driver == "" {
+		driver = "sqlite"
+	}
+	return orm.Config{Driver: driver, DSN: dsn}
+}
+
+func memoryConfig(name string) orm.Config {
+	return orm.Config{
+		Driver: "sqlite",
+		// 共享缓存让连接池中的连接访问同一个内存库；保留空闲连接避免内存库随最后一个连接关闭而销毁。
+		DSN:          fmt.Sprintf("file:ormtest_%s?mode=memory&cache=shared&_foreign_keys=1&_busy_timeout=5000", name),
+		MaxIdleConns: 8,
+	}
+}
+
+// loadModels 加载 load 中注册的全部模型，模型加载时按表结构建表并写入种子数据。
+func loadModels() (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("load models: %v", r)
+		}
+	}()
+	for _, name := range load.Models() {
+		load.Model(name)
+	}
+	return orm.EnsureCachedSchemas(context.Background())
+}
+
+func txFrom(t testing.TB, ctx context.Context) *sqlx.Tx {
+	t.Helper()
+	tx, ok := ctx.Value(txContextKey{}).(*sqlx.Tx)
+	if !ok {
+		t.Fatalf("ormtest: context is not created by ormtest.Setup")
+	}
+	return tx
+}
+
+func quote(tx *sqlx.Tx, name string) string {
+	if tx.DriverName() == "mysql" {
+		return "`" + name + "`"
+	}
+	return `"` + name + `"`
+}
+
+func ensureIdentifier(name string) error {
+	if name == "" {
+		return fmt.Errorf("identifier cannot be empty")
+	}
+	for _, r := range name {
+		if !(r == '_' || r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z') {
+			return fmt.Errorf("invalid identifier %q", name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package ormtest_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/shemic/dever/orm"
+	"github.com/shemic/dever/orm/ormtest"
+)
+
+type parent struct {
+	ID   uint64 `dorm:"primaryKey;autoIncrement"`
+	Name string `dorm:"type:varchar(32)"`
+}
+
+type child struct {
+	ID       uint64 `dorm:"primaryKey;autoIncrement"`
+	ParentID uint64 `dorm:"fk:ormtest_parent.id"`
+	Name     string `dorm:"type:varchar(32)"`
+	Tags     string `dorm:"type:text"`
+}
+
+var (
+	parents  = ormtest.Register[parent]("ormtest_parent", orm.ModelConfig{})
+	children = ormtest.Register[child]("ormtest_child", orm.ModelConfig{})
+)
+
+func TestSetupRollsBackBetweenTests(t *testing.T) {
+	t.Run("write", func(t *testing.T) {
+		ctx := ormtest.Setup(t)
+		id := parents().Insert(ctx, map[string]any{"name": "carol"})
+		if id == 0 {
+			t.Fatal("insert returned no id")
+		}
+		ormtest.AssertCount(t, ctx, "ormtest_parent", nil, 1)
+		ormtest.AssertRowExists(t, ctx, "ormtest_parent", map[string]any{"id": id, "name": "carol"})
+	})
+	t.Run("read", func(t *testing.T) {
+		ctx := ormtest.Setup(t)
+		ormtest.AssertCount(t, ctx, "ormtest_parent", nil, 0)
+	})
+	if got := parents().Count(context.Background(), nil); got != 0 {
+		t.Fatalf("rows outside test transaction = %d, want 0", got)
+	}
+}
+
+func TestFixturesLoadInFileOrder(t *testing.T) {
+	ctx := ormtest.Setup(t, ormtest.Options{Fixtures: []string{"testdata/parent.yaml", "testdata/child.json"}})
+	ormtest.AssertCount(t, ctx, "ormtest_parent", nil, 2)
+	ormtest.AssertCount(t, ctx, "ormtest_child", nil, 2)
+	ormtest.AssertRowExists(t, ctx, "ormtest_child", map[string]any{"id": 2, "parent_id": 2, "tags": `["x","y"]`})
+
+	row := children().Find(ctx, map[string]any{"id": 1})
+	if row == nil || row.ParentID != 1 || row.Name != "a1" {
+		t.Fatalf("child 1 = %+v", row)
+	}
+}
@@ -0,0 +1,23 @@
+package ormtest
+
+import (
+	"github.com/shemic/dever/load"
+	"github.com/shemic/dever/orm"
+)
+
+// Register 为测试声明一个模型：在 load 中注册 <table>Model 并返回加载该模型的函数，
+// Setup 初始化时与其他注册模型一起建表。需在包级变量中调用（早于 Setup），返回的函数可在测试中反复调用。
+//
+//	var users = ormtest.Register[User]("user", orm.ModelConfig{})
+//
+//	func TestUser(t *testing.T) {
+//		ctx := ormtest.Setup(t)
+//		users().Insert(ctx, map[string]any{"name": "alice"})
+//	}
+func Register[T any](table string, config orm.ModelConfig) func() *orm.Model[T] {
+	model := func() *orm.Model[T] {
+		return orm.LoadModel[T](table, table, config)
+	}
+	load.Register("ormtest."+table+"Model", model)
+	return model
+}
@@ -0,0 +1,228 @@
+package orm
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jmoiron/sqlx"
+	"github.com/mattn/go-sqlite3"
+)
+
+// 只读副本：Select/Find/Count/Sum 等读操作轮询分发到健康的副本，
+// 写操作、FOR UPDATE、事务内查询以及 UsePrimary 标记的上下文始终走主库。
+// 副本上的查询遇到连接类错误时标记该副本不可用（等到下一次健康检查再恢复），并在主库上重试一次。
+
+// replicaCheckInterval 为副本健康检查的最小间隔，间隔内复用上一次 Ping 结果。
+const replicaCheckInterval = 5 * time.Second
+
+type primaryContextKey struct{}
+
+type replicaSet struct {
+	nodes []*replicaNode
+	next  atomic.Uint64
+}
+
+type replicaNode struct {
+	db        *sqlx.DB
+	timeout   time.Duration
+	healthy   atomic.Bool
+	checkedAt atomic.Int64
+	checking  sync.Mutex
+}
+
+// UsePrimary 返回强制读主库的上下文，用于写后立即读取等对一致性敏感的场景。
+func UsePrimary(ctx context.Context) context.Context {
+	return context.WithValue(normalizeContext(ctx), primaryContextKey{}, true)
+}
+
+func usePrimaryFromContext(ctx context.Context) bool {
+	if ctx == nil {
+		return false
+	}
+	flag, _ := ctx.Value(primaryContextKey{}).(bool)
+	return flag
+}
+
+// openReplicas 打开主库配置中的只读副本；副本启动时 Ping 失败只标记为不可用，不影响主库初始化。
+func openReplicas(name string, cfg Config) (*replicaSet, error) {
+	if len(cfg.Replicas) == 0 {
+		return nil, nil
+	}
+	set := &replicaSet{}
+	for i, replicaCfg := range cfg.Replicas {
+		replicaCfg = inheritReplicaConfig(replicaCfg, cfg)
+		dsn, err := replicaCfg.buildDSN()
+		if err != nil {
+			_ = set.close()
+			return nil, fmt.Errorf("orm: build dsn for %q replica %d failed: %w", name, i, err)
+		}
+		db, err := openDatabase(replicaCfg.driverName(), dsn, replicaCfg)
+		if err != nil {
+			_ = set.close()
+			return nil, fmt.Errorf("orm: open %q replica %d failed: %w", name, i, err)
+		}
+		node := &replicaNode{db: db, timeout: timeoutOrDefault(replicaCfg.HealthCheckPeriod)}
+		node.healthy.Store(pingDatabase(db, replicaCfg) == nil)
+		node.checkedAt.Store(time.Now().UnixNano())
+		set.nodes = append(set.nodes, node)
+	}
+	return set, nil
+}
+
+// inheritReplicaConfig 让只读副本沿用主库未单独配置的连接参数；setting.json 与 orm.Config 配置的副本都经过这里。
+func inheritReplicaConfig(replica, primary Config) Config {
+	if strings.TrimSpace(replica.Driver) == "" {
+		replica.Driver = primary.Driver
+	}
+	if strings.TrimSpace(replica.Host) == "" && replica.driverName() != "sqlite3" {
+		replica.Host = primary.Host
+	}
+	if strings.TrimSpace(replica.User) == "" {
+		replica.User = primary.User
+		if replica.Password == "" {
+			replica.Password = primary.Password
+		}
+	}
+	if strings.TrimSpace(replica.DBName) == "" {
+		replica.DBName = primary.DBName
+	}
+	if replica.Params == nil {
+		replica.Params = primary.Params
+	}
+	if replica.MaxOpenConns <= 0 {
+		replica.MaxOpenConns = primary.MaxOpenConns
+	}
+	if replica.MaxIdleConns <= 0 {
+		replica.MaxIdleConns = primary.MaxIdleConns
+	}
+	if replica.ConnMaxLifetime <= 0 {
+		replica.ConnMaxLifetime = primary.ConnMaxLifetime
+	}
+	if replica.ConnMaxIdleTime <= 0 {
+		replica.ConnMaxIdleTime = primary.ConnMaxIdleTime
+	}
+	if replica.HealthCheckPeriod <= 0 {
+		replica.HealthCheckPeriod = primary.HealthCheckPeriod
+	}
+	replica.Prefix = primary.Prefix
+	replica.Replicas = nil
+	return replica
+}
+
+// pick 从上次位置开始轮询，返回第一个健康的副本；全部不可用时返回 nil（回退主库）。
+func (s *replicaSet) pick(ctx context.Context) *replicaNode {
+	if s == nil || len(s.nodes) == 0 {
+		return nil
+	}
+	total := uint64(len(s.nodes))
+	start := s.next.Add(1) - 1
+	for i := uint64(0); i < total; i++ {
+		node := s.nodes[(start+i)%total]
+		if node.available(ctx) {
+			return node
+		}
+	}
+	return nil
+}
+
+func (s *replicaSet) close() error {
+	if s == nil {
+		return nil
+	}
+	var errs []error
+	for _, node := range s.nodes {
+		errs = append(errs, node.db.Close())
+	}
+	return errors.Join(errs...)
+}
+
+// available 在检查间隔到期后重新 Ping 副本，并发请求只由一个协程执行检查。
+func (n *replicaNode) available(ctx context.Context) bool {
+	if time.Since(time.Unix(0, n.checkedAt.Load())) < replicaCheckInterval {
+		return n.healthy.Load()
+	}
+	if !n.checking.TryLock() {
+		return n.healthy.Load()
+	}
+	defer n.checking.Unlock()
+	pingCtx, cancel := context.WithTimeout(context.WithoutCancel(normalizeContext(ctx)), n.timeout)
+	defer cancel()
+	n.healthy.Store(n.db.PingContext(pingCtx) == nil)
+	n.checkedAt.Store(time.Now().UnixNano())
+	return n.healthy.Load()
+}
+
+func pickReplica(ctx context.Context, name string) *replicaNode {
+	if strings.TrimSpace(name) == "" {
+		name = currentDefaultDatabase()
+	}
+	mu.RLock()
+	set := replicas[name]
+	mu.RUnlock()
+	return set.pick(ctx)
+}
+
+// readExecutor 返回读操作使用的执行器：事务内、加锁查询或 UsePrimary 时走主库，否则优先选择副本。
+func (m *modelCore) readExecutor(ctx context.Context, lock bool) (context.Context, executor) {
+	ctx, exec := m.executor(ctx)
+	if exec.tx != nil || lock || usePrimaryFromContext(ctx) {
+		return ctx, exec
+	}
+	if node := pickReplica(ctx, m.dbName); node != nil {
+		exec.primary = exec.db
+		exec.db = node.db
+		exec.replica = node
+	}
+	return ctx, exec
+}
+
+// fallback 在副本查询返回连接类错误时将副本标记为不可用，并返回用于重试的主库。
+func (e executor) fallback(ctx context.Context, err error) (*sqlx.DB, bool) {
+	if e.replica == nil || e.primary == nil || !isConnectionError(err) || ctx.Err() != nil {
+		return nil, false
+	}
+	e.replica.markUnhealthy()
+	return e.primary, true
+}
+
+// markUnhealthy 将副本标记为不可用，检查间隔到期前 pick 会跳过它。
+func (n *replicaNode) markUnhealthy() {
+	n.healthy.Store(false)
+	n.checkedAt.Store(time.Now().UnixNano())
+}
+
+// isConnectionError 判断错误是否来自连接本身（断开、拒绝、已关闭等），而不是 SQL 执行失败。
+func isConnectionError(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, driver.ErrBadConn) || errors.Is(err, mysql.ErrInvalidConn) ||
+		errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, sql.ErrConnDone) {
+		return true
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+	var connectErr *pgconn.ConnectError
+	if errors.As(err, &connectErr) {
+		return true
+	}
+	var sqliteErr sqlite3.Error
+	if errors.As(err, &sqliteErr) && sqliteErr.Code == sqlite3.ErrCantOpen {
+		return true
+	}
+	// database/sql 对已关闭的连接池返回未导出的错误值，只能按文本识别。
+	return strings.Contains(err.Error(), "sql: database is closed")
+}
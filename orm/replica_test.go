@@ -0,0 +1,153 @@
+package orm
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type replicaItem struct {
+	ID   uint64 `dorm:"primaryKey;autoIncrement"`
+	Name string `dorm:"type:varchar(32)"`
+}
+
+// setupReplicas 初始化一个主库加两个副本的 sqlite 连接，每个库中 replica_item 的 name 为库名。
+func setupReplicas(t *testing.T, name string) *Model[replicaItem] {
+	t.Helper()
+	DisableConfigInit()
+	dir := t.TempDir()
+	paths := map[string]string{}
+	for _, node := range []string{"primary", "r1", "r2"} {
+		paths[node] = filepath.Join(dir, node+".db")
+		db, err := sqlx.Open("sqlite3", paths[node])
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, stmt := range []string{
+			`CREATE TABLE "replica_item" ("id" INTEGER PRIMARY KEY, "name" VARCHAR(32))`,
+			`INSERT INTO "replica_item" ("id", "name") VALUES (1, '` + node + `')`,
+		} {
+			if _, err := db.Exec(stmt); err != nil {
+				t.Fatal(err)
+			}
+		}
+		_ = db.Close()
+	}
+	_, err := Init(name, Config{
+		Driver:   "sqlite3",
+		Path:     paths["primary"],
+		Replicas: []Config{{Path: paths["r1"]}, {Path: paths["r2"]}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	config := ModelConfig{Database: name}
+	m := LoadModel[replicaItem](name, "replica_item", config)
+	// 从模型缓存移除后再关闭连接，避免同一测试进程中的 EnsureCachedSchemas 访问已关闭的库。
+	t.Cleanup(func() {
+		modelCacheMu.Lock()
+		for key, cached := range modelCache {
+			if cached == m.modelCore {
+				delete(modelCache, key)
+			}
+		}
+		modelCacheMu.Unlock()
+		_ = Close(name)
+	})
+	return m
+}
+
+func readReplicaName(ctx context.Context, m *Model[replicaItem]) string {
+	return m.Find(ctx, map[string]any{"id": 1}).Name
+}
+
+func TestReplicaReadsRoundRobinAndPrimaryRouting(t *testing.T) {
+	m := setupReplicas(t, "replica_routing")
+	ctx := context.Background()
+
+	var got []string
+	for range 4 {
+		got = append(got, readReplicaName(ctx, m))
+	}
+	if got[0] == got[1] || got[0] != got[2] || got[1] != got[3] || got[0] == "primary" || got[1] == "primary" {
+		t.Fatalf("reads = %v, want alternating replicas", got)
+	}
+
+	if name := readReplicaName(UsePrimary(ctx), m); name != "primary" {
+		t.Fatalf("UsePrimary read from %s", name)
+	}
+	err := Transaction(ctx, func(ctx context.Context) error {
+		if name := readReplicaName(ctx, m); name != "primary" {
+			t.Fatalf("transaction read from %s", name)
+		}
+		return nil
+	}, "replica_routing")
+	if err != nil {
+		t.Fatal(err)
+	}
+	primary, err := Get("replica_routing")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, exec := m.readExecutor(ctx, true); exec.db != primary || exec.replica != nil {
+		t.Fatal("locking read should use the primary")
+	}
+}
+
+func TestReplicaConnectionErrorFallsBackToPrimary(t *testing.T) {
+	m := setupReplicas(t, "replica_fallback")
+	ctx := context.Background()
+	mu.RLock()
+	set := replicas["replica_fallback"]
+	mu.RUnlock()
+	broken := set.nodes[0]
+	_ = broken.db.Close()
+
+	set.next.Store(0)
+	if name := readReplicaName(ctx, m); name != "primary" {
+		t.Fatalf("read after replica failure from %s, want primary retry", name)
+	}
+	if broken.healthy.Load() {
+		t.Fatal("failed replica should be marked unhealthy")
+	}
+	for range 3 {
+		if name := readReplicaName(ctx, m); name != "r2" {
+			t.Fatalf("read from %s, want the remaining healthy replica", name)
+		}
+	}
+	if total := m.Count(ctx, nil); total != 1 {
+		t.Fatalf("count = %d", total)
+	}
+
+	// SQL 错误不是连接错误，不会标记副本或回退主库。
+	healthy := set.nodes[1]
+	if _, err := m.E().SelectMap(ctx, nil, map[string]any{"field": "main.missing_column"}); err == nil {
+		t.Fatal("expected SQL error")
+	}
+	if !healthy.healthy.Load() {
+		t.Fatal("SQL error should not mark the replica unhealthy")
+	}
+}
+
+func TestInheritReplicaConfig(t *testing.T) {
+	primary := Config{
+		Driver: "mysql", Host: "db:3306", User: "app", Password: "secret", DBName: "shop",
+		Params: map[string]string{"charset": "utf8mb4"}, MaxOpenConns: 20, MaxIdleConns: 5,
+		ConnMaxLifetime: time.Minute, Prefix: "dv",
+	}
+	got := inheritReplicaConfig(Config{Host: "replica:3306"}, primary)
+	if got.Driver != "mysql" || got.Host != "replica:3306" || got.User != "app" || got.Password != "secret" ||
+		got.DBName != "shop" || got.Params["charset"] != "utf8mb4" || got.MaxOpenConns != 20 ||
+		got.MaxIdleConns != 5 || got.ConnMaxLifetime != time.Minute || got.Prefix != "dv" {
+		t.Fatalf("inherited config = %+v", got)
+	}
+
+	// 单独配置了用户时不沿用主库密码。
+	got = inheritReplicaConfig(Config{User: "reader", MaxOpenConns: 3}, primary)
+	if got.Host != "db:3306" || got.User != "reader" || got.Password != "" || got.MaxOpenConns != 3 {
+		t.Fatalf("override config = %+v", got)
+	}
+}
@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -24,7 +26,7 @@ func (m *modelCore) ensureSchema(ctx context.Context) error {
 		return err
 	}
 	driver := normalizeDriver(db.DriverName())
-	statements, err := syncTableSchema(ctx, db, driver, m.table, m.schema)
+	statements, err := syncTableSchema(ctx, newSchemaConn(db, false), driver, m.table, m.schema)
 	if err != nil {
 		return err
 	}
@@ -36,26 +38,33 @@ func (m *modelCore) ensureSchema(ctx context.Context) error {
 	return nil
 }
 
-func syncTableSchema(ctx context.Context, db *sqlx.DB, driver, table string, schema *tableSchema) ([]string, error) {
+func syncTableSchema(ctx context.Context, db *schemaConn, driver, table string, schema *tableSchema) ([]string, error) {
 	var statements []string
+	reset := false
 	if hasPendingSchemaReset(table) {
 		dropStmt, err := dropTableIfExists(ctx, db, driver, table)
 		if err != nil {
 			return nil, err
 		}
-		clearPendingSchemaReset(table)
+		if !db.plan {
+			clearPendingSchemaReset(table)
+		}
 		if dropStmt != "" {
 			statements = append(statements, dropStmt)
+			reset = true
 		}
 	}
 
-	exists, err := tableExists(ctx, db, driver, table)
+	exists, err := tableExists(ctx, db.DB, driver, table)
 	if err != nil {
 		return nil, err
 	}
+	if db.plan && reset {
+		exists = false
+	}
 
 	if !exists {
-		stmt, err := createTable(ctx, db, driver, table, schema.Columns)
+		stmt, err := createTable(ctx, db, driver, table, schema.Columns, schema.ForeignKeys)
 		if err != nil {
 			return nil, err
 		}
@@ -68,6 +77,12 @@ func syncTableSchema(ctx context.Context, db *sqlx.DB, driver, table string, sch
 		}
 		statements = append(statements, seedStmt...)
 	} else {
+		fkDropStmt, err := dropObsoleteForeignKeys(ctx, db, driver, table, schema.ForeignKeys)
+		if err != nil {
+			return nil, err
+		}
+		statements = append(statements, fkDropStmt...)
+
 		renameStmt, err := reconcileColumnNames(ctx, db, driver, table, schema.Columns)
 		if err != nil {
 			return nil, err
@@ -91,6 +106,14 @@ func syncTableSchema(ctx context.Context, db *sqlx.DB, driver, table string, sch
 			return nil, err
 		}
 		statements = append(statements, dropStmt...)
+
+		if driver == "sqlite" {
+			rebuildStmt, err := rebuildSQLiteForeignKeys(ctx, db, table, schema.Columns, schema.ForeignKeys)
+			if err != nil {
+				return nil, err
+			}
+			statements = append(statements, rebuildStmt...)
+		}
 	}
 
 	sequenceStmt, err := syncPostgresSeedSequences(ctx, db, driver, table, schema.Columns, schema.Seeds)
@@ -105,6 +128,12 @@ func syncTableSchema(ctx context.Context, db *sqlx.DB, driver, table string, sch
 	}
 	statements = append(statements, indexStmt...)
 
+	fkAddStmt, err := addMissingForeignKeys(ctx, db, driver, table, schema.ForeignKeys)
+	if err != nil {
+		return nil, err
+	}
+	statements = append(statements, fkAddStmt...)
+
 	return uniqueStrings(statements), nil
 }
 
@@ -144,7 +173,7 @@ func tableExists(ctx context.Context, db *sqlx.DB, driver, table string) (bool,
 	return count > 0, nil
 }
 
-func dropTableIfExists(ctx context.Context, db *sqlx.DB, driver, table string) (string, error) {
+func dropTableIfExists(ctx context.Context, db *schemaConn, driver, table string) (string, error) {
 	if err := ensureIdentifier(table); err != nil {
 		return "", err
 	}
@@ -152,7 +181,7 @@ func dropTableIfExists(ctx context.Context, db *sqlx.DB, driver, table string) (
 	if stmt == "" {
 		return "", fmt.Errorf("orm: unsupported driver %s", driver)
 	}
-	if _, err := db.ExecContext(ctx, stmt); err != nil {
+	if err := db.apply(ctx, SchemaChange{Table: table, Kind: SchemaResetTable, Up: []string{stmt}, Irreversible: true, Destructive: true}); err != nil {
 		return "", err
 	}
 	return stmt, nil
@@ -167,10 +196,21 @@ func buildDropTableStatement(driver, table string) string {
 	}
 }
 
-func createTable(ctx context.Context, db *sqlx.DB, driver, table string, columns []columnDef) (string, error) {
+func createTable(ctx context.Context, db *schemaConn, driver, table string, columns []columnDef, foreignKeys []foreignKeyDef) (string, error) {
 	if err := ensureIdentifier(table); err != nil {
 		return "", err
 	}
+	statement := buildCreateTableStatement(driver, table, columns, foreignKeys)
+	change := SchemaChange{Table: table, Kind: SchemaCreateTable, Up: []string{statement}, Down: []string{buildDropTableStatement(driver, table)}}
+	if err := db.apply(ctx, change); err != nil {
+		return "", err
+	}
+	db.markCreated(table)
+	return statement, nil
+}
+
+// buildCreateTableStatement 生成建表语句；sqlite 无法在建表后添加外键，外键内联声明，其他数据库建表后单独添加。
+func buildCreateTableStatement(driver, table string, columns []columnDef, foreignKeys []foreignKeyDef) string {
 	defs := make([]string, 0, len(columns))
 	var pk string
 	for _, col := range columns {
@@ -206,15 +246,16 @@ func createTable(ctx context.Context, db *sqlx.DB, driver, table string, columns
 	if pk != "" {
 		defs = append(defs, fmt.Sprintf("PRIMARY KEY (%s)", quoteIdentifier(driver, pk)))
 	}
-	statement := fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (%s)", quoteIdentifier(driver, table), strings.Join(defs, ", "))
-	if _, err := db.ExecContext(ctx, statement); err != nil {
-		return "", err
+	if driver == "sqlite" {
+		for _, foreignKey := range foreignKeys {
+			defs = append(defs, buildForeignKeyClause(driver, foreignKey))
+		}
 	}
-	return statement, nil
+	return fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (%s)", quoteIdentifier(driver, table), strings.Join(defs, ", "))
 }
 
-func addMissingColumns(ctx context.Context, db *sqlx.DB, driver, table string, columns []columnDef) ([]string, error) {
-	existing, err := loadExistingColumns(ctx, db, driver, table)
+func addMissingColumns(ctx context.Context, db *schemaConn, driver, table string, columns []columnDef) ([]string, error) {
+	existing, err := loadExistingColumns(ctx, db.DB, driver, table)
 	if err != nil {
 		return nil, err
 	}
@@ -233,7 +274,8 @@ func addMissingColumns(ctx context.Context, db *sqlx.DB, driver, table string, c
 		if driver == "mysql" && column.AutoIncrement {
 			definition += " AUTO_INCREMENT"
 		}
-		if _, err := db.ExecContext(ctx, definition); err != nil {
+		change := SchemaChange{Table: table, Kind: SchemaAddColumn, Up: []string{definition}, Down: []string{buildDropColumnStatement(driver, table, column.Name)}}
+		if err := db.apply(ctx, change); err != nil {
 			return nil, err
 		}
 		statements = append(statements, definition)
@@ -241,8 +283,8 @@ func addMissingColumns(ctx context.Context, db *sqlx.DB, driver, table string, c
 	return statements, nil
 }
 
-func syncColumnTypes(ctx context.Context, db *sqlx.DB, driver, table string, columns []columnDef) ([]string, error) {
-	existing, err := loadExistingColumnStates(ctx, db, driver, table)
+func syncColumnTypes(ctx context.Context, db *schemaConn, driver, table string, columns []columnDef) ([]string, error) {
+	existing, err := loadExistingColumnStates(ctx, db.DB, driver, table)
 	if err != nil {
 		return nil, err
 	}
@@ -259,13 +301,23 @@ func syncColumnTypes(ctx context.Context, db *sqlx.DB, driver, table string, col
 		if columnTypesMatch(actual.Type, desiredType) {
 			continue
 		}
-		ops := buildAlterColumnTypeStatements(driver, table, actual.Name, column, desiredType)
-		for _, stmt := range ops {
-			if _, err := db.ExecContext(ctx, stmt); err != nil {
-				return nil, err
-			}
-			statements = append(statements, stmt)
+		name := db.columnName(table, actual.Name)
+		ops := buildAlterColumnTypeStatements(driver, table, name, column, desiredType)
+		if len(ops) == 0 {
+			continue
+		}
+		previousType := existingSQLType(driver, actual.Type)
+		change := SchemaChange{
+			Table:       table,
+			Kind:        SchemaAlterColumn,
+			Up:          ops,
+			Down:        buildAlterColumnTypeStatements(driver, table, name, column, previousType),
+			Destructive: columnTypeNarrows(actual.Type, desiredType),
+		}
+		if err := db.apply(ctx, change); err != nil {
+			return nil, err
 		}
+		statements = append(statements, ops...)
 	}
 	return statements, nil
 }
@@ -301,8 +353,8 @@ func buildAlterColumnTypeStatements(driver, table, actualName string, column col
 	}
 }
 
-func dropObsoleteColumns(ctx context.Context, db *sqlx.DB, driver, table string, columns []columnDef) ([]string, error) {
-	existing, err := loadExistingColumns(ctx, db, driver, table)
+func dropObsoleteColumns(ctx context.Context, db *schemaConn, driver, table string, columns []columnDef) ([]string, error) {
+	existing, err := loadExistingColumnStates(ctx, db.DB, driver, table)
 	if err != nil {
 		return nil, err
 	}
@@ -313,19 +365,31 @@ func dropObsoleteColumns(ctx context.Context, db *sqlx.DB, driver, table string,
 		desired[canonicalColumnKey(lower)] = struct{}{}
 	}
 	var statements []string
+	names := make([]string, 0, len(existing))
 	for name := range existing {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
 		if _, ok := desired[name]; ok {
 			continue
 		}
 		if _, ok := desired[canonicalColumnKey(name)]; ok {
 			continue
 		}
-		colName := existing[name]
-		stmt := buildDropColumnStatement(driver, table, colName)
+		state := existing[name]
+		state.Name = db.columnName(table, state.Name)
+		if renamed := strings.ToLower(state.Name); renamed != name {
+			if _, ok := desired[renamed]; ok {
+				continue
+			}
+		}
+		stmt := buildDropColumnStatement(driver, table, state.Name)
 		if stmt == "" {
 			continue
 		}
-		if _, err := db.ExecContext(ctx, stmt); err != nil {
+		restore := buildRestoreColumnStatement(driver, table, state)
+		if err := db.apply(ctx, SchemaChange{Table: table, Kind: SchemaDropColumn, Up: []string{stmt}, Down: []string{restore}, Destructive: true}); err != nil {
 			return nil, err
 		}
 		statements = append(statements, stmt)
@@ -333,6 +397,44 @@ func dropObsoleteColumns(ctx context.Context, db *sqlx.DB, driver, table string,
 	return statements, nil
 }
 
+// buildRestoreColumnStatement 生成删除列的回滚语句，按数据库现状恢复类型、NOT NULL 与默认值；
+// sqlite 不允许新增没有默认值的 NOT NULL 列，此时只恢复类型与默认值。
+func buildRestoreColumnStatement(driver, table string, state existingColumnState) string {
+	definition := fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", quoteIdentifier(driver, table), quoteIdentifier(driver, state.Name), existingSQLType(driver, state.Type))
+	defaultClause := existingDefaultClause(driver, state)
+	if !state.Nullable && !(driver == "sqlite" && defaultClause == "") {
+		definition += " NOT NULL"
+	}
+	if defaultClause != "" {
+		definition += " DEFAULT " + defaultClause
+	}
+	return definition
+}
+
+// existingDefaultClause 将数据库报告的默认值还原为 DDL 写法：postgres/sqlite 报告的即为表达式，
+// mysql 报告的是字面值，除数字、CURRENT_TIMESTAMP 等表达式外需要加引号。
+func existingDefaultClause(driver string, state existingColumnState) string {
+	if state.Default == nil || state.AutoIncrement {
+		return ""
+	}
+	value := strings.TrimSpace(*state.Default)
+	if strings.EqualFold(value, "NULL") {
+		return ""
+	}
+	if driver != "mysql" {
+		return value
+	}
+	if _, err := strconv.ParseFloat(value, 64); err == nil {
+		return value
+	}
+	upper := strings.ToUpper(value)
+	if strings.HasPrefix(upper, "CURRENT_TIMESTAMP") || strings.HasPrefix(upper, "NOW(") ||
+		strings.HasPrefix(value, "'") || strings.HasPrefix(value, "(") || strings.HasPrefix(value, "b'") {
+		return value
+	}
+	return formatDefaultValue(value, false)
+}
+
 func buildDropColumnStatement(driver, table, column string) string {
 	switch driver {
 	case "postgres":
@@ -352,8 +454,8 @@ type indexState struct {
 	Unique  bool
 }
 
-func reconcileColumnNames(ctx context.Context, db *sqlx.DB, driver, table string, columns []columnDef) ([]string, error) {
-	existing, err := loadExistingColumns(ctx, db, driver, table)
+func reconcileColumnNames(ctx context.Context, db *schemaConn, driver, table string, columns []columnDef) ([]string, error) {
+	existing, err := loadExistingColumns(ctx, db.DB, driver, table)
 	if err != nil {
 		return nil, err
 	}
@@ -370,9 +472,11 @@ func reconcileColumnNames(ctx context.Context, db *sqlx.DB, driver, table string
 		if stmt == "" {
 			continue
 		}
-		if _, err := db.ExecContext(ctx, stmt); err != nil {
+		change := SchemaChange{Table: table, Kind: SchemaRenameColumn, Up: []string{stmt}, Down: []string{buildRenameColumnStatement(driver, table, column.Name, actual)}}
+		if err := db.apply(ctx, change); err != nil {
 			return nil, err
 		}
+		db.markRenamed(table, actual, column.Name)
 		statements = append(statements, stmt)
 	}
 	return statements, nil
@@ -392,7 +496,7 @@ func buildRenameColumnStatement(driver, table, oldName, newName string) string {
 	}
 }
 
-func applySeedData(ctx context.Context, db *sqlx.DB, driver, table string, columns []columnDef, seeds []map[string]any) ([]string, error) {
+func applySeedData(ctx context.Context, db *schemaConn, driver, table string, columns []columnDef, seeds []map[string]any) ([]string, error) {
 	if len(seeds) == 0 {
 		return nil, nil
 	}
@@ -409,9 +513,12 @@ func applySeedData(ctx context.Context, db *sqlx.DB, driver, table string, colum
 		if err != nil {
 			return nil, err
 		}
-		if _, err := db.NamedExecContext(ctx, query, payload); err != nil {
-			return nil, err
+		if !db.plan {
+			if _, err := db.NamedExecContext(ctx, query, payload); err != nil {
+				return nil, err
+			}
 		}
+		db.record(SchemaChange{Table: table, Kind: SchemaSeed, Up: []string{renderSeedInsert(driver, table, prepared)}})
 		statements = append(statements, query)
 	}
 	return statements, nil
@@ -428,7 +535,14 @@ func prepareSeedInsertRow(row map[string]any, columns []columnDef) map[string]an
 	}
 
 	for _, column := range columns {
-		if hasSeedInsertValue(prepared[column.Name]) || column.DefaultValue != nil {
+		if hasSeedInsertValue(prepared[column.Name]) {
+			continue
+		}
+		if mode := column.autoTimeMode(); mode != "" {
+			prepared[column.Name] = autoTimeValue(mode, time.Now())
+			continue
+		}
+		if column.DefaultValue != nil {
 			continue
 		}
 		switch normalizeColumnKey(column.Name) {
@@ -453,7 +567,7 @@ func hasSeedInsertValue(value any) bool {
 }
 
 // PostgreSQL 不会因显式插入种子 ID 自动推进 identity 序列。
-func syncPostgresSeedSequences(ctx context.Context, db *sqlx.DB, driver, table string, columns []columnDef, seeds []map[string]any) ([]string, error) {
+func syncPostgresSeedSequences(ctx context.Context, db *schemaConn, driver, table string, columns []columnDef, seeds []map[string]any) ([]string, error) {
 	if driver != "postgres" || len(seeds) == 0 {
 		return nil, nil
 	}
@@ -470,20 +584,13 @@ func syncPostgresSeedSequences(ctx context.Context, db *sqlx.DB, driver, table s
 			return nil, err
 		}
 
-		stmt := fmt.Sprintf(`WITH seed_sequence AS (
-	SELECT pg_get_serial_sequence('%s', '%s')::regclass AS sequence_id,
-		COALESCE(MAX(%s), 0)::bigint AS max_id
-	FROM %s
-)
-SELECT setval(sequence_id, max_id, true)
-FROM seed_sequence
-WHERE sequence_id IS NOT NULL
-	AND max_id > 0
-	AND (
-		pg_sequence_last_value(sequence_id) IS NULL
-		OR max_id > pg_sequence_last_value(sequence_id)
-	)`, table, column.Name, quoteIdentifier(driver, column.Name), quoteIdentifier(driver, table))
+		stmt := buildPostgresSequenceSync(table, column.Name)
 
+		if db.plan {
+			db.record(SchemaChange{Table: table, Kind: SchemaSequence, Up: []string{stmt}})
+			statements = append(statements, stmt)
+			continue
+		}
 		var sequenceValue int64
 		if err := db.QueryRowxContext(ctx, stmt).Scan(&sequenceValue); err != nil {
 			if err == sql.ErrNoRows {
@@ -491,11 +598,29 @@ WHERE sequence_id IS NOT NULL
 			}
 			return nil, err
 		}
+		db.record(SchemaChange{Table: table, Kind: SchemaSequence, Up: []string{stmt}})
 		statements = append(statements, stmt)
 	}
 	return statements, nil
 }
 
+// buildPostgresSequenceSync 生成把自增列序列推进到当前最大值的语句，序列已不小于最大值时不返回行。
+func buildPostgresSequenceSync(table, column string) string {
+	return fmt.Sprintf(`WITH seed_sequence AS (
+	SELECT pg_get_serial_sequence('%s', '%s')::regclass AS sequence_id,
+		COALESCE(MAX(%s), 0)::bigint AS max_id
+	FROM %s
+)
+SELECT setval(sequence_id, max_id, true)
+FROM seed_sequence
+WHERE sequence_id IS NOT NULL
+	AND max_id > 0
+	AND (
+		pg_sequence_last_value(sequence_id) IS NULL
+		OR max_id > pg_sequence_last_value(sequence_id)
+	)`, table, column, quoteIdentifier("postgres", column), quoteIdentifier("postgres", table))
+}
+
 func seedHasColumnValue(seeds []map[string]any, column string) bool {
 	for _, seed := range seeds {
 		if value, ok := seed[column]; ok && hasSeedInsertValue(value) {
@@ -505,36 +630,21 @@ func seedHasColumnValue(seeds []map[string]any, column string) bool {
 	return false
 }
 
-func ensureIndexes(ctx context.Context, db *sqlx.DB, driver, table string, indexes []indexDef) ([]string, error) {
-	existing, err := loadExistingIndexes(ctx, db, driver, table)
-	if err != nil {
-		return nil, err
-	}
-
-	desired := map[string]indexDef{}
-	for _, index := range indexes {
-		if len(index.Columns) == 0 {
-			continue
-		}
-		indexName := normalizeIndexName(index.Name)
-		if indexName == "" {
-			indexName = defaultIndexName(table, index.Columns[0], index.Unique)
-		} else if err := ensureIdentifier(indexName); err != nil {
-			indexName = defaultIndexName(table, index.Columns[0], index.Unique)
-		}
-		normalizedCols := normalizeIndexColumns(index.Columns)
-		if len(normalizedCols) == 0 {
-			continue
-		}
-		desired[strings.ToLower(indexName)] = indexDef{
-			Name:    indexName,
-			Columns: normalizedCols,
-			Unique:  index.Unique,
+func ensureIndexes(ctx context.Context, db *schemaConn, driver, table string, indexes []indexDef) ([]string, error) {
+	existing := map[string]indexState{}
+	if !db.created(table) {
+		loaded, err := loadExistingIndexes(ctx, db.DB, driver, table)
+		if err != nil {
+			return nil, err
 		}
+		existing = loaded
 	}
 
+	desired := desiredIndexes(table, indexes)
+
 	var statements []string
-	for key, info := range existing {
+	for _, key := range sortedIndexKeys(existing) {
+		info := existing[key]
 		if _, ok := desired[key]; ok {
 			continue
 		}
@@ -542,13 +652,15 @@ func ensureIndexes(ctx context.Context, db *sqlx.DB, driver, table string, index
 		if drop == "" {
 			continue
 		}
-		if _, err := db.ExecContext(ctx, drop); err != nil {
+		change := SchemaChange{Table: table, Kind: SchemaDropIndex, Up: []string{drop}, Down: []string{buildIndexStatement(driver, table, info.Name, info.Columns, info.Unique)}}
+		if err := db.apply(ctx, change); err != nil {
 			return nil, err
 		}
 		statements = append(statements, drop)
 	}
 
-	for key, target := range desired {
+	for _, key := range sortedIndexKeys(desired) {
+		target := desired[key]
 		existingInfo, ok := existing[key]
 		if ok && sameIndex(existingInfo, target) {
 			continue
@@ -556,21 +668,19 @@ func ensureIndexes(ctx context.Context, db *sqlx.DB, driver, table string, index
 		if ok {
 			drop := buildDropIndexStatement(driver, table, existingInfo.Name)
 			if drop != "" {
-				if _, err := db.ExecContext(ctx, drop); err != nil {
+				change := SchemaChange{Table: table, Kind: SchemaDropIndex, Up: []string{drop}, Down: []string{buildIndexStatement(driver, table, existingInfo.Name, existingInfo.Columns, existingInfo.Unique)}}
+				if err := db.apply(ctx, change); err != nil {
 					return nil, err
 				}
 				statements = append(statements, drop)
 			}
 		}
-		columnParts := make([]string, 0, len(target.Columns))
-		for _, col := range target.Columns {
-			columnParts = append(columnParts, quoteIdentifier(driver, col))
-		}
-		stmt := buildCreateIndexStatement(driver, target.Name, table, columnParts, target.Unique)
+		stmt := buildIndexStatement(driver, table, target.Name, target.Columns, target.Unique)
 		if stmt == "" {
 			continue
 		}
-		if _, err := db.ExecContext(ctx, stmt); err != nil {
+		change := SchemaChange{Table: table, Kind: SchemaCreateIndex, Up: []string{stmt}, Down: []string{buildDropIndexStatement(driver, table, target.Name)}}
+		if err := db.apply(ctx, change); err != nil {
 			return nil, err
 		}
 		statements = append(statements, stmt)
@@ -579,6 +689,32 @@ func ensureIndexes(ctx context.Context, db *sqlx.DB, driver, table string, index
 	return statements, nil
 }
 
+// desiredIndexes 按小写索引名整理声明的索引，补齐缺省或非法的索引名。
+func desiredIndexes(table string, indexes []indexDef) map[string]indexDef {
+	desired := map[string]indexDef{}
+	for _, index := range indexes {
+		if len(index.Columns) == 0 {
+			continue
+		}
+		indexName := normalizeIndexName(index.Name)
+		if indexName == "" {
+			indexName = defaultIndexName(table, index.Columns[0], index.Unique)
+		} else if err := ensureIdentifier(indexName); err != nil {
+			indexName = defaultIndexName(table, index.Columns[0], index.Unique)
+		}
+		normalizedCols := normalizeIndexColumns(index.Columns)
+		if len(normalizedCols) == 0 {
+			continue
+		}
+		desired[strings.ToLower(indexName)] = indexDef{
+			Name:    indexName,
+			Columns: normalizedCols,
+			Unique:  index.Unique,
+		}
+	}
+	return desired
+}
+
 func normalizeIndexColumns(columns []string) []string {
 	if len(columns) == 0 {
 		return nil
@@ -643,6 +779,10 @@ func buildDropIndexStatement(driver, table, indexName string) string {
 	case "mysql":
 		return fmt.Sprintf("DROP INDEX %s ON %s", quoteIdentifier(driver, indexName), quoteIdentifier(driver, table))
 	case "sqlite":
+		// UNIQUE 约束自动创建的索引随表存在，无法单独删除。
+		if strings.HasPrefix(strings.ToLower(indexName), "sqlite_autoindex_") {
+			return ""
+		}
 		return fmt.Sprintf("DROP INDEX IF EXISTS %s", quoteIdentifier(driver, indexName))
 	default:
 		return ""
@@ -715,9 +855,16 @@ func loadExistingColumns(ctx context.Context, db *sqlx.DB, driver, table string)
 	return result, nil
 }
 
+// existingColumnState 描述数据库中已有列的现状；Type 之外的字段主要用于 model import 反向生成模型。
 type existingColumnState struct {
-	Name string
-	Type string
+	Name          string
+	Type          string
+	Position      int
+	Nullable      bool
+	Default       *string
+	Comment       string
+	Primary       bool
+	AutoIncrement bool
 }
 
 func loadExistingColumnStates(ctx context.Context, db *sqlx.DB, driver, table string) (map[string]existingColumnState, error) {
@@ -728,45 +875,71 @@ func loadExistingColumnStates(ctx context.Context, db *sqlx.DB, driver, table st
 	switch driver {
 	case "postgres":
 		query := `
-SELECT column_name,
+SELECT c.column_name,
        CASE
-         WHEN data_type = 'character varying' AND character_maximum_length IS NOT NULL THEN 'VARCHAR(' || character_maximum_length || ')'
-         WHEN data_type = 'character varying' THEN 'VARCHAR'
-         WHEN data_type = 'timestamp with time zone' THEN 'TIMESTAMPTZ'
-         WHEN data_type = 'timestamp without time zone' THEN 'TIMESTAMP'
-         WHEN data_type = 'double precision' THEN 'DOUBLE'
-         ELSE UPPER(data_type)
-       END AS column_type
-FROM information_schema.columns
-WHERE table_schema = current_schema() AND table_name = $1`
+         WHEN c.data_type = 'character varying' AND c.character_maximum_length IS NOT NULL THEN 'VARCHAR(' || c.character_maximum_length || ')'
+         WHEN c.data_type = 'character varying' THEN 'VARCHAR'
+         WHEN c.data_type = 'timestamp with time zone' THEN 'TIMESTAMPTZ'
+         WHEN c.data_type = 'timestamp without time zone' THEN 'TIMESTAMP'
+         WHEN c.data_type = 'double precision' THEN 'DOUBLE'
+         WHEN c.data_type = 'numeric' AND c.numeric_precision IS NOT NULL THEN 'DECIMAL(' || c.numeric_precision || ',' || c.numeric_scale || ')'
+         ELSE UPPER(c.data_type)
+       END AS column_type,
+       c.ordinal_position,
+       c.is_nullable = 'YES' AS nullable,
+       c.column_default,
+       COALESCE(col_description((quote_ident(c.table_schema) || '.' || quote_ident(c.table_name))::regclass, c.ordinal_position), '') AS column_comment,
+       EXISTS (
+         SELECT 1 FROM information_schema.table_constraints tc
+         JOIN information_schema.key_column_usage k
+           ON k.constraint_name = tc.constraint_name AND k.table_schema = tc.table_schema AND k.table_name = tc.table_name
+         WHERE tc.constraint_type = 'PRIMARY KEY' AND tc.table_schema = c.table_schema
+           AND tc.table_name = c.table_name AND k.column_name = c.column_name
+       ) AS is_primary,
+       (c.is_identity = 'YES' OR COALESCE(c.column_default, '') LIKE 'nextval(%') AS auto_increment
+FROM information_schema.columns c
+WHERE c.table_schema = current_schema() AND c.table_name = $1`
 		rows, err := db.QueryxContext(ctx, query, table)
 		if err != nil {
 			return nil, err
 		}
 		defer rows.Close()
 		for rows.Next() {
-			var name, columnType string
-			if err := rows.Scan(&name, &columnType); err != nil {
+			var state existingColumnState
+			var dflt sql.NullString
+			if err := rows.Scan(&state.Name, &state.Type, &state.Position, &state.Nullable, &dflt, &state.Comment, &state.Primary, &state.AutoIncrement); err != nil {
 				return nil, err
 			}
-			result[strings.ToLower(name)] = existingColumnState{Name: name, Type: columnType}
+			if dflt.Valid && !state.AutoIncrement {
+				state.Default = &dflt.String
+			}
+			result[strings.ToLower(state.Name)] = state
 		}
 		if err := rows.Err(); err != nil {
 			return nil, err
 		}
 	case "mysql":
-		query := "SELECT column_name, column_type FROM information_schema.columns WHERE table_schema = DATABASE() AND table_name = ?"
+		query := `
+SELECT column_name, column_type, ordinal_position, is_nullable = 'YES', column_default, column_comment,
+       column_key = 'PRI', extra LIKE '%auto_increment%'
+FROM information_schema.columns
+WHERE table_schema = DATABASE() AND table_name = ?`
 		rows, err := db.QueryxContext(ctx, query, table)
 		if err != nil {
 			return nil, err
 		}
 		defer rows.Close()
 		for rows.Next() {
-			var name, columnType string
-			if err := rows.Scan(&name, &columnType); err != nil {
+			var state existingColumnState
+			var dflt sql.NullString
+			if err := rows.Scan(&state.Name, &state.Type, &state.Position, &state.Nullable, &dflt, &state.Comment, &state.Primary, &state.AutoIncrement); err != nil {
 				return nil, err
 			}
-			result[strings.ToLower(name)] = existingColumnState{Name: name, Type: columnType}
+			// MariaDB 以字面量 NULL 表示没有默认值。
+			if dflt.Valid && !strings.EqualFold(dflt.String, "NULL") {
+				state.Default = &dflt.String
+			}
+			result[strings.ToLower(state.Name)] = state
 		}
 		if err := rows.Err(); err != nil {
 			return nil, err
@@ -778,6 +951,7 @@ WHERE table_schema = current_schema() AND table_name = $1`
 			return nil, err
 		}
 		defer rows.Close()
+		primaryCount := 0
 		for rows.Next() {
 			var cid int
 			var name, columnType string
@@ -786,11 +960,27 @@ WHERE table_schema = current_schema() AND table_name = $1`
 			if err := rows.Scan(&cid, &name, &columnType, &notnull, &dflt, &pk); err != nil {
 				return nil, err
 			}
-			result[strings.ToLower(name)] = existingColumnState{Name: name, Type: columnType}
+			state := existingColumnState{Name: name, Type: columnType, Position: cid + 1, Nullable: notnull == 0 && pk == 0, Primary: pk > 0}
+			if dflt.Valid {
+				state.Default = &dflt.String
+			}
+			if pk > 0 {
+				primaryCount++
+			}
+			result[strings.ToLower(name)] = state
 		}
 		if err := rows.Err(); err != nil {
 			return nil, err
 		}
+		// 单列 INTEGER 主键是 rowid 的别名，插入时自动递增。
+		if primaryCount == 1 {
+			for key, state := range result {
+				if state.Primary && strings.EqualFold(strings.TrimSpace(state.Type), "INTEGER") {
+					state.AutoIncrement = true
+					result[key] = state
+				}
+			}
+		}
 	default:
 		return nil, fmt.Errorf("orm: unsupported driver %s", driver)
 	}
@@ -842,6 +1032,9 @@ func normalizeColumnType(columnType string) string {
 		return "BOOLEAN"
 	}
 	normalized = strings.ReplaceAll(normalized, "CHARACTER VARYING(", "VARCHAR(")
+	if strings.HasPrefix(normalized, "NUMERIC(") {
+		normalized = "DECIMAL" + strings.TrimPrefix(normalized, "NUMERIC")
+	}
 	normalized = strings.ReplaceAll(normalized, " ", "")
 	return normalized
 }
@@ -1039,6 +1232,10 @@ func sqlTypeForDriver(baseType, driver string, autoIncrement bool) string {
 			return "TIMESTAMPTZ"
 		case "BOOLEAN":
 			return "BOOLEAN"
+		case "JSON":
+			return "JSONB"
+		case "BLOB":
+			return "BYTEA"
 		default:
 			return base
 		}
@@ -1065,6 +1262,8 @@ func sqlTypeForDriver(baseType, driver string, autoIncrement bool) string {
 			return "TEXT"
 		case "BIGINT":
 			return "INTEGER"
+		case "JSON":
+			return "TEXT"
 		default:
 			return base
 		}
@@ -1144,7 +1343,7 @@ func uniqueStrings(items []string) []string {
 	return result
 }
 
-// ApplyRecordedSchemas 读取 data/table 下记录的表结构并应用到指定数据库。
+// ApplyRecordedSchemas 读取 data/table 下记录的表结构并应用到指定数据库；不读写 dever_migrations 执行记录，与版本化迁移二选一使用。
 func ApplyRecordedSchemas(ctx context.Context, dbName string) ([]string, error) {
 	if ctx == nil {
 		ctx = context.Background()
@@ -1162,11 +1361,11 @@ func ApplyRecordedSchemas(ctx context.Context, dbName string) ([]string, error)
 		return nil, nil
 	}
 	var statements []string
-	for _, schema := range schemas {
+	for _, schema := range sortSchemasByDependency(schemas) {
 		if schema == nil {
 			continue
 		}
-		ops, err := syncTableSchema(ctx, db, driver, schema.Table, schema)
+		ops, err := syncTableSchema(ctx, newSchemaConn(db, false), driver, schema.Table, schema)
 		if err != nil {
 			return nil, err
 		}
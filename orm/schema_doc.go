@@ -0,0 +1,87 @@
+package orm
+
+import "strings"
+
+// SchemaDoc 描述一张表的结构，供 dever schema doc 生成参考文档与 ER 图。
+type SchemaDoc struct {
+	Table       string
+	Model       string // 模型业务名，由 dever schema doc 从源码的 LoadModel 调用中补充
+	Columns     []SchemaDocColumn
+	Indexes     []SchemaDocIndex
+	ForeignKeys []SchemaDocForeignKey
+	Relations   []Relation
+}
+
+// SchemaDocColumn 描述一列，Label 取自列注释（与查询结果的字段标签一致）。
+type SchemaDocColumn struct {
+	Name          string
+	Type          string
+	Label         string
+	Default       string
+	NotNull       bool
+	Primary       bool
+	AutoIncrement bool
+}
+
+type SchemaDocIndex struct {
+	Name    string
+	Columns []string
+	Unique  bool
+}
+
+type SchemaDocForeignKey struct {
+	Column    string
+	RefTable  string
+	RefColumn string
+	OnDelete  string
+	OnUpdate  string
+}
+
+// RecordedSchemaDocs 读取 data/table 下记录的表结构，按表名排序。
+func RecordedSchemaDocs() ([]SchemaDoc, error) {
+	schemas, err := listRecordedSchemas()
+	if err != nil {
+		return nil, err
+	}
+	docs := make([]SchemaDoc, 0, len(schemas))
+	for _, schema := range schemas {
+		docs = append(docs, newSchemaDoc(schema))
+	}
+	return docs, nil
+}
+
+func newSchemaDoc(schema *tableSchema) SchemaDoc {
+	doc := SchemaDoc{Table: schema.Table}
+	for _, column := range schema.Columns {
+		item := SchemaDocColumn{
+			Name:          column.Name,
+			Type:          strings.ToUpper(column.Type),
+			Label:         strings.TrimSpace(column.Comment),
+			NotNull:       column.NotNull,
+			Primary:       column.Primary,
+			AutoIncrement: column.AutoIncrement,
+		}
+		if column.DefaultValue != nil {
+			item.Default = *column.DefaultValue
+		}
+		doc.Columns = append(doc.Columns, item)
+	}
+	indexes := append([]indexDef(nil), schema.Indexes...)
+	for _, index := range withForeignKeyIndexes(schema.Table, schema.Columns, indexes, schema.ForeignKeys) {
+		doc.Indexes = append(doc.Indexes, SchemaDocIndex{
+			Name:    index.Name,
+			Columns: append([]string(nil), index.Columns...),
+			Unique:  index.Unique,
+		})
+	}
+	for _, foreignKey := range schema.ForeignKeys {
+		doc.ForeignKeys = append(doc.ForeignKeys, SchemaDocForeignKey{
+			Column:    foreignKey.Column,
+			RefTable:  foreignKey.RefTable,
+			RefColumn: foreignKey.RefColumn,
+			OnDelete:  foreignKey.OnDelete,
+			OnUpdate:  foreignKey.OnUpdate,
+		})
+	}
+	return doc
+}
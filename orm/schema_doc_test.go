@@ -0,0 +1,81 @@
+package orm
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+type docAuthor struct {
+	ID   uint64 `dorm:"primaryKey;autoIncrement"`
+	Name string `dorm:"type:varchar(32);comment:作者名"`
+}
+
+type docPost struct {
+	ID          uint64 `dorm:"primaryKey;autoIncrement"`
+	DocAuthorID uint64 `dorm:"fk:doc_author.id;onDelete:cascade"`
+	Title       string `dorm:"type:varchar(64);unique;comment: 标题 "`
+	Status      int    `dorm:"default:1"`
+}
+
+func TestRecordedSchemaDocs(t *testing.T) {
+	t.Chdir(t.TempDir())
+	if err := os.MkdirAll(filepath.Join("data", "table"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for table, model := range map[string]any{"doc_post": docPost{}, "doc_author": docAuthor{}} {
+		schema, err := buildSchema(table, model, schemaOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := json.Marshal(schema)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join("data", "table", table+".json"), data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	docs, err := RecordedSchemaDocs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(docs) != 2 || docs[0].Table != "doc_author" || docs[1].Table != "doc_post" {
+		t.Fatalf("docs = %+v, want doc_author, doc_post", docs)
+	}
+	post := docs[1]
+	columns := map[string]SchemaDocColumn{}
+	for _, column := range post.Columns {
+		columns[column.Name] = column
+	}
+	if id := columns["id"]; !id.Primary || !id.AutoIncrement {
+		t.Errorf("id = %+v, want primary auto increment", id)
+	}
+	if title := columns["title"]; title.Type != "VARCHAR(64)" || title.Label != "标题" {
+		t.Errorf("title = %+v, want upper-cased type and trimmed label", title)
+	}
+	if status := columns["status"]; status.Default != "1" {
+		t.Errorf("status default = %q, want 1", status.Default)
+	}
+	wantForeignKeys := []SchemaDocForeignKey{{Column: "doc_author_id", RefTable: "doc_author", RefColumn: "id", OnDelete: "CASCADE"}}
+	if !reflect.DeepEqual(post.ForeignKeys, wantForeignKeys) {
+		t.Errorf("foreign keys = %+v, want %+v", post.ForeignKeys, wantForeignKeys)
+	}
+	// 外键列没有显式索引时，文档里要列出建表时自动补的索引。
+	indexes := map[string]SchemaDocIndex{}
+	for _, index := range post.Indexes {
+		indexes[index.Name] = index
+	}
+	if index, ok := indexes["uidx_doc_post_title"]; !ok || !index.Unique {
+		t.Errorf("indexes = %+v, want unique uidx_doc_post_title", post.Indexes)
+	}
+	if index, ok := indexes["idx_doc_post_doc_author_id"]; !ok || index.Unique || !reflect.DeepEqual(index.Columns, []string{"doc_author_id"}) {
+		t.Errorf("indexes = %+v, want foreign key index idx_doc_post_doc_author_id", post.Indexes)
+	}
+	if author := docs[0]; len(author.ForeignKeys) != 0 || author.Columns[1].Label != "作者名" {
+		t.Errorf("author = %+v", author)
+	}
+}
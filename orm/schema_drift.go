@@ -0,0 +1,321 @@
+package orm
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// 结构漂移检测：只读对比 data/table 中记录的表结构与数据库现状，用于发现线上被手工修改的库。
+
+// SchemaDrift 是 DetectDrift 的结果。UnknownTables 为数据库中存在但没有记录的表（不含迁移历史表）。
+type SchemaDrift struct {
+	Database      string       `json:"database"`
+	Driver        string       `json:"driver"`
+	CheckedAt     time.Time    `json:"checkedAt"`
+	UnknownTables []string     `json:"unknownTables,omitempty"`
+	Tables        []TableDrift `json:"tables,omitempty"`
+}
+
+// TableDrift 描述单张记录表与数据库的差异，没有差异的表不会出现在结果中。
+type TableDrift struct {
+	Table              string        `json:"table"`
+	Missing            bool          `json:"missing,omitempty"`
+	MissingColumns     []string      `json:"missingColumns,omitempty"`
+	ExtraColumns       []string      `json:"extraColumns,omitempty"`
+	TypeMismatches     []ColumnDrift `json:"typeMismatches,omitempty"`
+	MissingIndexes     []string      `json:"missingIndexes,omitempty"`
+	ExtraIndexes       []string      `json:"extraIndexes,omitempty"`
+	ChangedIndexes     []string      `json:"changedIndexes,omitempty"`
+	MissingForeignKeys []string      `json:"missingForeignKeys,omitempty"`
+	ExtraForeignKeys   []string      `json:"extraForeignKeys,omitempty"`
+	Seeds              []SeedDrift   `json:"seeds,omitempty"`
+}
+
+// ColumnDrift 描述列类型差异，Expected 为按当前驱动换算后的声明类型。
+type ColumnDrift struct {
+	Column   string `json:"column"`
+	Expected string `json:"expected"`
+	Actual   string `json:"actual"`
+}
+
+// SeedDrift 描述按主键定位的种子数据差异；Missing 表示该行不存在，Columns 为取值不同的列及数据库中的值。
+type SeedDrift struct {
+	Key     any            `json:"key"`
+	Missing bool           `json:"missing,omitempty"`
+	Columns map[string]any `json:"columns,omitempty"`
+}
+
+// HasDrift 判断是否存在任何差异。
+func (d SchemaDrift) HasDrift() bool {
+	return len(d.UnknownTables) > 0 || len(d.Tables) > 0
+}
+
+func (t TableDrift) empty() bool {
+	return !t.Missing && len(t.MissingColumns) == 0 && len(t.ExtraColumns) == 0 && len(t.TypeMismatches) == 0 &&
+		len(t.MissingIndexes) == 0 && len(t.ExtraIndexes) == 0 && len(t.ChangedIndexes) == 0 &&
+		len(t.MissingForeignKeys) == 0 && len(t.ExtraForeignKeys) == 0 && len(t.Seeds) == 0
+}
+
+// DetectDrift 对比 data/table 中记录的全部表结构与指定数据库，只读取不修改。
+func DetectDrift(ctx context.Context, dbName string) (SchemaDrift, error) {
+	ctx = normalizeContext(ctx)
+	dbName = migrationDatabase(dbName)
+	db, err := Get(dbName)
+	if err != nil {
+		return SchemaDrift{}, err
+	}
+	driver := normalizeDriver(db.DriverName())
+	schemas, err := listRecordedSchemas()
+	if err != nil {
+		return SchemaDrift{}, err
+	}
+	result := SchemaDrift{Database: dbName, Driver: driver, CheckedAt: time.Now()}
+
+	recorded := make(map[string]struct{}, len(schemas))
+	for _, schema := range schemas {
+		if schema == nil {
+			continue
+		}
+		recorded[strings.ToLower(schema.Table)] = struct{}{}
+		drift, err := detectTableDrift(ctx, db, driver, schema)
+		if err != nil {
+			return SchemaDrift{}, fmt.Errorf("orm: detect drift for %s failed: %w", schema.Table, err)
+		}
+		if !drift.empty() {
+			result.Tables = append(result.Tables, drift)
+		}
+	}
+
+	tables, err := listDatabaseTables(ctx, db, driver)
+	if err != nil {
+		return SchemaDrift{}, err
+	}
+	for _, table := range tables {
+		if _, ok := recorded[strings.ToLower(table)]; ok || strings.EqualFold(table, migrationHistoryTable) {
+			continue
+		}
+		result.UnknownTables = append(result.UnknownTables, table)
+	}
+	sort.Strings(result.UnknownTables)
+	sort.Slice(result.Tables, func(i, j int) bool {
+		return result.Tables[i].Table < result.Tables[j].Table
+	})
+	return result, nil
+}
+
+func detectTableDrift(ctx context.Context, db *sqlx.DB, driver string, schema *tableSchema) (TableDrift, error) {
+	table := schema.Table
+	drift := TableDrift{Table: table}
+	exists, err := tableExists(ctx, db, driver, table)
+	if err != nil {
+		return drift, err
+	}
+	if !exists {
+		drift.Missing = true
+		return drift, nil
+	}
+
+	existing, err := loadExistingColumnStates(ctx, db, driver, table)
+	if err != nil {
+		return drift, err
+	}
+	desired := make(map[string]struct{}, len(schema.Columns)*2)
+	for _, column := range schema.Columns {
+		lower := strings.ToLower(column.Name)
+		desired[lower] = struct{}{}
+		desired[canonicalColumnKey(lower)] = struct{}{}
+		actual, ok := findExistingColumnState(existing, column.Name)
+		if !ok {
+			drift.MissingColumns = append(drift.MissingColumns, column.Name)
+			continue
+		}
+		if column.Primary || column.AutoIncrement {
+			continue
+		}
+		expected := sqlTypeForDriver(column.Type, driver, column.AutoIncrement)
+		if !columnTypesMatch(actual.Type, expected) {
+			drift.TypeMismatches = append(drift.TypeMismatches, ColumnDrift{Column: column.Name, Expected: expected, Actual: actual.Type})
+		}
+	}
+	for _, name := range sortedIndexKeys(existing) {
+		if _, ok := desired[name]; ok {
+			continue
+		}
+		if _, ok := desired[canonicalColumnKey(name)]; ok {
+			continue
+		}
+		drift.ExtraColumns = append(drift.ExtraColumns, existing[name].Name)
+	}
+
+	indexes, err := loadExistingIndexes(ctx, db, driver, table)
+	if err != nil {
+		return drift, err
+	}
+	wanted := desiredIndexes(table, schema.Indexes)
+	for _, key := range sortedIndexKeys(wanted) {
+		current, ok := indexes[key]
+		switch {
+		case !ok:
+			drift.MissingIndexes = append(drift.MissingIndexes, wanted[key].Name)
+		case !sameIndex(current, wanted[key]):
+			drift.ChangedIndexes = append(drift.ChangedIndexes, wanted[key].Name)
+		}
+	}
+	for _, key := range sortedIndexKeys(indexes) {
+		if _, ok := wanted[key]; !ok {
+			drift.ExtraIndexes = append(drift.ExtraIndexes, indexes[key].Name)
+		}
+	}
+
+	foreignKeys, err := loadExistingForeignKeys(ctx, db, driver, table)
+	if err != nil {
+		return drift, err
+	}
+	for _, foreignKey := range schema.ForeignKeys {
+		if !containsForeignKey(foreignKeys, foreignKey) {
+			drift.MissingForeignKeys = append(drift.MissingForeignKeys, describeForeignKey(foreignKey))
+		}
+	}
+	for _, foreignKey := range foreignKeys {
+		if !containsForeignKey(schema.ForeignKeys, foreignKey) {
+			drift.ExtraForeignKeys = append(drift.ExtraForeignKeys, describeForeignKey(foreignKey))
+		}
+	}
+
+	seeds, err := detectSeedDrift(ctx, db, driver, schema)
+	if err != nil {
+		return drift, err
+	}
+	drift.Seeds = seeds
+	return drift, nil
+}
+
+func containsForeignKey(items []foreignKeyDef, target foreignKeyDef) bool {
+	for _, item := range items {
+		if sameForeignKey(item, target) {
+			return true
+		}
+	}
+	return false
+}
+
+func describeForeignKey(foreignKey foreignKeyDef) string {
+	text := fmt.Sprintf("%s -> %s.%s", foreignKey.Column, foreignKey.RefTable, foreignKey.RefColumn)
+	if action := effectiveForeignKeyAction(foreignKey.OnDelete); action != "NO ACTION" {
+		text += " ON DELETE " + action
+	}
+	if action := effectiveForeignKeyAction(foreignKey.OnUpdate); action != "NO ACTION" {
+		text += " ON UPDATE " + action
+	}
+	if foreignKey.Name != "" {
+		text = foreignKey.Name + " (" + text + ")"
+	}
+	return text
+}
+
+// detectSeedDrift 按主键逐行对比种子数据中声明的列；没有主键值的种子行无法定位，跳过。
+func detectSeedDrift(ctx context.Context, db *sqlx.DB, driver string, schema *tableSchema) ([]SeedDrift, error) {
+	if len(schema.Seeds) == 0 {
+		return nil, nil
+	}
+	primary := ""
+	for _, column := range schema.Columns {
+		if column.Primary {
+			primary = column.Name
+			break
+		}
+	}
+	if primary == "" {
+		return nil, nil
+	}
+	quoter := func(name string) string {
+		return quoteIdentifier(driver, name)
+	}
+	query := db.Rebind(fmt.Sprintf("SELECT * FROM %s WHERE %s = ?", quoter(schema.Table), quoter(primary)))
+	var result []SeedDrift
+	for _, seed := range schema.Seeds {
+		key, ok := seed[primary]
+		if !ok || key == nil {
+			continue
+		}
+		rows, err := db.QueryxContext(ctx, query, key)
+		if err != nil {
+			return nil, err
+		}
+		var actual map[string]any
+		if rows.Next() {
+			actual = map[string]any{}
+			if err := rows.MapScan(actual); err != nil {
+				rows.Close()
+				return nil, err
+			}
+			normalizeMapValues(actual)
+		}
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			return nil, err
+		}
+		if actual == nil {
+			result = append(result, SeedDrift{Key: key, Missing: true})
+			continue
+		}
+		changed := map[string]any{}
+		for _, name := range sortedKeys(seed) {
+			column, ok := schema.resolveColumnDef(name)
+			if !ok || column.autoTimeMode() != "" {
+				continue
+			}
+			current, ok := findSeedColumnValue(actual, column.Name)
+			if !ok {
+				continue
+			}
+			if !seedValueEqual(column, seed[name], current) {
+				changed[column.Name] = current
+			}
+		}
+		if len(changed) > 0 {
+			result = append(result, SeedDrift{Key: key, Columns: changed})
+		}
+	}
+	return result, nil
+}
+
+func findSeedColumnValue(row map[string]any, column string) (any, bool) {
+	for key, value := range row {
+		if strings.EqualFold(key, column) {
+			return value, true
+		}
+	}
+	return nil, false
+}
+
+func seedValueEqual(column columnDef, expected, actual any) bool {
+	expected = normalizeValueByType(expected, column.Type)
+	actual = normalizeValueByType(actual, column.Type)
+	return fmt.Sprint(expected) == fmt.Sprint(actual)
+}
+
+func listDatabaseTables(ctx context.Context, db *sqlx.DB, driver string) ([]string, error) {
+	var query string
+	switch driver {
+	case "postgres":
+		query = "SELECT table_name FROM information_schema.tables WHERE table_schema = current_schema() AND table_type = 'BASE TABLE'"
+	case "mysql":
+		query = "SELECT table_name FROM information_schema.tables WHERE table_schema = DATABASE() AND table_type = 'BASE TABLE'"
+	case "sqlite":
+		query = "SELECT name FROM sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite_%'"
+	default:
+		return nil, fmt.Errorf("orm: unsupported driver %s", driver)
+	}
+	var tables []string
+	if err := db.SelectContext(ctx, &tables, query); err != nil {
+		return nil, err
+	}
+	return tables, nil
+}
@@ -0,0 +1,145 @@
+package orm
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+type driftTeam struct {
+	ID   uint64 `dorm:"primaryKey;autoIncrement"`
+	Name string `dorm:"type:varchar(32)"`
+}
+
+type driftMember struct {
+	ID          uint64 `dorm:"primaryKey;autoIncrement"`
+	DriftTeamID uint64 `dorm:"fk:drift_team.id"`
+	Name        string `dorm:"type:varchar(32);index"`
+	Age         int
+}
+
+// setupDriftDatabase 在临时目录记录表结构到 data/table 并按记录建表（含种子数据），得到没有漂移的库。
+func setupDriftDatabase(t *testing.T, name string) *tableSchema {
+	t.Helper()
+	dir := t.TempDir()
+	t.Chdir(dir)
+	db, err := Init(name, Config{Driver: "sqlite3", Path: filepath.Join(dir, name+".db")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = Close(name) })
+
+	team, err := buildSchema("drift_team", driftTeam{}, schemaOptions{seeds: []map[string]any{{"id": 1, "name": "core"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	member, err := buildSchema("drift_member", driftMember{}, schemaOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join("data", "table"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	for _, schema := range []*tableSchema{team, member} {
+		data, err := json.Marshal(schema)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join("data", "table", schema.Table+".json"), data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := syncTableSchema(ctx, newSchemaConn(db, false), "sqlite", schema.Table, schema); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return member
+}
+
+func TestDetectDriftCleanDatabase(t *testing.T) {
+	const dbName = "drift_clean"
+	setupDriftDatabase(t, dbName)
+	drift, err := DetectDrift(context.Background(), dbName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if drift.HasDrift() {
+		t.Fatalf("fresh database reports drift: %+v", drift)
+	}
+	if drift.Database != dbName || drift.Driver != "sqlite" {
+		t.Fatalf("drift header = %s/%s", drift.Database, drift.Driver)
+	}
+}
+
+func TestDetectDriftReportsManualChanges(t *testing.T) {
+	const dbName = "drift_changed"
+	member := setupDriftDatabase(t, dbName)
+	db, err := Get(dbName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, stmt := range []string{
+		`ALTER TABLE "drift_member" ADD COLUMN "nickname" TEXT`,
+		`DROP INDEX "` + member.Indexes[0].Name + `"`,
+		`CREATE INDEX "idx_manual_age" ON "drift_member" ("age")`,
+		`UPDATE "drift_team" SET "name" = 'renamed' WHERE "id" = 1`,
+		`CREATE TABLE "legacy_log" ("id" INTEGER PRIMARY KEY)`,
+	} {
+		if _, err := db.Exec(stmt); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	drift, err := DetectDrift(context.Background(), dbName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !drift.HasDrift() {
+		t.Fatal("manual changes not detected")
+	}
+	if !reflect.DeepEqual(drift.UnknownTables, []string{"legacy_log"}) {
+		t.Errorf("unknown tables = %v", drift.UnknownTables)
+	}
+	if len(drift.Tables) != 2 {
+		t.Fatalf("tables = %+v", drift.Tables)
+	}
+	memberDrift, teamDrift := drift.Tables[0], drift.Tables[1]
+	if memberDrift.Table != "drift_member" || teamDrift.Table != "drift_team" {
+		t.Fatalf("tables not sorted: %s, %s", memberDrift.Table, teamDrift.Table)
+	}
+	if !reflect.DeepEqual(memberDrift.ExtraColumns, []string{"nickname"}) {
+		t.Errorf("extra columns = %v", memberDrift.ExtraColumns)
+	}
+	if !reflect.DeepEqual(memberDrift.MissingIndexes, []string{member.Indexes[0].Name}) {
+		t.Errorf("missing indexes = %v", memberDrift.MissingIndexes)
+	}
+	if !reflect.DeepEqual(memberDrift.ExtraIndexes, []string{"idx_manual_age"}) {
+		t.Errorf("extra indexes = %v", memberDrift.ExtraIndexes)
+	}
+	if len(memberDrift.MissingForeignKeys)+len(memberDrift.ExtraForeignKeys) != 0 {
+		t.Errorf("foreign keys unexpectedly drifted: %+v", memberDrift)
+	}
+	if len(teamDrift.Seeds) != 1 || teamDrift.Seeds[0].Columns["name"] != "renamed" {
+		t.Errorf("seed drift = %+v", teamDrift.Seeds)
+	}
+
+	if _, err := db.Exec(`DROP TABLE "drift_member"`); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec(`DELETE FROM "drift_team"`); err != nil {
+		t.Fatal(err)
+	}
+	drift, err = DetectDrift(context.Background(), dbName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !drift.Tables[0].Missing {
+		t.Errorf("dropped table not reported missing: %+v", drift.Tables[0])
+	}
+	if seeds := drift.Tables[1].Seeds; len(seeds) != 1 || !seeds[0].Missing {
+		t.Errorf("deleted seed row = %+v", seeds)
+	}
+}
@@ -0,0 +1,191 @@
+package orm
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shemic/dever/util"
+)
+
+// 字段类型扩展：匿名嵌入结构体展开、JSON 列、二进制列与定点小数。
+
+const defaultDecimalType = "DECIMAL(18,4)"
+
+var (
+	timeType        = reflect.TypeOf(time.Time{})
+	rawMessageType  = reflect.TypeOf(json.RawMessage(nil))
+	scannerType     = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
+	driverValueType = reflect.TypeOf((*driver.Valuer)(nil)).Elem()
+)
+
+// schemaFields 返回参与建表与映射的导出字段；未声明 dorm/db 标签的匿名嵌入结构体展开为父级字段，
+// Index 为从顶层开始的完整路径，同名字段按 Go 的提升规则保留层级最浅的一个。
+func schemaFields(t reflect.Type) []reflect.StructField {
+	fields := make([]reflect.StructField, 0, t.NumField())
+	positions := make(map[string]int, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		candidates := []reflect.StructField{field}
+		if embedded := embeddedStructType(field); embedded != nil {
+			candidates = candidates[:0]
+			for _, inner := range schemaFields(embedded) {
+				inner.Index = append([]int{i}, inner.Index...)
+				candidates = append(candidates, inner)
+			}
+		}
+		for _, candidate := range candidates {
+			if pos, ok := positions[candidate.Name]; ok {
+				if len(fields[pos].Index) > len(candidate.Index) {
+					fields[pos] = candidate
+				}
+				continue
+			}
+			positions[candidate.Name] = len(fields)
+			fields = append(fields, candidate)
+		}
+	}
+	return fields
+}
+
+func embeddedStructType(field reflect.StructField) reflect.Type {
+	if !field.Anonymous || field.Tag.Get("dorm") != "" || field.Tag.Get("db") != "" {
+		return nil
+	}
+	t := field.Type
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct || t == timeType || implementsValueType(t) {
+		return nil
+	}
+	return t
+}
+
+// fieldByIndexAlloc 按完整路径取字段，途经的嵌入指针为 nil 时自动分配。
+func fieldByIndexAlloc(value reflect.Value, index []int) reflect.Value {
+	for i, position := range index {
+		if i > 0 && value.Kind() == reflect.Pointer {
+			if value.IsNil() {
+				value.Set(reflect.New(value.Type().Elem()))
+			}
+			value = value.Elem()
+		}
+		value = value.Field(position)
+	}
+	return value
+}
+
+// implementsValueType 判断类型是否自行实现了数据库读写（如第三方 decimal 类型）。
+func implementsValueType(t reflect.Type) bool {
+	return reflect.PointerTo(t).Implements(scannerType) || t.Implements(driverValueType)
+}
+
+// isJSONFieldType 判断字段是否自动映射为 JSON 列：map、切片（[]byte 除外）、数组、普通结构体和 json.RawMessage。
+func isJSONFieldType(t reflect.Type) bool {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == rawMessageType {
+		return true
+	}
+	if t == timeType || implementsValueType(t) {
+		return false
+	}
+	switch t.Kind() {
+	case reflect.Map, reflect.Array, reflect.Struct:
+		return true
+	case reflect.Slice:
+		return t.Elem().Kind() != reflect.Uint8
+	default:
+		return false
+	}
+}
+
+func isJSONColumnType(sqlType string) bool {
+	return strings.HasPrefix(strings.ToUpper(strings.TrimSpace(sqlType)), "JSON")
+}
+
+// parseDecimalType 解析 dorm:"decimal:18,4"，省略精度时使用 DECIMAL(18,4)。
+func parseDecimalType(field reflect.StructField, options map[string][]string) (string, error) {
+	raw := strings.TrimSpace(util.FirstNonEmpty(options["decimal"]...))
+	if raw == "" {
+		return defaultDecimalType, nil
+	}
+	parts := strings.Split(raw, ",")
+	if len(parts) > 2 {
+		return "", fmt.Errorf("orm: invalid decimal %q on field %s", raw, field.Name)
+	}
+	values := make([]string, 0, len(parts))
+	for _, part := range parts {
+		value, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil || value < 0 {
+			return "", fmt.Errorf("orm: invalid decimal %q on field %s", raw, field.Name)
+		}
+		values = append(values, strconv.Itoa(value))
+	}
+	return "DECIMAL(" + strings.Join(values, ",") + ")", nil
+}
+
+// encodeColumnValue 将写入 JSON 列的 map、切片、结构体序列化为字符串。
+func encodeColumnValue(column columnDef, value any) any {
+	if value == nil || !isJSONColumnType(column.Type) {
+		return value
+	}
+	switch v := value.(type) {
+	case string, driver.Valuer:
+		return value
+	case json.RawMessage:
+		return string(v)
+	case []byte:
+		return string(v)
+	}
+	encoded, err := json.Marshal(value)
+	panicOnError(err)
+	return string(encoded)
+}
+
+// decodeJSONField 将 JSON 列的值反序列化为字段类型。
+func decodeJSONField(value any, target reflect.Type) (reflect.Value, bool) {
+	var data []byte
+	switch v := value.(type) {
+	case string:
+		data = []byte(v)
+	case []byte:
+		data = v
+	default:
+		encoded, err := json.Marshal(v)
+		if err != nil {
+			return reflect.Value{}, false
+		}
+		data = encoded
+	}
+	if len(strings.TrimSpace(string(data))) == 0 {
+		return reflect.Zero(target), true
+	}
+	ptr := reflect.New(target)
+	if err := json.Unmarshal(data, ptr.Interface()); err != nil {
+		return reflect.Value{}, false
+	}
+	return ptr.Elem(), true
+}
+
+// scanValue 通过 sql.Scanner 转换值，用于 sql.NullString、第三方 decimal 等类型。
+func scanValue(value any, target reflect.Type) (reflect.Value, bool) {
+	if !reflect.PointerTo(target).Implements(scannerType) {
+		return reflect.Value{}, false
+	}
+	ptr := reflect.New(target)
+	if err := ptr.Interface().(sql.Scanner).Scan(value); err != nil {
+		return reflect.Value{}, false
+	}
+	return ptr.Elem(), true
+}
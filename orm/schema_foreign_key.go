@@ -0,0 +1,397 @@
+package orm
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/shemic/dever/util"
+)
+
+// 外键约束：dorm:"fk:table.column;onDelete:cascade;onUpdate:cascade"。
+// mysql/postgres 通过 ALTER TABLE 增删约束，sqlite 建表时内联声明，已有表的外键变化通过重建表完成。
+// 只管理以 fk_ 开头的约束，手工创建的其他约束不会被删除。
+
+const foreignKeyPrefix = "fk_"
+
+var foreignKeyActions = map[string]string{
+	"CASCADE":     "CASCADE",
+	"SET NULL":    "SET NULL",
+	"SETNULL":     "SET NULL",
+	"SET DEFAULT": "SET DEFAULT",
+	"SETDEFAULT":  "SET DEFAULT",
+	"RESTRICT":    "RESTRICT",
+	"NO ACTION":   "NO ACTION",
+	"NOACTION":    "NO ACTION",
+}
+
+func parseForeignKey(table, column string, options map[string][]string) (*foreignKeyDef, error) {
+	if !tagExists(options, "fk") {
+		return nil, nil
+	}
+	raw := strings.TrimSpace(util.FirstNonEmpty(options["fk"]...))
+	if raw == "" {
+		return nil, fmt.Errorf("orm: fk on %s.%s requires table.column", table, column)
+	}
+	refTable, refColumn := raw, "id"
+	if idx := strings.LastIndex(raw, "."); idx > 0 {
+		refTable, refColumn = strings.TrimSpace(raw[:idx]), strings.TrimSpace(raw[idx+1:])
+	}
+	if err := ensureIdentifier(refTable); err != nil {
+		return nil, err
+	}
+	if err := ensureIdentifier(refColumn); err != nil {
+		return nil, err
+	}
+	onDelete, err := normalizeForeignKeyAction(util.FirstNonEmpty(options["ondelete"]...))
+	if err != nil {
+		return nil, err
+	}
+	onUpdate, err := normalizeForeignKeyAction(util.FirstNonEmpty(options["onupdate"]...))
+	if err != nil {
+		return nil, err
+	}
+	return &foreignKeyDef{
+		Name:      limitIdentifier(fmt.Sprintf("%s%s_%s", foreignKeyPrefix, util.ToSnake(table), column)),
+		Column:    column,
+		RefTable:  refTable,
+		RefColumn: refColumn,
+		OnDelete:  onDelete,
+		OnUpdate:  onUpdate,
+	}, nil
+}
+
+func normalizeForeignKeyAction(raw string) (string, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return "", nil
+	}
+	key := strings.ToUpper(strings.NewReplacer("_", " ", "-", " ").Replace(raw))
+	key = strings.Join(strings.Fields(key), " ")
+	action, ok := foreignKeyActions[key]
+	if !ok {
+		return "", fmt.Errorf("orm: unsupported foreign key action %q", raw)
+	}
+	return action, nil
+}
+
+// effectiveForeignKeyAction 将未声明与 RESTRICT 视为 NO ACTION，避免不同数据库的报告差异导致反复重建。
+func effectiveForeignKeyAction(action string) string {
+	action = strings.ToUpper(strings.TrimSpace(action))
+	if action == "" || action == "RESTRICT" {
+		return "NO ACTION"
+	}
+	return action
+}
+
+func sameForeignKey(existing, desired foreignKeyDef) bool {
+	return strings.EqualFold(existing.Column, desired.Column) &&
+		strings.EqualFold(existing.RefTable, desired.RefTable) &&
+		strings.EqualFold(existing.RefColumn, desired.RefColumn) &&
+		effectiveForeignKeyAction(existing.OnDelete) == effectiveForeignKeyAction(desired.OnDelete) &&
+		effectiveForeignKeyAction(existing.OnUpdate) == effectiveForeignKeyAction(desired.OnUpdate)
+}
+
+func findForeignKey(items []foreignKeyDef, name string) (foreignKeyDef, bool) {
+	for _, item := range items {
+		if strings.EqualFold(item.Name, name) {
+			return item, true
+		}
+	}
+	return foreignKeyDef{}, false
+}
+
+// withForeignKeyIndexes 为没有可用索引的外键列补充普通索引，mysql 要求外键列有索引，其他数据库也能加速级联操作。
+func withForeignKeyIndexes(table string, columns []columnDef, indexes []indexDef, foreignKeys []foreignKeyDef) []indexDef {
+	for _, foreignKey := range foreignKeys {
+		covered := false
+		for _, column := range columns {
+			if column.Primary && strings.EqualFold(column.Name, foreignKey.Column) {
+				covered = true
+			}
+		}
+		for _, index := range indexes {
+			if len(index.Columns) > 0 && strings.EqualFold(index.Columns[0], foreignKey.Column) {
+				covered = true
+			}
+		}
+		if !covered {
+			indexes = append(indexes, indexDef{Name: defaultIndexName(table, foreignKey.Column, false), Columns: []string{foreignKey.Column}})
+		}
+	}
+	return indexes
+}
+
+func buildForeignKeyClause(driver string, foreignKey foreignKeyDef) string {
+	clause := fmt.Sprintf("FOREIGN KEY (%s) REFERENCES %s (%s)",
+		quoteIdentifier(driver, foreignKey.Column),
+		quoteIdentifier(driver, foreignKey.RefTable),
+		quoteIdentifier(driver, foreignKey.RefColumn))
+	if foreignKey.OnDelete != "" {
+		clause += " ON DELETE " + foreignKey.OnDelete
+	}
+	if foreignKey.OnUpdate != "" {
+		clause += " ON UPDATE " + foreignKey.OnUpdate
+	}
+	return clause
+}
+
+func buildAddForeignKeyStatement(driver, table string, foreignKey foreignKeyDef) string {
+	return fmt.Sprintf("ALTER TABLE %s ADD CONSTRAINT %s %s",
+		quoteIdentifier(driver, table), quoteIdentifier(driver, foreignKey.Name), buildForeignKeyClause(driver, foreignKey))
+}
+
+func buildDropForeignKeyStatement(driver, table, name string) string {
+	switch driver {
+	case "mysql":
+		return fmt.Sprintf("ALTER TABLE %s DROP FOREIGN KEY %s", quoteIdentifier(driver, table), quoteIdentifier(driver, name))
+	case "postgres":
+		return fmt.Sprintf("ALTER TABLE %s DROP CONSTRAINT IF EXISTS %s", quoteIdentifier(driver, table), quoteIdentifier(driver, name))
+	default:
+		return ""
+	}
+}
+
+// dropObsoleteForeignKeys 在同步列之前删除已不需要或定义变化的外键（mysql/postgres），避免阻塞列的修改与删除。
+func dropObsoleteForeignKeys(ctx context.Context, db *schemaConn, driver, table string, desired []foreignKeyDef) ([]string, error) {
+	if driver != "mysql" && driver != "postgres" {
+		return nil, nil
+	}
+	existing, err := loadExistingForeignKeys(ctx, db.DB, driver, table)
+	if err != nil {
+		return nil, err
+	}
+	var statements []string
+	for _, foreignKey := range existing {
+		if !strings.HasPrefix(strings.ToLower(foreignKey.Name), foreignKeyPrefix) {
+			continue
+		}
+		if target, ok := findForeignKey(desired, foreignKey.Name); ok && sameForeignKey(foreignKey, target) {
+			continue
+		}
+		stmt := buildDropForeignKeyStatement(driver, table, foreignKey.Name)
+		change := SchemaChange{Table: table, Kind: SchemaDropForeignKey, Up: []string{stmt}, Down: []string{buildAddForeignKeyStatement(driver, table, foreignKey)}}
+		if err := db.apply(ctx, change); err != nil {
+			return nil, err
+		}
+		statements = append(statements, stmt)
+	}
+	return statements, nil
+}
+
+// addMissingForeignKeys 在索引同步之后补齐外键（mysql/postgres）；被引用的表尚不存在时跳过，待其创建后再次同步时补齐。
+func addMissingForeignKeys(ctx context.Context, db *schemaConn, driver, table string, desired []foreignKeyDef) ([]string, error) {
+	if (driver != "mysql" && driver != "postgres") || len(desired) == 0 {
+		return nil, nil
+	}
+	var existing []foreignKeyDef
+	if !db.created(table) {
+		loaded, err := loadExistingForeignKeys(ctx, db.DB, driver, table)
+		if err != nil {
+			return nil, err
+		}
+		existing = loaded
+	}
+	var statements []string
+	for _, foreignKey := range desired {
+		if current, ok := findForeignKey(existing, foreignKey.Name); ok && sameForeignKey(current, foreignKey) {
+			continue
+		}
+		if !db.plan && !strings.EqualFold(foreignKey.RefTable, table) {
+			exists, err := tableExists(ctx, db.DB, driver, foreignKey.RefTable)
+			if err != nil {
+				return nil, err
+			}
+			if !exists {
+				continue
+			}
+		}
+		stmt := buildAddForeignKeyStatement(driver, table, foreignKey)
+		change := SchemaChange{Table: table, Kind: SchemaAddForeignKey, Up: []string{stmt}, Down: []string{buildDropForeignKeyStatement(driver, table, foreignKey.Name)}}
+		if err := db.apply(ctx, change); err != nil {
+			return nil, err
+		}
+		statements = append(statements, stmt)
+	}
+	return statements, nil
+}
+
+// rebuildSQLiteForeignKeys 在 sqlite 已有表的外键与声明不一致时重建表：按新定义建临时表、复制数据、删除旧表并改名。
+func rebuildSQLiteForeignKeys(ctx context.Context, db *schemaConn, table string, columns []columnDef, desired []foreignKeyDef) ([]string, error) {
+	const driver = "sqlite"
+	existing, err := loadExistingForeignKeys(ctx, db.DB, driver, table)
+	if err != nil {
+		return nil, err
+	}
+	if sameForeignKeySet(existing, desired) {
+		return nil, nil
+	}
+	existingColumns, err := loadExistingColumns(ctx, db.DB, driver, table)
+	if err != nil {
+		return nil, err
+	}
+	temp := table + "__dever_rebuild"
+	targets := make([]string, 0, len(columns))
+	sources := make([]string, 0, len(columns))
+	for _, column := range columns {
+		actual, ok := findExistingColumn(existingColumns, column.Name)
+		if !ok {
+			continue
+		}
+		targets = append(targets, quoteIdentifier(driver, column.Name))
+		sources = append(sources, quoteIdentifier(driver, db.columnName(table, actual)))
+	}
+	statements := []string{
+		buildCreateTableStatement(driver, temp, columns, desired),
+		fmt.Sprintf("INSERT INTO %s (%s) SELECT %s FROM %s", quoteIdentifier(driver, temp), strings.Join(targets, ", "), strings.Join(sources, ", "), quoteIdentifier(driver, table)),
+		buildDropTableStatement(driver, table),
+		fmt.Sprintf("ALTER TABLE %s RENAME TO %s", quoteIdentifier(driver, temp), quoteIdentifier(driver, table)),
+	}
+	change := SchemaChange{Table: table, Kind: SchemaRebuildTable, Up: statements, Irreversible: true}
+	if db.plan {
+		db.record(change)
+		// 重建后旧索引随旧表删除，plan 模式按新表计划索引。
+		db.markCreated(table)
+		return statements, nil
+	}
+	if err := execSQLiteRebuild(ctx, db.DB, statements); err != nil {
+		return nil, err
+	}
+	db.record(change)
+	return statements, nil
+}
+
+// execSQLiteRebuild 在同一连接上关闭外键检查后以事务执行重建语句，避免删除旧表时触发级联删除。
+func execSQLiteRebuild(ctx context.Context, db *sqlx.DB, statements []string) error {
+	conn, err := db.Connx(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	var enabled int
+	if err := conn.QueryRowxContext(ctx, "PRAGMA foreign_keys").Scan(&enabled); err != nil {
+		return err
+	}
+	if enabled == 1 {
+		if _, err := conn.ExecContext(ctx, "PRAGMA foreign_keys = OFF"); err != nil {
+			return err
+		}
+		defer conn.ExecContext(context.WithoutCancel(ctx), "PRAGMA foreign_keys = ON")
+	}
+	tx, err := conn.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	for _, stmt := range statements {
+		if _, err := tx.ExecContext(ctx, stmt); err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func sameForeignKeySet(existing, desired []foreignKeyDef) bool {
+	if len(existing) != len(desired) {
+		return false
+	}
+	for _, target := range desired {
+		matched := false
+		for _, current := range existing {
+			if sameForeignKey(current, target) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
+
+func loadExistingForeignKeys(ctx context.Context, db *sqlx.DB, driver, table string) ([]foreignKeyDef, error) {
+	if err := ensureIdentifier(table); err != nil {
+		return nil, err
+	}
+	var query string
+	var args []any
+	switch driver {
+	case "postgres":
+		query = `
+SELECT con.conname, att.attname, ref.relname, refatt.attname,
+       CASE con.confdeltype WHEN 'c' THEN 'CASCADE' WHEN 'n' THEN 'SET NULL' WHEN 'd' THEN 'SET DEFAULT' WHEN 'r' THEN 'RESTRICT' ELSE 'NO ACTION' END,
+       CASE con.confupdtype WHEN 'c' THEN 'CASCADE' WHEN 'n' THEN 'SET NULL' WHEN 'd' THEN 'SET DEFAULT' WHEN 'r' THEN 'RESTRICT' ELSE 'NO ACTION' END
+FROM pg_constraint con
+JOIN pg_class tbl ON tbl.oid = con.conrelid
+JOIN pg_namespace ns ON ns.oid = tbl.relnamespace
+JOIN pg_attribute att ON att.attrelid = con.conrelid AND att.attnum = con.conkey[1]
+JOIN pg_class ref ON ref.oid = con.confrelid
+JOIN pg_attribute refatt ON refatt.attrelid = con.confrelid AND refatt.attnum = con.confkey[1]
+WHERE con.contype = 'f' AND ns.nspname = current_schema() AND tbl.relname = $1`
+		args = []any{table}
+	case "mysql":
+		query = `
+SELECT k.constraint_name, k.column_name, k.referenced_table_name, k.referenced_column_name, r.delete_rule, r.update_rule
+FROM information_schema.key_column_usage k
+JOIN information_schema.referential_constraints r ON r.constraint_schema = k.constraint_schema AND r.constraint_name = k.constraint_name
+WHERE k.table_schema = DATABASE() AND k.table_name = ? AND k.referenced_table_name IS NOT NULL`
+		args = []any{table}
+	case "sqlite":
+		query = fmt.Sprintf("SELECT \"table\", \"from\", \"to\", on_delete, on_update FROM pragma_foreign_key_list(%s)", formatDefaultValue(table, false))
+	default:
+		return nil, fmt.Errorf("orm: unsupported driver %s", driver)
+	}
+	rows, err := db.QueryxContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var result []foreignKeyDef
+	for rows.Next() {
+		var foreignKey foreignKeyDef
+		if driver == "sqlite" {
+			err = rows.Scan(&foreignKey.RefTable, &foreignKey.Column, &foreignKey.RefColumn, &foreignKey.OnDelete, &foreignKey.OnUpdate)
+		} else {
+			err = rows.Scan(&foreignKey.Name, &foreignKey.Column, &foreignKey.RefTable, &foreignKey.RefColumn, &foreignKey.OnDelete, &foreignKey.OnUpdate)
+		}
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, foreignKey)
+	}
+	return result, rows.Err()
+}
+
+// sortSchemasByDependency 按外键依赖排序，被引用的表排在前面；存在循环依赖时保持原有顺序。
+func sortSchemasByDependency(schemas []*tableSchema) []*tableSchema {
+	byName := make(map[string]int, len(schemas))
+	for i, schema := range schemas {
+		if schema != nil {
+			byName[strings.ToLower(schema.Table)] = i
+		}
+	}
+	result := make([]*tableSchema, 0, len(schemas))
+	state := make([]int, len(schemas)) // 0 未访问，1 访问中，2 已完成
+	var visit func(int)
+	visit = func(i int) {
+		if state[i] != 0 {
+			return
+		}
+		state[i] = 1
+		if schema := schemas[i]; schema != nil {
+			for _, foreignKey := range schema.ForeignKeys {
+				if dep, ok := byName[strings.ToLower(foreignKey.RefTable)]; ok && dep != i {
+					visit(dep)
+				}
+			}
+		}
+		state[i] = 2
+		result = append(result, schemas[i])
+	}
+	for i := range schemas {
+		visit(i)
+	}
+	return result
+}
@@ -0,0 +1,140 @@
+package orm
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+)
+
+func TestGeneratedConstraintNamesRespectIdentifierLimit(t *testing.T) {
+	fk, err := parseForeignKey("Order", "user_id", map[string][]string{"fk": {"user.id"}})
+	if err != nil || fk.Name != "fk_order_user_id" {
+		t.Fatalf("short fk = %+v, %v", fk, err)
+	}
+	if got := defaultIndexName("order", "user_id", true); got != "uidx_order_user_id" {
+		t.Fatalf("short index name = %s", got)
+	}
+
+	table := strings.Repeat("warehouse_inventory_", 3)
+	long, err := parseForeignKey(table, "supplier_contact_id", map[string][]string{"fk": {"supplier_contact.id"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := parseForeignKey(table, "supplier_address_id", map[string][]string{"fk": {"supplier_address.id"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{long.Name, other.Name, defaultIndexName(table, "supplier_contact_id", false)} {
+		if len(name) > maxIdentifierLength {
+			t.Errorf("%s has %d bytes, want <= %d", name, len(name), maxIdentifierLength)
+		}
+	}
+	if !strings.HasPrefix(long.Name, foreignKeyPrefix) {
+		t.Errorf("truncated fk %s lost prefix %s", long.Name, foreignKeyPrefix)
+	}
+	if long.Name == other.Name {
+		t.Errorf("different columns share truncated name %s", long.Name)
+	}
+	if again := limitIdentifier(foreignKeyPrefix + table + "_supplier_contact_id"); again != long.Name {
+		t.Errorf("truncation not deterministic: %s vs %s", again, long.Name)
+	}
+}
+
+func TestSortSchemasByDependency(t *testing.T) {
+	schema := func(table string, refs ...string) *tableSchema {
+		result := &tableSchema{Table: table}
+		for _, ref := range refs {
+			result.ForeignKeys = append(result.ForeignKeys, foreignKeyDef{Column: ref + "_id", RefTable: ref, RefColumn: "id"})
+		}
+		return result
+	}
+	names := func(schemas []*tableSchema) []string {
+		result := make([]string, 0, len(schemas))
+		for _, item := range schemas {
+			result = append(result, item.Table)
+		}
+		return result
+	}
+	cases := []struct {
+		name    string
+		schemas []*tableSchema
+		want    []string
+	}{
+		{"chain", []*tableSchema{schema("order_item", "order", "product"), schema("order", "user"), schema("product"), schema("user")},
+			[]string{"user", "order", "product", "order_item"}},
+		{"self reference", []*tableSchema{schema("category", "category"), schema("post", "category")},
+			[]string{"category", "post"}},
+		{"external reference", []*tableSchema{schema("post", "account"), schema("tag")},
+			[]string{"post", "tag"}},
+		{"cycle", []*tableSchema{schema("a", "b"), schema("b", "a"), schema("c")},
+			[]string{"b", "a", "c"}},
+	}
+	for _, tc := range cases {
+		if got := names(sortSchemasByDependency(tc.schemas)); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("%s: order = %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestSQLiteRebuildAddsForeignKeyAndKeepsRows(t *testing.T) {
+	ctx := context.Background()
+	db, err := sqlx.Open("sqlite3", "file:"+filepath.Join(t.TempDir(), "rebuild.db")+"?_foreign_keys=1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	for _, stmt := range []string{
+		`CREATE TABLE "team" ("id" INTEGER PRIMARY KEY)`,
+		`CREATE TABLE "member" ("id" INTEGER PRIMARY KEY AUTOINCREMENT, "team_id" INTEGER, "name" TEXT)`,
+		`INSERT INTO "team" ("id") VALUES (1)`,
+		`INSERT INTO "member" ("team_id", "name") VALUES (1, 'a'), (1, 'b')`,
+	} {
+		if _, err := db.Exec(stmt); err != nil {
+			t.Fatal(err)
+		}
+	}
+	fk, err := parseForeignKey("member", "team_id", map[string][]string{"fk": {"team.id"}, "ondelete": {"cascade"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	schema := &tableSchema{
+		Table: "member",
+		Columns: []columnDef{
+			{Name: "id", Type: "INTEGER", Primary: true, AutoIncrement: true},
+			{Name: "team_id", Type: "INTEGER"},
+			{Name: "name", Type: "TEXT"},
+		},
+		ForeignKeys: []foreignKeyDef{*fk},
+	}
+	conn := newSchemaConn(db, false)
+	if _, err := syncTableSchema(ctx, conn, "sqlite", "member", schema); err != nil {
+		t.Fatal(err)
+	}
+
+	existing, err := loadExistingForeignKeys(ctx, db, "sqlite", "member")
+	if err != nil || !sameForeignKeySet(existing, schema.ForeignKeys) {
+		t.Fatalf("foreign keys after rebuild = %+v, %v", existing, err)
+	}
+	var names []string
+	if err := db.Select(&names, `SELECT "name" FROM "member" ORDER BY "id"`); err != nil || !reflect.DeepEqual(names, []string{"a", "b"}) {
+		t.Fatalf("rows after rebuild = %v, %v", names, err)
+	}
+	if _, err := db.Exec(`INSERT INTO "member" ("team_id", "name") VALUES (9, 'x')`); err == nil {
+		t.Fatal("dangling team_id accepted after rebuild")
+	}
+
+	// 外键已一致时不再重建。
+	conn = newSchemaConn(db, false)
+	if _, err := syncTableSchema(ctx, conn, "sqlite", "member", schema); err != nil {
+		t.Fatal(err)
+	}
+	for _, change := range conn.changes {
+		if change.Kind == SchemaRebuildTable {
+			t.Fatalf("unchanged foreign keys rebuilt again: %+v", change)
+		}
+	}
+}
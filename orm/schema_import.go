@@ -0,0 +1,432 @@
+package orm
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// 反向生成模型：按 schema_apply 的查询读取已有表的列、索引与外键，推导字段类型和 dorm 标签。
+
+// ImportedModel 描述一张已有表对应的模型。Table 为去掉表前缀后传给 LoadModel 的表名，
+// Name 为模型名称（优先使用表注释），Struct 为结构体名。
+type ImportedModel struct {
+	Database string
+	Table    string
+	Name     string
+	Struct   string
+	Fields   []ImportedField
+	Indexes  []ImportedIndex
+	schema   *tableSchema
+}
+
+// ImportedField 为结构体字段，Tag 为完整的结构体标签，Import 为字段类型需要的包。
+type ImportedField struct {
+	Name   string
+	Column string
+	GoType string
+	Tag    string
+	Import string
+	typ    reflect.Type
+}
+
+// ImportedIndex 为索引结构体中的一个字段，Columns 按索引列顺序以逗号分隔。
+type ImportedIndex struct {
+	Field   string
+	Name    string
+	Columns string
+	Unique  bool
+}
+
+var (
+	bytesType           = reflect.TypeOf([]byte(nil))
+	postgresCastPattern = regexp.MustCompile(`::[a-zA-Z_ ]+(\[\])?$`)
+	goInitialisms       = map[string]string{"id": "ID", "ip": "IP", "url": "URL", "uri": "URI", "uuid": "UUID", "api": "API", "json": "JSON", "sql": "SQL", "http": "HTTP"}
+)
+
+// ImportModels 读取指定数据库中的表结构并推导模型定义；tables 为空时导入全部表（不含迁移历史表）。
+func ImportModels(ctx context.Context, dbName string, tables []string) ([]ImportedModel, error) {
+	ctx = normalizeContext(ctx)
+	dbName = migrationDatabase(dbName)
+	db, err := Get(dbName)
+	if err != nil {
+		return nil, err
+	}
+	driver := normalizeDriver(db.DriverName())
+	existing, err := listDatabaseTables(ctx, db, driver)
+	if err != nil {
+		return nil, err
+	}
+	available := make(map[string]string, len(existing))
+	for _, table := range existing {
+		if strings.EqualFold(table, migrationHistoryTable) {
+			continue
+		}
+		available[strings.ToLower(table)] = table
+	}
+	if len(tables) == 0 {
+		for _, table := range available {
+			tables = append(tables, table)
+		}
+	}
+	sort.Strings(tables)
+
+	prefix := getDatabasePrefix(dbName)
+	models := make([]ImportedModel, 0, len(tables))
+	for _, name := range tables {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		table, ok := available[strings.ToLower(name)]
+		if !ok && prefix != "" {
+			table, ok = available[strings.ToLower(applyTablePrefix(name, dbName))]
+		}
+		if !ok {
+			return nil, fmt.Errorf("orm: table %s not found in database %s", name, dbName)
+		}
+		model, err := importTableModel(ctx, db, driver, dbName, prefix, table)
+		if err != nil {
+			return nil, fmt.Errorf("orm: import table %s failed: %w", table, err)
+		}
+		models = append(models, model)
+	}
+	return models, nil
+}
+
+// Record 将模型对应的表结构写入 data/table，与加载生成的模型时记录的内容一致。
+func (m ImportedModel) Record() error {
+	if m.schema == nil {
+		return fmt.Errorf("orm: imported model %s has no schema", m.Table)
+	}
+	return persistSchema(m.schema)
+}
+
+func importTableModel(ctx context.Context, db *sqlx.DB, driver, dbName, prefix, table string) (ImportedModel, error) {
+	shortTable := stripTablePrefix(table, prefix)
+	model := ImportedModel{
+		Database: dbName,
+		Table:    shortTable,
+		Name:     shortTable,
+		Struct:   goIdentifier(shortTable),
+	}
+	comment, err := loadTableComment(ctx, db, driver, table)
+	if err != nil {
+		return model, err
+	}
+	if comment != "" {
+		model.Name = comment
+	}
+
+	states, err := loadExistingColumnStates(ctx, db, driver, table)
+	if err != nil {
+		return model, err
+	}
+	columns := make([]existingColumnState, 0, len(states))
+	for _, state := range states {
+		columns = append(columns, state)
+	}
+	sort.Slice(columns, func(i, j int) bool {
+		return columns[i].Position < columns[j].Position
+	})
+
+	foreignKeys, err := loadExistingForeignKeys(ctx, db, driver, table)
+	if err != nil {
+		return model, err
+	}
+	foreignKeyByColumn := make(map[string]foreignKeyDef, len(foreignKeys))
+	for _, foreignKey := range foreignKeys {
+		foreignKeyByColumn[strings.ToLower(foreignKey.Column)] = foreignKey
+	}
+
+	usedNames := map[string]int{}
+	for _, state := range columns {
+		field := importField(driver, state)
+		field.Name = uniqueIdentifier(goIdentifier(state.Name), usedNames)
+		if foreignKey, ok := foreignKeyByColumn[strings.ToLower(state.Name)]; ok {
+			field.Tag = appendImportedTag(field.Tag, importForeignKeyTag(foreignKey, prefix)...)
+		}
+		model.Fields = append(model.Fields, field)
+	}
+
+	indexes, err := loadExistingIndexes(ctx, db, driver, table)
+	if err != nil {
+		return model, err
+	}
+	indexNames := map[string]int{}
+	for _, key := range sortedIndexKeys(indexes) {
+		index := indexes[key]
+		name := index.Name
+		// sqlite 为 UNIQUE 约束自动创建的索引不能按原名重建，改用默认命名。
+		if strings.HasPrefix(strings.ToLower(name), "sqlite_autoindex_") {
+			name = defaultIndexName(table, strings.Join(index.Columns, "_"), index.Unique)
+		}
+		model.Indexes = append(model.Indexes, ImportedIndex{
+			Field:   uniqueIdentifier(goIdentifier(name), indexNames),
+			Name:    name,
+			Columns: strings.Join(index.Columns, ","),
+			Unique:  index.Unique,
+		})
+	}
+
+	schema, err := buildImportedSchema(table, dbName, model)
+	if err != nil {
+		return model, err
+	}
+	model.schema = schema
+	return model, nil
+}
+
+// importField 按列类型推导字段类型与 dorm 标签；推导出的默认类型与数据库不一致时显式声明 type。
+func importField(driver string, state existingColumnState) ImportedField {
+	info := parseColumnType(state.Type)
+	declared := strings.Join(strings.Fields(strings.ToUpper(state.Type)), " ")
+	var options []string
+	var typ reflect.Type
+	goType, imp := "", ""
+	switch info.family {
+	case "bool":
+		typ, goType = reflect.TypeOf(false), "bool"
+	case "int":
+		typ, goType = reflect.TypeOf(int64(0)), "int64"
+		if strings.Contains(declared, "UNSIGNED") {
+			typ, goType = reflect.TypeOf(uint64(0)), "uint64"
+		}
+	case "float":
+		typ, goType = reflect.TypeOf(float64(0)), "float64"
+	case "decimal":
+		typ, goType = reflect.TypeOf(""), "string"
+		if strings.Contains(declared, "(") {
+			options = append(options, fmt.Sprintf("decimal:%d,%d", info.size, info.scale))
+		}
+	case "text":
+		typ, goType = reflect.TypeOf(""), "string"
+		if strings.HasPrefix(normalizeColumnType(declared), "VARCHAR(") {
+			options = append(options, fmt.Sprintf("size:%d", info.size))
+		}
+	case "json":
+		typ, goType, imp = rawMessageType, "json.RawMessage", "encoding/json"
+	case "blob":
+		typ, goType = bytesType, "[]byte"
+	case "time":
+		typ, goType, imp = timeType, "time.Time", "time"
+	default:
+		typ, goType = reflect.TypeOf(""), "string"
+	}
+
+	if state.Primary {
+		options = append(options, "primaryKey")
+	}
+	if state.AutoIncrement {
+		options = append(options, "autoIncrement")
+	}
+	// 可空列使用指针类型；JSON 与二进制列保持值类型，通过 null 标签声明可空。
+	if info.family == "json" || info.family == "blob" {
+		if state.Nullable {
+			options = append(options, "null")
+		}
+	} else if state.Nullable {
+		typ, goType = reflect.PointerTo(typ), "*"+goType
+	}
+
+	if !state.AutoIncrement {
+		probe := reflect.StructField{Name: "Field", Type: typ}
+		inferred, _, err := inferSQLType(probe, parseDormTag(strings.Join(options, ";")))
+		declaredType := importDeclaredType(driver, declared)
+		// 只有显式类型换算后能与数据库一致时才声明 type（如 sqlite 的 JSON 列无论如何都按 TEXT 建表）。
+		if (err != nil || !columnTypesMatch(state.Type, sqlTypeForDriver(inferred, driver, false))) &&
+			columnTypesMatch(state.Type, sqlTypeForDriver(declaredType, driver, false)) {
+			options = append([]string{"type:" + declaredType}, options...)
+			// 声明 type 后不再按指针推断可空。
+			if state.Nullable && info.family != "json" && info.family != "blob" {
+				options = append(options, "null")
+			}
+		}
+	}
+	if value, ok := importDefaultValue(driver, state, info.family); ok {
+		options = append(options, "default:"+value)
+	}
+	if comment := sanitizeTagValue(state.Comment); comment != "" {
+		options = append(options, "comment:"+comment)
+	}
+
+	field := ImportedField{Column: state.Name, GoType: goType, Import: imp, typ: typ}
+	field.Tag = fmt.Sprintf("db:%s", strconv.Quote(state.Name))
+	field.Tag = appendImportedTag(field.Tag, options...)
+	return field
+}
+
+// importDeclaredType 返回写入 type 标签的类型；postgres 的 TIMESTAMP 会被换算为 TIMESTAMPTZ，需使用完整写法。
+func importDeclaredType(driver, declared string) string {
+	if driver == "postgres" && declared == "TIMESTAMP" {
+		return "TIMESTAMP WITHOUT TIME ZONE"
+	}
+	return declared
+}
+
+// appendImportedTag 向结构体标签的 dorm 部分追加选项。
+func appendImportedTag(tag string, options ...string) string {
+	if len(options) == 0 {
+		return tag
+	}
+	dorm := reflect.StructTag(tag).Get("dorm")
+	if dorm != "" {
+		tag = strings.TrimSuffix(tag, " dorm:"+strconv.Quote(dorm))
+		options = append([]string{dorm}, options...)
+	}
+	return tag + " dorm:" + strconv.Quote(strings.Join(options, ";"))
+}
+
+func importForeignKeyTag(foreignKey foreignKeyDef, prefix string) []string {
+	options := []string{fmt.Sprintf("fk:%s.%s", stripTablePrefix(foreignKey.RefTable, prefix), foreignKey.RefColumn)}
+	if action := effectiveForeignKeyAction(foreignKey.OnDelete); action != "NO ACTION" {
+		options = append(options, "onDelete:"+strings.ToLower(action))
+	}
+	if action := effectiveForeignKeyAction(foreignKey.OnUpdate); action != "NO ACTION" {
+		options = append(options, "onUpdate:"+strings.ToLower(action))
+	}
+	return options
+}
+
+// importDefaultValue 将数据库报告的默认值转换为 dorm 标签写法，字符串类默认值统一加单引号。
+func importDefaultValue(driver string, state existingColumnState, family string) (string, bool) {
+	if state.Default == nil || state.AutoIncrement {
+		return "", false
+	}
+	value := strings.TrimSpace(*state.Default)
+	if driver == "postgres" {
+		value = postgresCastPattern.ReplaceAllString(value, "")
+		if strings.HasPrefix(value, "(") && strings.HasSuffix(value, ")") {
+			value = strings.TrimSpace(value[1 : len(value)-1])
+		}
+	}
+	if value == "" && driver != "mysql" {
+		return "", false
+	}
+	if strings.EqualFold(value, "NULL") {
+		return "", false
+	}
+	quoted := len(value) >= 2 && value[0] == '\'' && value[len(value)-1] == '\''
+	switch family {
+	case "text", "decimal", "json":
+		if !quoted {
+			value = "'" + strings.ReplaceAll(value, "'", "''") + "'"
+		}
+	case "time":
+		if strings.EqualFold(value, "now()") {
+			value = "CURRENT_TIMESTAMP"
+		}
+	case "bool":
+		switch value {
+		case "1", "'1'", "b'1'":
+			value = "true"
+		case "0", "'0'", "b'0'":
+			value = "false"
+		}
+	}
+	if strings.ContainsAny(value, ";`\"") {
+		return "", false
+	}
+	return value, true
+}
+
+// buildImportedSchema 用导入的字段动态构造结构体，走与 LoadModel 相同的 buildSchema 得到表结构记录。
+func buildImportedSchema(table, dbName string, model ImportedModel) (*tableSchema, error) {
+	fields := make([]reflect.StructField, 0, len(model.Fields))
+	for _, field := range model.Fields {
+		fields = append(fields, reflect.StructField{Name: field.Name, Type: field.typ, Tag: reflect.StructTag(field.Tag)})
+	}
+	options := schemaOptions{database: dbName}
+	if len(model.Indexes) > 0 {
+		indexFields := make([]reflect.StructField, 0, len(model.Indexes))
+		for _, index := range model.Indexes {
+			indexFields = append(indexFields, reflect.StructField{
+				Name: index.Field,
+				Type: reflect.TypeOf(struct{}{}),
+				Tag:  reflect.StructTag(index.Tag()),
+			})
+		}
+		options.indexes = []any{reflect.New(reflect.StructOf(indexFields)).Elem().Interface()}
+	}
+	value := reflect.New(reflect.StructOf(fields)).Elem().Interface()
+	return buildSchema(table, value, options)
+}
+
+// Tag 返回索引结构体字段的标签。
+func (i ImportedIndex) Tag() string {
+	key := "index"
+	if i.Unique {
+		key = "unique"
+	}
+	return fmt.Sprintf("%s:%s name:%s", key, strconv.Quote(i.Columns), strconv.Quote(i.Name))
+}
+
+func loadTableComment(ctx context.Context, db *sqlx.DB, driver, table string) (string, error) {
+	var query string
+	switch driver {
+	case "postgres":
+		query = "SELECT COALESCE(obj_description((quote_ident(current_schema()) || '.' || quote_ident($1))::regclass, 'pg_class'), '')"
+	case "mysql":
+		query = "SELECT COALESCE(table_comment, '') FROM information_schema.tables WHERE table_schema = DATABASE() AND table_name = ?"
+	default:
+		return "", nil
+	}
+	var comment string
+	if err := db.GetContext(ctx, &comment, query, table); err != nil {
+		return "", err
+	}
+	return sanitizeTagValue(comment), nil
+}
+
+func stripTablePrefix(table, prefix string) string {
+	if prefix == "" {
+		return table
+	}
+	if trimmed := strings.TrimPrefix(table, prefix+"_"); trimmed != "" {
+		return trimmed
+	}
+	return table
+}
+
+// sanitizeTagValue 去掉会破坏 dorm 标签或 Go 源码的字符。
+func sanitizeTagValue(value string) string {
+	value = strings.NewReplacer(";", "，", "`", "'", "\"", "'", "\n", " ", "\r", " ").Replace(value)
+	return strings.TrimSpace(value)
+}
+
+// goIdentifier 将下划线命名转换为导出的 Go 标识符，常见缩写保持大写。
+func goIdentifier(name string) string {
+	parts := strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	var builder strings.Builder
+	for _, part := range parts {
+		if upper, ok := goInitialisms[strings.ToLower(part)]; ok {
+			builder.WriteString(upper)
+			continue
+		}
+		runes := []rune(part)
+		runes[0] = unicode.ToUpper(runes[0])
+		builder.WriteString(string(runes))
+	}
+	result := builder.String()
+	if result == "" || !unicode.IsLetter([]rune(result)[0]) {
+		result = "F" + result
+	}
+	return result
+}
+
+func uniqueIdentifier(name string, used map[string]int) string {
+	used[name]++
+	if count := used[name]; count > 1 {
+		return name + strconv.Itoa(count)
+	}
+	return name
+}
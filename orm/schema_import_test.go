@@ -0,0 +1,113 @@
+package orm
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestImportModelsInfersFieldsAndRoundTrips(t *testing.T) {
+	const dbName = "import_models"
+	dir := t.TempDir()
+	t.Chdir(dir)
+	db, err := Init(dbName, Config{Driver: "sqlite3", Path: filepath.Join(dir, dbName+".db"), Prefix: "app"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = Close(dbName) })
+	for _, stmt := range []string{
+		`CREATE TABLE "app_user_group" ("id" INTEGER PRIMARY KEY AUTOINCREMENT, "name" VARCHAR(32) NOT NULL DEFAULT '')`,
+		`CREATE TABLE "app_user_profile" (
+			"id" INTEGER PRIMARY KEY AUTOINCREMENT,
+			"user_group_id" INTEGER NOT NULL REFERENCES "app_user_group" ("id") ON DELETE CASCADE,
+			"nickname" VARCHAR(64),
+			"score" DECIMAL(10,2) NOT NULL DEFAULT 0,
+			"meta" JSON,
+			"avatar" BLOB NOT NULL,
+			"created_at" DATETIME NOT NULL,
+			"api_url" TEXT NOT NULL DEFAULT 'x'
+		)`,
+		`CREATE UNIQUE INDEX "uidx_profile_nickname" ON "app_user_profile" ("nickname")`,
+		`CREATE INDEX "idx_app_user_profile_user_group_id" ON "app_user_profile" ("user_group_id")`,
+	} {
+		if _, err := db.Exec(stmt); err != nil {
+			t.Fatal(err)
+		}
+	}
+	ctx := context.Background()
+
+	models, err := ImportModels(ctx, dbName, []string{"user_profile"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(models) != 1 {
+		t.Fatalf("models = %d, want 1", len(models))
+	}
+	model := models[0]
+	if model.Table != "user_profile" || model.Struct != "UserProfile" {
+		t.Fatalf("model = %s/%s", model.Table, model.Struct)
+	}
+	var got []string
+	for _, field := range model.Fields {
+		got = append(got, field.Name+" "+field.GoType+" "+field.Tag)
+	}
+	want := []string{
+		`ID int64 db:"id" dorm:"primaryKey;autoIncrement"`,
+		`UserGroupID int64 db:"user_group_id" dorm:"fk:user_group.id;onDelete:cascade"`,
+		`Nickname *string db:"nickname" dorm:"size:64"`,
+		`Score string db:"score" dorm:"decimal:10,2;default:'0'"`,
+		`Meta json.RawMessage db:"meta" dorm:"null"`,
+		`Avatar []byte db:"avatar"`,
+		`CreatedAt time.Time db:"created_at"`,
+		`APIURL string db:"api_url" dorm:"default:'x'"`,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("fields =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+	var indexes []string
+	for _, index := range model.Indexes {
+		indexes = append(indexes, index.Field+" "+index.Tag())
+	}
+	wantIndexes := []string{
+		`IdxAppUserProfileUserGroupID index:"user_group_id" name:"idx_app_user_profile_user_group_id"`,
+		`UidxProfileNickname unique:"nickname" name:"uidx_profile_nickname"`,
+	}
+	if !reflect.DeepEqual(indexes, wantIndexes) {
+		t.Fatalf("indexes = %q, want %q", indexes, wantIndexes)
+	}
+
+	// 导入结果生成的表结构与数据库一致，同步时不应产生任何变更。
+	conn := newSchemaConn(db, true)
+	if _, err := syncTableSchema(ctx, conn, "sqlite", model.schema.Table, model.schema); err != nil {
+		t.Fatal(err)
+	}
+	if len(conn.changes) != 0 {
+		t.Fatalf("imported schema differs from database: %+v", conn.changes)
+	}
+
+	all, err := ImportModels(ctx, dbName, nil)
+	if err != nil || len(all) != 2 || all[0].Table != "user_group" {
+		t.Fatalf("import all = %+v, %v", all, err)
+	}
+	if _, err := ImportModels(ctx, dbName, []string{"missing"}); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Fatalf("missing table err = %v", err)
+	}
+}
+
+func TestGoIdentifier(t *testing.T) {
+	cases := map[string]string{
+		"user_id":      "UserID",
+		"api_url":      "APIURL",
+		"order-items":  "OrderItems",
+		"2fa_code":     "F2faCode",
+		"uuid":         "UUID",
+		"created_at_1": "CreatedAt1",
+	}
+	for input, want := range cases {
+		if got := goIdentifier(input); got != want {
+			t.Errorf("goIdentifier(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
@@ -0,0 +1,323 @@
+package orm
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// schemaConn 包装结构同步使用的连接：执行模式下逐条执行并记录变更，plan 模式下只记录不执行。
+// 每条变更同时记录对应的回滚语句，用于生成版本化迁移文件。
+type schemaConn struct {
+	*sqlx.DB
+	plan      bool
+	changes   []SchemaChange
+	newTables map[string]struct{}
+	// renamed 记录 plan 模式下已计划但未执行的列改名（表|旧列名 => 新列名），后续变更需使用新列名。
+	renamed map[string]string
+}
+
+// 结构变更类型。
+const (
+	SchemaCreateTable  = "create_table"
+	SchemaResetTable   = "reset_table"
+	SchemaAddColumn    = "add_column"
+	SchemaRenameColumn = "rename_column"
+	SchemaAlterColumn  = "alter_column"
+	SchemaDropColumn   = "drop_column"
+	SchemaCreateIndex  = "create_index"
+	SchemaDropIndex    = "drop_index"
+	SchemaSeed         = "seed"
+	SchemaSequence     = "sequence"
+	// 外键变更与 sqlite 为调整外键而进行的重建表。
+	SchemaAddForeignKey  = "add_foreign_key"
+	SchemaDropForeignKey = "drop_foreign_key"
+	SchemaRebuildTable   = "rebuild_table"
+)
+
+// SchemaChange 描述一次结构变更。Irreversible 表示无法自动生成回滚语句（如删表重建），
+// Destructive 表示执行后可能丢失数据：删表重建、删除列、收窄列类型。
+type SchemaChange struct {
+	Table        string   `json:"table"`
+	Kind         string   `json:"kind"`
+	Up           []string `json:"up"`
+	Down         []string `json:"down,omitempty"`
+	Irreversible bool     `json:"irreversible,omitempty"`
+	Destructive  bool     `json:"destructive,omitempty"`
+}
+
+// SchemaPlan 是 PlanSchemas 计算出的待执行变更，不包含任何已执行的操作。
+type SchemaPlan struct {
+	Database string         `json:"database"`
+	Driver   string         `json:"driver"`
+	Changes  []SchemaChange `json:"changes"`
+}
+
+// PlanSchemas 对比 data/table 中记录的全部表结构与数据库现状，计算待执行的语句但不执行。
+func PlanSchemas(ctx context.Context, dbName string) (SchemaPlan, error) {
+	ctx = normalizeContext(ctx)
+	dbName = migrationDatabase(dbName)
+	db, err := Get(dbName)
+	if err != nil {
+		return SchemaPlan{}, err
+	}
+	changes, err := planRecordedSchemas(ctx, db)
+	if err != nil {
+		return SchemaPlan{}, err
+	}
+	return SchemaPlan{Database: dbName, Driver: normalizeDriver(db.DriverName()), Changes: changes}, nil
+}
+
+// Destructive 返回计划中可能丢失数据的变更。
+func (p SchemaPlan) Destructive() []SchemaChange {
+	var result []SchemaChange
+	for _, change := range p.Changes {
+		if change.Destructive {
+			result = append(result, change)
+		}
+	}
+	return result
+}
+
+// Statements 按执行顺序返回全部语句。
+func (p SchemaPlan) Statements() []string {
+	var result []string
+	for _, change := range p.Changes {
+		result = append(result, change.Up...)
+	}
+	return result
+}
+
+// SQL 将计划渲染为可审阅的 SQL 文件内容，破坏性变更前附加 DESTRUCTIVE 注释。
+func (p SchemaPlan) SQL() string {
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "-- Generated by dever migrate --plan\n-- Database: %s, Driver: %s\n", p.Database, p.Driver)
+	fmt.Fprintf(&builder, "-- Changes: %d, Destructive: %d\n", len(p.Changes), len(p.Destructive()))
+	for _, change := range p.Changes {
+		mark := "safe"
+		if change.Destructive {
+			mark = "DESTRUCTIVE"
+		}
+		fmt.Fprintf(&builder, "\n-- [%s] %s %s\n", mark, change.Kind, change.Table)
+		for _, stmt := range change.Up {
+			builder.WriteString(strings.TrimSuffix(stmt, ";") + ";\n")
+		}
+	}
+	return builder.String()
+}
+
+func newSchemaConn(db *sqlx.DB, plan bool) *schemaConn {
+	return &schemaConn{DB: db, plan: plan, newTables: map[string]struct{}{}, renamed: map[string]string{}}
+}
+
+func (c *schemaConn) apply(ctx context.Context, change SchemaChange) error {
+	if !c.plan {
+		for _, stmt := range change.Up {
+			if _, err := c.ExecContext(ctx, stmt); err != nil {
+				return err
+			}
+		}
+	}
+	c.record(change)
+	return nil
+}
+
+func (c *schemaConn) record(change SchemaChange) {
+	c.changes = append(c.changes, change)
+}
+
+func (c *schemaConn) markRenamed(table, oldName, newName string) {
+	if c.plan {
+		c.renamed[strings.ToLower(table)+"|"+strings.ToLower(oldName)] = newName
+	}
+}
+
+// columnName 返回 plan 模式下计划改名后的列名，数据库中仍为旧列名。
+func (c *schemaConn) columnName(table, name string) string {
+	if renamed, ok := c.renamed[strings.ToLower(table)+"|"+strings.ToLower(name)]; ok {
+		return renamed
+	}
+	return name
+}
+
+func (c *schemaConn) markCreated(table string) {
+	c.newTables[strings.ToLower(table)] = struct{}{}
+}
+
+// created 判断表是否在 plan 模式下刚被计划创建，此时数据库中还不存在，不能读取其索引等状态。
+func (c *schemaConn) created(table string) bool {
+	if !c.plan {
+		return false
+	}
+	_, ok := c.newTables[strings.ToLower(table)]
+	return ok
+}
+
+// existingSQLType 将 loadExistingColumnStates 读取到的类型转换为可用于 DDL 的写法。
+func existingSQLType(driver, columnType string) string {
+	columnType = strings.TrimSpace(columnType)
+	if driver == "postgres" && strings.EqualFold(columnType, "DOUBLE") {
+		return "DOUBLE PRECISION"
+	}
+	return columnType
+}
+
+func buildIndexStatement(driver, table, indexName string, columns []string, unique bool) string {
+	quoted := make([]string, 0, len(columns))
+	for _, column := range columns {
+		quoted = append(quoted, quoteIdentifier(driver, column))
+	}
+	return buildCreateIndexStatement(driver, indexName, table, quoted, unique)
+}
+
+func sortedIndexKeys[V any](items map[string]V) []string {
+	keys := make([]string, 0, len(items))
+	for key := range items {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// renderSeedInsert 以字面量形式渲染种子数据插入语句，用于写入迁移文件。
+func renderSeedInsert(driver, table string, row map[string]any) string {
+	columns := sortedKeys(row)
+	names := make([]string, 0, len(columns))
+	values := make([]string, 0, len(columns))
+	for _, column := range columns {
+		names = append(names, quoteIdentifier(driver, column))
+		values = append(values, sqlLiteral(driver, row[column]))
+	}
+	return fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)", quoteIdentifier(driver, table), strings.Join(names, ", "), strings.Join(values, ", "))
+}
+
+func sqlLiteral(driver string, value any) string {
+	switch v := value.(type) {
+	case nil:
+		return "NULL"
+	case bool:
+		if driver == "postgres" {
+			return strconv.FormatBool(v)
+		}
+		if v {
+			return "1"
+		}
+		return "0"
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		return fmt.Sprint(v)
+	case time.Time:
+		return formatDefaultValue(v.Format("2006-01-02 15:04:05"), false)
+	case *time.Time:
+		if v == nil {
+			return "NULL"
+		}
+		return sqlLiteral(driver, *v)
+	case []byte:
+		return formatDefaultValue(string(v), false)
+	default:
+		return formatDefaultValue(fmt.Sprint(v), false)
+	}
+}
+
+// columnTypeNarrows 判断列类型从 actual 改为 desired 是否可能截断或丢失数据；无法识别的类型按收窄处理。
+func columnTypeNarrows(actual, desired string) bool {
+	from, to := parseColumnType(actual), parseColumnType(desired)
+	if from.family == "" || to.family == "" {
+		return true
+	}
+	if from.family != to.family {
+		switch {
+		case to.family == "text":
+			// 数值与时间的文本形式不超过 64 个字符。
+			return to.size < 64
+		case from.family == "bool":
+			return to.family != "int"
+		case from.family == "int":
+			return to.family != "float" && to.family != "decimal"
+		default:
+			return true
+		}
+	}
+	if from.family == "decimal" {
+		return to.size < from.size || to.scale < from.scale
+	}
+	return to.size < from.size
+}
+
+type columnTypeInfo struct {
+	family string
+	size   int64
+	scale  int64
+}
+
+// parseColumnType 解析列类型的类别与容量：整数、浮点按宽度，字符串按长度，小数按精度与小数位。
+func parseColumnType(columnType string) columnTypeInfo {
+	normalized := normalizeColumnType(columnType)
+	base, args := normalized, []int64(nil)
+	if open := strings.Index(normalized, "("); open >= 0 && strings.HasSuffix(normalized, ")") {
+		base = normalized[:open]
+		for _, part := range strings.Split(normalized[open+1:len(normalized)-1], ",") {
+			value, err := strconv.ParseInt(strings.TrimSpace(part), 10, 64)
+			if err != nil {
+				return columnTypeInfo{}
+			}
+			args = append(args, value)
+		}
+	}
+	base = strings.TrimSuffix(base, "UNSIGNED")
+	arg := func(index int, fallback int64) int64 {
+		if index < len(args) {
+			return args[index]
+		}
+		return fallback
+	}
+	switch base {
+	case "BOOLEAN":
+		return columnTypeInfo{family: "bool", size: 1}
+	case "TINYINT":
+		if arg(0, 0) == 1 {
+			return columnTypeInfo{family: "bool", size: 1}
+		}
+		return columnTypeInfo{family: "int", size: 1}
+	case "SMALLINT":
+		return columnTypeInfo{family: "int", size: 2}
+	case "MEDIUMINT":
+		return columnTypeInfo{family: "int", size: 3}
+	case "INTEGER":
+		return columnTypeInfo{family: "int", size: 4}
+	case "BIGINT":
+		return columnTypeInfo{family: "int", size: 8}
+	case "REAL", "FLOAT":
+		return columnTypeInfo{family: "float", size: 4}
+	case "DOUBLE":
+		return columnTypeInfo{family: "float", size: 8}
+	case "DECIMAL", "NUMERIC":
+		return columnTypeInfo{family: "decimal", size: arg(0, 65), scale: arg(1, 0)}
+	case "CHAR", "VARCHAR":
+		return columnTypeInfo{family: "text", size: arg(0, math.MaxInt64)}
+	case "TINYTEXT":
+		return columnTypeInfo{family: "text", size: 255}
+	case "TEXT":
+		return columnTypeInfo{family: "text", size: 65535}
+	case "MEDIUMTEXT":
+		return columnTypeInfo{family: "text", size: 16777215}
+	case "LONGTEXT":
+		return columnTypeInfo{family: "text", size: math.MaxInt64}
+	case "JSON", "JSONB":
+		return columnTypeInfo{family: "json", size: 1}
+	case "BLOB", "BYTEA", "LONGBLOB":
+		return columnTypeInfo{family: "blob", size: 1}
+	case "DATE":
+		return columnTypeInfo{family: "time", size: 1}
+	case "DATETIME", "TIMESTAMP", "TIMESTAMPTZ":
+		return columnTypeInfo{family: "time", size: 2}
+	default:
+		return columnTypeInfo{}
+	}
+}
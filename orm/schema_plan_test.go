@@ -0,0 +1,57 @@
+package orm
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+)
+
+func TestPlanUsesRenamedColumnsAndRestoresDroppedColumns(t *testing.T) {
+	ctx := context.Background()
+	db, err := sqlx.Open("sqlite3", "file:plan_rename?mode=memory&cache=shared")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	for _, stmt := range []string{
+		`CREATE TABLE "team" ("id" INTEGER PRIMARY KEY)`,
+		`CREATE TABLE "member" ("id" INTEGER PRIMARY KEY, "teamId" INTEGER, "legacy" INTEGER NOT NULL DEFAULT 0)`,
+	} {
+		if _, err := db.Exec(stmt); err != nil {
+			t.Fatal(err)
+		}
+	}
+	schema := &tableSchema{
+		Table: "member",
+		Columns: []columnDef{
+			{Name: "id", Type: "INTEGER", Primary: true, AutoIncrement: true},
+			{Name: "team_id", Type: "INTEGER"},
+		},
+		ForeignKeys: []foreignKeyDef{{Column: "team_id", RefTable: "team", RefColumn: "id"}},
+	}
+	conn := newSchemaConn(db, true)
+	if _, err := syncTableSchema(ctx, conn, "sqlite", "member", schema); err != nil {
+		t.Fatal(err)
+	}
+
+	byKind := map[string]SchemaChange{}
+	for _, change := range conn.changes {
+		byKind[change.Kind] = change
+	}
+	if got := byKind[SchemaRenameColumn].Up; len(got) != 1 || !strings.Contains(got[0], `RENAME COLUMN "teamId" TO "team_id"`) {
+		t.Fatalf("rename = %q", got)
+	}
+	drop := byKind[SchemaDropColumn]
+	if want := `ALTER TABLE "member" ADD COLUMN "legacy" INTEGER NOT NULL DEFAULT 0`; len(drop.Down) != 1 || drop.Down[0] != want {
+		t.Fatalf("drop column down = %q, want %q", drop.Down, want)
+	}
+	rebuild := byKind[SchemaRebuildTable].Up
+	if len(rebuild) < 2 || !strings.Contains(rebuild[1], `SELECT "id", "team_id" FROM "member"`) {
+		t.Fatalf("rebuild copies from pre-rename column: %q", rebuild)
+	}
+	if _, err := db.Exec(`SELECT "teamId" FROM "member"`); err != nil {
+		t.Fatalf("plan mode changed the database: %v", err)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"hash/fnv"
 	"os"
 	"path/filepath"
 	"reflect"
@@ -117,21 +118,21 @@ func cloneSeedRows(rows []map[string]any) []map[string]any {
 
 // RegisterSchema 注册表结构并同步索引信息，可传入额外的索引结构用于描述复合索引。
 func RegisterSchema(table string, model any, indexModels ...any) error {
-	return registerSchemaWithOptions(table, model, indexModels, nil)
+	return registerSchemaWithOptions(table, model, schemaOptions{indexes: indexModels})
 }
 
 // RegisterSchemaWithSeeds 注册表结构并附带默认数据。
 func RegisterSchemaWithSeeds(table string, model any, seeds []map[string]any, indexModels ...any) error {
-	return registerSchemaWithOptions(table, model, indexModels, seeds)
+	return registerSchemaWithOptions(table, model, schemaOptions{indexes: indexModels, seeds: seeds})
 }
 
-func registerSchemaWithOptions(table string, model any, indexModels []any, seeds []map[string]any) error {
+func registerSchemaWithOptions(table string, model any, options schemaOptions) error {
 	table = strings.TrimSpace(table)
 	if table == "" {
 		return errors.New("orm: table name required for registration")
 	}
 	shouldReset := shouldResetTableOnMissingSchemaFile(table)
-	schema, err := buildSchema(table, model, indexModels, seeds)
+	schema, err := buildSchema(table, model, options)
 	if err != nil {
 		return err
 	}
@@ -216,7 +217,7 @@ func registerSchemaOnce(table string, model any, options schemaOptions) error {
 	}
 	entry, _ := schemaOnceMap.LoadOrStore(lower, &schemaOnceEntry{})
 	entry.once.Do(func() {
-		entry.err = registerSchemaWithOptions(table, model, options.indexes, options.seeds)
+		entry.err = registerSchemaWithOptions(table, model, options)
 	})
 	return entry.err
 }
@@ -224,6 +225,8 @@ func registerSchemaOnce(table string, model any, options schemaOptions) error {
 type schemaOptions struct {
 	indexes []any
 	seeds   []map[string]any
+	// database 用于给外键引用的表名加上与模型相同的表前缀。
+	database string
 }
 
 func getRegisteredSchema(table string) (*tableSchema, bool) {
@@ -247,7 +250,7 @@ func getRegisteredSchema(table string) (*tableSchema, bool) {
 	return loaded, true
 }
 
-func buildSchema(table string, model any, indexModels []any, seeds []map[string]any) (*tableSchema, error) {
+func buildSchema(table string, model any, options schemaOptions) (*tableSchema, error) {
 	t := reflect.TypeOf(model)
 	if t == nil {
 		return nil, fmt.Errorf("orm: model for %s must not be nil", table)
@@ -261,13 +264,10 @@ func buildSchema(table string, model any, indexModels []any, seeds []map[string]
 
 	columns := make([]columnDef, 0, t.NumField())
 	indexMap := map[string]*indexDef{}
+	var foreignKeys []foreignKeyDef
 
-	for i := 0; i < t.NumField(); i++ {
-		field := t.Field(i)
-		if field.PkgPath != "" {
-			continue
-		}
-		col, indexes, skip, err := parseField(table, field)
+	for _, field := range schemaFields(t) {
+		col, indexes, foreignKey, skip, err := parseField(table, field)
 		if err != nil {
 			return nil, err
 		}
@@ -275,6 +275,10 @@ func buildSchema(table string, model any, indexModels []any, seeds []map[string]
 			continue
 		}
 		columns = append(columns, col)
+		if foreignKey != nil {
+			foreignKey.RefTable = applyTablePrefix(foreignKey.RefTable, options.database)
+			foreignKeys = append(foreignKeys, *foreignKey)
+		}
 		for _, idx := range indexes {
 			existing, ok := indexMap[idx.Name]
 			if ok {
@@ -310,7 +314,7 @@ func buildSchema(table string, model any, indexModels []any, seeds []map[string]
 		}
 	}
 
-	extraIndexes, err := parseIndexModels(table, columns, indexModels...)
+	extraIndexes, err := parseIndexModels(table, columns, options.indexes...)
 	if err != nil {
 		return nil, err
 	}
@@ -329,15 +333,17 @@ func buildSchema(table string, model any, indexModels []any, seeds []map[string]
 	for _, idx := range indexMap {
 		indexes = append(indexes, *idx)
 	}
+	indexes = withForeignKeyIndexes(table, columns, indexes, foreignKeys)
 	sort.Slice(indexes, func(i, j int) bool {
 		return indexes[i].Name < indexes[j].Name
 	})
 
 	schema := &tableSchema{
-		Table:   table,
-		Columns: columns,
-		Indexes: indexes,
-		Seeds:   cloneSeedRows(seeds),
+		Table:       table,
+		Columns:     columns,
+		Indexes:     indexes,
+		ForeignKeys: foreignKeys,
+		Seeds:       cloneSeedRows(options.seeds),
 	}
 	schema.ensureLookup()
 	return schema, nil
@@ -453,10 +459,10 @@ func buildCustomIndexName(table string, field reflect.StructField, unique bool)
 	if unique {
 		prefix = "uidx"
 	}
-	return fmt.Sprintf("%s_%s_%s", prefix, util.ToSnake(table), base)
+	return limitIdentifier(fmt.Sprintf("%s_%s_%s", prefix, util.ToSnake(table), base))
 }
 
-func parseField(table string, field reflect.StructField) (columnDef, []indexDef, bool, error) {
+func parseField(table string, field reflect.StructField) (columnDef, []indexDef, *foreignKeyDef, bool, error) {
 	var empty columnDef
 	dormTag := field.Tag.Get("dorm")
 	columnName := ""
@@ -465,8 +471,8 @@ func parseField(table string, field reflect.StructField) (columnDef, []indexDef,
 	}
 
 	tagOptions := parseDormTag(dormTag)
-	if tagExists(tagOptions, "-") {
-		return empty, nil, true, nil
+	if tagExists(tagOptions, "-") || tagExists(tagOptions, "relation") {
+		return empty, nil, nil, true, nil
 	}
 	if colName := util.FirstNonEmpty(tagOptions["column"]...); colName != "" {
 		columnName = colName
@@ -475,12 +481,12 @@ func parseField(table string, field reflect.StructField) (columnDef, []indexDef,
 		columnName = util.ToSnake(field.Name)
 	}
 	if err := ensureIdentifier(columnName); err != nil {
-		return empty, nil, false, err
+		return empty, nil, nil, false, err
 	}
 
 	sqlType, nullable, err := inferSQLType(field, tagOptions)
 	if err != nil {
-		return empty, nil, false, err
+		return empty, nil, nil, false, err
 	}
 
 	col := columnDef{
@@ -490,6 +496,8 @@ func parseField(table string, field reflect.StructField) (columnDef, []indexDef,
 		NotNull:       !nullable,
 		AutoIncrement: tagExists(tagOptions, "autoincrement"),
 		Primary:       tagExists(tagOptions, "primarykey"),
+		Tenant:        tagExists(tagOptions, "tenant"),
+		Exact:         tagExists(tagOptions, "decimal"),
 	}
 
 	if tagExists(tagOptions, "not null") || tagExists(tagOptions, "notnull") {
@@ -499,18 +507,37 @@ func parseField(table string, field reflect.StructField) (columnDef, []indexDef,
 		col.NotNull = false
 	}
 
+	if values, ok := tagOptions["autocreatetime"]; ok {
+		col.AutoCreate = normalizeAutoTimeMode(util.FirstNonEmpty(values...), col.Type)
+	}
+	if values, ok := tagOptions["autoupdatetime"]; ok {
+		col.AutoUpdate = normalizeAutoTimeMode(util.FirstNonEmpty(values...), col.Type)
+	}
+	if values, ok := tagOptions["softdelete"]; ok {
+		col.SoftDelete = normalizeSoftDeleteMode(util.FirstNonEmpty(values...), col.Type)
+		if col.SoftDelete == softDeleteTime {
+			col.NotNull = false
+		}
+	}
+
 	if defaultStr, raw, ok := resolveColumnDefaultValue(field, tagOptions, col.NotNull); ok {
 		col.DefaultValue = &defaultStr
 		col.DefaultIsRaw = raw
 	}
 
 	indexes := collectIndexes(table, columnName, tagOptions)
-	return col, indexes, false, nil
+	foreignKey, err := parseForeignKey(table, columnName, tagOptions)
+	if err != nil {
+		return empty, nil, nil, false, err
+	}
+	return col, indexes, foreignKey, false, nil
 }
 
 func inferSQLType(field reflect.StructField, options map[string][]string) (string, bool, error) {
 	if custom := util.FirstNonEmpty(options["type"]...); custom != "" {
-		return strings.ToUpper(custom), false, nil
+		custom = strings.ToUpper(custom)
+		// JSON 列与推断的 JSON 类型一致，按指针判断是否可空。
+		return custom, isJSONColumnType(custom) && field.Type.Kind() == reflect.Pointer, nil
 	}
 
 	ft := field.Type
@@ -520,10 +547,19 @@ func inferSQLType(field reflect.StructField, options map[string][]string) (strin
 		ft = ft.Elem()
 	}
 
-	switch ft {
-	case reflect.TypeOf(time.Time{}):
+	if tagExists(options, "decimal") {
+		sqlType, err := parseDecimalType(field, options)
+		return sqlType, nullable, err
+	}
+	if ft == timeType {
 		return "TIMESTAMP", nullable, nil
 	}
+	if isJSONFieldType(ft) {
+		return "JSON", nullable, nil
+	}
+	if ft.Kind() == reflect.Slice && ft.Elem().Kind() == reflect.Uint8 {
+		return "BLOB", nullable, nil
+	}
 
 	switch ft.Kind() {
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
@@ -540,7 +576,7 @@ func inferSQLType(field reflect.StructField, options map[string][]string) (strin
 	case reflect.Bool:
 		return "BOOLEAN", nullable, nil
 	case reflect.Struct:
-		return "", false, fmt.Errorf("orm: unsupported embedded struct %s for schema", field.Name)
+		return "", false, fmt.Errorf("orm: field %s of type %s requires dorm:\"type:...\" or dorm:\"decimal:...\"", field.Name, ft.String())
 	default:
 		return "", false, fmt.Errorf("orm: unsupported field type %s for schema", ft.String())
 	}
@@ -617,7 +653,21 @@ func defaultIndexName(table, column string, unique bool) string {
 	if unique {
 		prefix = "uidx"
 	}
-	return fmt.Sprintf("%s_%s_%s", prefix, util.ToSnake(table), column)
+	return limitIdentifier(fmt.Sprintf("%s_%s_%s", prefix, util.ToSnake(table), column))
+}
+
+// maxIdentifierLength 取 postgres（63 字节）与 mysql（64 字节）标识符上限中较小者。
+const maxIdentifierLength = 63
+
+// limitIdentifier 将超长的自动生成名截断并追加 8 位哈希，保证不超过 maxIdentifierLength 且不同原名不会撞名。
+func limitIdentifier(name string) string {
+	if len(name) <= maxIdentifierLength {
+		return name
+	}
+	hash := fnv.New32a()
+	_, _ = hash.Write([]byte(name))
+	suffix := fmt.Sprintf("_%08x", hash.Sum32())
+	return strings.TrimRight(name[:maxIdentifierLength-len(suffix)], "_") + suffix
 }
 
 func normalizeIndexName(name string) string {
@@ -691,6 +741,9 @@ func resolveColumnDefaultValue(field reflect.StructField, options map[string][]s
 	if fieldType.Kind() != reflect.String {
 		return "", false, false
 	}
+	if tagExists(options, "decimal") {
+		return "0", false, true
+	}
 	return "", false, true
 }
 
@@ -744,10 +797,11 @@ func cloneSchemaForPersistence(schema *tableSchema) *tableSchema {
 	}
 
 	cloned := &tableSchema{
-		Table:   schema.Table,
-		Columns: append([]columnDef(nil), schema.Columns...),
-		Indexes: append([]indexDef(nil), schema.Indexes...),
-		Seeds:   cloneSeedRows(schema.Seeds),
+		Table:       schema.Table,
+		Columns:     append([]columnDef(nil), schema.Columns...),
+		Indexes:     append([]indexDef(nil), schema.Indexes...),
+		ForeignKeys: append([]foreignKeyDef(nil), schema.ForeignKeys...),
+		Seeds:       cloneSeedRows(schema.Seeds),
 	}
 	return cloned
 }
@@ -800,8 +854,8 @@ func listRecordedSchemas() ([]*tableSchema, error) {
 			if err != nil {
 				return nil, err
 			}
-			var schema tableSchema
-			if err := json.Unmarshal(data, &schema); err != nil {
+			schema := &tableSchema{}
+			if err := json.Unmarshal(data, schema); err != nil {
 				return nil, err
 			}
 			tableName := strings.ToLower(strings.TrimSpace(schema.Table))
@@ -813,8 +867,7 @@ func listRecordedSchemas() ([]*tableSchema, error) {
 			}
 			schema.Table = strings.TrimSpace(schema.Table)
 			seen[tableName] = struct{}{}
-			schemaCopy := schema
-			schemas = append(schemas, &schemaCopy)
+			schemas = append(schemas, schema)
 		}
 	}
 	sort.Slice(schemas, func(i, j int) bool {
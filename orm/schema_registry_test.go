@@ -0,0 +1,101 @@
+package orm
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TypeMoney 是自行实现数据库读写的小数类型，模拟第三方 decimal 库。
+type TypeMoney struct{ cents int64 }
+
+func (m *TypeMoney) Scan(value any) error {
+	_, err := fmt.Sscanf(fmt.Sprint(value), "%d", &m.cents)
+	return err
+}
+
+func (m TypeMoney) Value() (driver.Value, error) { return m.cents, nil }
+
+// TypeStamps 作为匿名嵌入结构体展开为父级列。
+type TypeStamps struct {
+	Name      string `dorm:"size:8"`
+	CreatedAt time.Time
+	UpdatedAt *time.Time
+}
+
+type typeSample struct {
+	TypeStamps
+	ID      uint64 `dorm:"primaryKey;autoIncrement"`
+	Name    string `dorm:"size:32"`
+	Tags    []string
+	Meta    map[string]any
+	Profile struct{ Age int }
+	Raw     json.RawMessage
+	OptMeta *map[string]any
+	Extra   map[string]any `dorm:"null"`
+	Doc     string         `dorm:"type:json"`
+	DocPtr  *string        `dorm:"type:json"`
+	Data    []byte
+	DataPtr *[]byte
+	Price   string    `dorm:"decimal:10,2"`
+	Amount  TypeMoney `dorm:"decimal"`
+	Total   float64   `dorm:"type:decimal(10,2)"`
+}
+
+func TestBuildSchemaInfersColumnTypes(t *testing.T) {
+	schema, err := buildSchema("type_sample", typeSample{}, schemaOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, col := range schema.Columns {
+		desc := col.Name + " " + col.Type
+		if col.NotNull {
+			desc += " NOT NULL"
+		}
+		if col.Exact {
+			desc += " exact"
+		}
+		if col.DefaultValue != nil {
+			desc += " default=" + *col.DefaultValue
+		}
+		got = append(got, desc)
+	}
+	want := []string{
+		// 外层同名字段覆盖嵌入字段，嵌入的其余字段按出现位置展开。
+		"name VARCHAR(32) NOT NULL default=",
+		"created_at TIMESTAMP NOT NULL",
+		"updated_at TIMESTAMP",
+		"id BIGINT NOT NULL",
+		"tags JSON NOT NULL",
+		"meta JSON NOT NULL",
+		"profile JSON NOT NULL",
+		"raw JSON NOT NULL",
+		"opt_meta JSON",
+		"extra JSON",
+		"doc JSON NOT NULL default=",
+		"doc_ptr JSON",
+		"data BLOB NOT NULL",
+		"data_ptr BLOB",
+		"price DECIMAL(10,2) NOT NULL exact default=0",
+		"amount DECIMAL(18,4) NOT NULL exact",
+		"total DECIMAL(10,2) NOT NULL",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("columns =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestBuildSchemaRejectsUntypedStructs(t *testing.T) {
+	type scannerWithoutType struct {
+		ID     uint64
+		Amount TypeMoney
+	}
+	if _, err := buildSchema("bad_sample", scannerWithoutType{}, schemaOptions{}); err == nil || !strings.Contains(err.Error(), "requires dorm") {
+		t.Fatalf("err = %v, want a type/decimal requirement", err)
+	}
+}
@@ -16,6 +16,12 @@ type columnDef struct {
 	AutoIncrement bool    `json:"autoIncrement"`
 	DefaultValue  *string `json:"defaultValue,omitempty"`
 	DefaultIsRaw  bool    `json:"defaultIsRaw,omitempty"`
+	SoftDelete    string  `json:"softDelete,omitempty"`
+	Tenant        bool    `json:"tenant,omitempty"`
+	AutoCreate    string  `json:"autoCreate,omitempty"`
+	AutoUpdate    string  `json:"autoUpdate,omitempty"`
+	// Exact 表示通过 dorm:"decimal" 声明的定点小数，查询结果保持字符串。
+	Exact bool `json:"exact,omitempty"`
 }
 
 type indexDef struct {
@@ -25,10 +31,21 @@ type indexDef struct {
 	Type    string   `json:"type,omitempty"`
 }
 
+// foreignKeyDef 描述外键约束，OnDelete/OnUpdate 为空时使用数据库默认行为（NO ACTION）。
+type foreignKeyDef struct {
+	Name      string `json:"name"`
+	Column    string `json:"column"`
+	RefTable  string `json:"refTable"`
+	RefColumn string `json:"refColumn"`
+	OnDelete  string `json:"onDelete,omitempty"`
+	OnUpdate  string `json:"onUpdate,omitempty"`
+}
+
 type tableSchema struct {
 	Table           string               `json:"table"`
 	Columns         []columnDef          `json:"columns"`
 	Indexes         []indexDef           `json:"indexes,omitempty"`
+	ForeignKeys     []foreignKeyDef      `json:"foreignKeys,omitempty"`
 	Seeds           []map[string]any     `json:"seeds,omitempty"`
 	UpdatedAt       time.Time            `json:"updatedAt"`
 	columnLookup    map[string]string    `json:"-"`
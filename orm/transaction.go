@@ -2,53 +2,226 @@ package orm
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
+	"math/rand/v2"
+	"sync"
+	"time"
 
 	"github.com/jmoiron/sqlx"
 )
 
+const (
+	defaultTxBackoff = 20 * time.Millisecond
+	maxTxBackoff     = time.Second
+)
+
 type txContextKey struct{}
 
+// TxOptions 描述事务选项。Isolation/ReadOnly 与重试只对最外层事务生效，嵌套调用使用 SAVEPOINT。
+type TxOptions struct {
+	Isolation sql.IsolationLevel
+	ReadOnly  bool
+	// Retries 为串行化失败或死锁时重新执行回调的次数，回调需可重复执行。
+	Retries int
+	// Backoff 为首次重试前的等待时间，之后按指数递增，默认 20ms。
+	Backoff time.Duration
+}
+
+type txState struct {
+	tx          *sqlx.Tx
+	dbName      string
+	mu          sync.Mutex
+	savepoints  int
+	afterCommit []func(context.Context)
+}
+
 // Transaction 在指定数据库连接上开启事务，并将事务句柄注入到上下文中。
-// 若上下文已存在事务，则直接复用，避免重复开启。
+// 若上下文已存在事务，则在该事务内创建 SAVEPOINT，回调失败只回滚到该保存点；
+// 嵌套调用显式指定了与外层不同的数据库时返回错误，不会复用外层事务。
 func Transaction(ctx context.Context, fn func(context.Context) error, name ...string) error {
+	return TransactionWith(ctx, TxOptions{}, fn, name...)
+}
+
+// TransactionWith 按选项开启事务；遇到串行化失败或死锁时按 Retries/Backoff 重新执行整个回调。
+func TransactionWith(ctx context.Context, opts TxOptions, fn func(context.Context) error, name ...string) error {
 	if fn == nil {
 		return fmt.Errorf("orm: transaction callback required")
 	}
-	if ctx == nil {
-		ctx = context.Background()
-	}
-	if current := txFromContext(ctx); current != nil {
-		return fn(ctx)
+	ctx = normalizeContext(ctx)
+	if state := txStateFromContext(ctx); state != nil {
+		if len(name) > 0 && name[0] != "" && name[0] != state.dbName {
+			return fmt.Errorf("orm: cannot nest transaction on database %q inside transaction on %q", name[0], state.dbName)
+		}
+		return state.savepoint(ctx, fn)
 	}
-	db, err := Get(name...)
+	dbName := transactionDatabase(name)
+	db, err := Get(dbName)
 	if err != nil {
 		return err
 	}
+	for attempt := 0; ; attempt++ {
+		panicked, err := runTransaction(ctx, db, dbName, opts, fn)
+		if err == nil {
+			return nil
+		}
+		if attempt >= opts.Retries || !IsRetryable(err) || !waitTxBackoff(ctx, opts.Backoff, attempt) {
+			if panicked {
+				panic(err)
+			}
+			return err
+		}
+	}
+}
+
+// BeginTx 开启事务并返回绑定该事务的上下文，由调用方通过返回的 *sqlx.Tx 提交或回滚；
+// 适用于测试夹具等无法使用回调的场景，业务代码应使用 Transaction。
+// 注意：通过返回的 *sqlx.Tx 提交时不会执行 AfterCommit 回调（包括查询缓存失效）。
+func BeginTx(ctx context.Context, name ...string) (context.Context, *sqlx.Tx, error) {
+	ctx = normalizeContext(ctx)
+	if txStateFromContext(ctx) != nil {
+		return nil, nil, fmt.Errorf("orm: context already in transaction")
+	}
+	dbName := transactionDatabase(name)
+	db, err := Get(dbName)
+	if err != nil {
+		return nil, nil, err
+	}
 	tx, err := db.BeginTxx(ctx, nil)
 	if err != nil {
-		return err
+		return nil, nil, err
+	}
+	return context.WithValue(ctx, txContextKey{}, &txState{tx: tx, dbName: dbName}), tx, nil
+}
+
+// transactionDatabase 返回事务所在的数据库名，未指定时为默认库。
+func transactionDatabase(name []string) string {
+	if len(name) > 0 && name[0] != "" {
+		return name[0]
 	}
-	txCtx := context.WithValue(ctx, txContextKey{}, tx)
+	return currentDefaultDatabase()
+}
+
+// AfterCommit 注册在最外层事务提交成功后执行的回调；回滚（含回滚到保存点）时丢弃。
+// 上下文不在事务中时立即执行。
+func AfterCommit(ctx context.Context, fn func(context.Context)) {
+	if fn == nil {
+		return
+	}
+	ctx = normalizeContext(ctx)
+	state := txStateFromContext(ctx)
+	if state == nil {
+		fn(ctx)
+		return
+	}
+	state.mu.Lock()
+	state.afterCommit = append(state.afterCommit, fn)
+	state.mu.Unlock()
+}
+
+// runTransaction 执行一次完整事务；回调以可重试错误 panic 时返回该错误并标记 panicked，交由外层决定重试或继续 panic。
+func runTransaction(ctx context.Context, db *sqlx.DB, dbName string, opts TxOptions, fn func(context.Context) error) (panicked bool, err error) {
+	var txOpts *sql.TxOptions
+	if opts.Isolation != sql.LevelDefault || opts.ReadOnly {
+		txOpts = &sql.TxOptions{Isolation: opts.Isolation, ReadOnly: opts.ReadOnly}
+	}
+	tx, err := db.BeginTxx(ctx, txOpts)
+	if err != nil {
+		return false, err
+	}
+	state := &txState{tx: tx, dbName: dbName}
+	txCtx := context.WithValue(ctx, txContextKey{}, state)
 	defer func() {
 		if r := recover(); r != nil {
 			_ = tx.Rollback()
+			if recovered, ok := r.(error); ok && IsRetryable(recovered) {
+				panicked, err = true, recovered
+				return
+			}
 			panic(r)
 		}
 	}()
 	if err := fn(txCtx); err != nil {
 		_ = tx.Rollback()
+		return false, err
+	}
+	if err := tx.Commit(); err != nil {
+		return false, err
+	}
+	for _, callback := range state.afterCommit {
+		callback(ctx)
+	}
+	return false, nil
+}
+
+func (s *txState) savepoint(ctx context.Context, fn func(context.Context) error) error {
+	s.mu.Lock()
+	s.savepoints++
+	name := fmt.Sprintf("dever_sp_%d", s.savepoints)
+	mark := len(s.afterCommit)
+	s.mu.Unlock()
+
+	if _, err := s.tx.ExecContext(ctx, "SAVEPOINT "+name); err != nil {
 		return err
 	}
-	return tx.Commit()
+	defer func() {
+		if r := recover(); r != nil {
+			_ = s.rollbackTo(ctx, name, mark)
+			panic(r)
+		}
+	}()
+	if err := fn(ctx); err != nil {
+		if rollbackErr := s.rollbackTo(ctx, name, mark); rollbackErr != nil {
+			return errors.Join(err, rollbackErr)
+		}
+		return err
+	}
+	_, err := s.tx.ExecContext(ctx, "RELEASE SAVEPOINT "+name)
+	return err
 }
 
-func txFromContext(ctx context.Context) *sqlx.Tx {
+func (s *txState) rollbackTo(ctx context.Context, name string, mark int) error {
+	s.mu.Lock()
+	if len(s.afterCommit) > mark {
+		s.afterCommit = s.afterCommit[:mark]
+	}
+	s.mu.Unlock()
+	_, err := s.tx.ExecContext(context.WithoutCancel(ctx), "ROLLBACK TO SAVEPOINT "+name)
+	return err
+}
+
+// waitTxBackoff 按指数退避并附加随机抖动等待，上下文取消时返回 false。
+func waitTxBackoff(ctx context.Context, base time.Duration, attempt int) bool {
+	if base <= 0 {
+		base = defaultTxBackoff
+	}
+	delay := base << min(attempt, 16)
+	if delay <= 0 || delay > maxTxBackoff {
+		delay = maxTxBackoff
+	}
+	delay += rand.N(delay/2 + 1)
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+func txStateFromContext(ctx context.Context) *txState {
 	if ctx == nil {
 		return nil
 	}
-	if tx, ok := ctx.Value(txContextKey{}).(*sqlx.Tx); ok {
-		return tx
+	state, _ := ctx.Value(txContextKey{}).(*txState)
+	return state
+}
+
+func txFromContext(ctx context.Context) *sqlx.Tx {
+	if state := txStateFromContext(ctx); state != nil {
+		return state.tx
 	}
 	return nil
 }
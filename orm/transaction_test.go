@@ -0,0 +1,218 @@
+package orm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// setupTxDatabase 初始化一个文件 sqlite 库并建表 tx_item(name)，busy_timeout 为 0 以便立即观察锁冲突。
+func setupTxDatabase(t *testing.T, name string) *sqlx.DB {
+	t.Helper()
+	db, err := Init(name, Config{
+		Driver: "sqlite3",
+		Path:   filepath.Join(t.TempDir(), name+".db"),
+		Params: map[string]string{"_busy_timeout": "0"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = Close(name) })
+	if _, err := db.Exec(`CREATE TABLE "tx_item" ("name" VARCHAR(32))`); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func insertTxItem(ctx context.Context, name string) error {
+	_, err := txFromContext(ctx).ExecContext(ctx, `INSERT INTO "tx_item" ("name") VALUES (?)`, name)
+	return err
+}
+
+func txItemNames(t *testing.T, db *sqlx.DB) string {
+	t.Helper()
+	var names []string
+	if err := db.Select(&names, `SELECT "name" FROM "tx_item" ORDER BY "name"`); err != nil {
+		t.Fatal(err)
+	}
+	return strings.Join(names, ",")
+}
+
+func TestSavepointsRollBackOnlyTheInnerCallback(t *testing.T) {
+	db := setupTxDatabase(t, "tx_savepoint")
+	errInner := errors.New("inner failed")
+	var events []string
+
+	err := Transaction(context.Background(), func(ctx context.Context) error {
+		if err := insertTxItem(ctx, "a"); err != nil {
+			return err
+		}
+		AfterCommit(ctx, func(context.Context) { events = append(events, "outer") })
+		err := Transaction(ctx, func(ctx context.Context) error {
+			AfterCommit(ctx, func(context.Context) { events = append(events, "rolled-back") })
+			if err := insertTxItem(ctx, "b"); err != nil {
+				return err
+			}
+			return errInner
+		})
+		if !errors.Is(err, errInner) {
+			return fmt.Errorf("inner err = %v", err)
+		}
+		return Transaction(ctx, func(ctx context.Context) error {
+			AfterCommit(ctx, func(context.Context) { events = append(events, "released") })
+			if err := insertTxItem(ctx, "c"); err != nil {
+				return err
+			}
+			// 第二层嵌套 panic 只回滚到自己的保存点。
+			func() {
+				defer func() { _ = recover() }()
+				_ = Transaction(ctx, func(ctx context.Context) error {
+					_ = insertTxItem(ctx, "d")
+					panic("boom")
+				})
+			}()
+			return nil
+		})
+	}, "tx_savepoint")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := txItemNames(t, db); got != "a,c" {
+		t.Fatalf("rows = %s, want a,c", got)
+	}
+	if got := strings.Join(events, ","); got != "outer,released" {
+		t.Fatalf("after commit = %s, want outer,released", got)
+	}
+}
+
+func TestTransactionRetriesRetryableErrors(t *testing.T) {
+	setupTxDatabase(t, "tx_retry")
+	ctx := context.Background()
+	opts := TxOptions{Retries: 2, Backoff: time.Millisecond}
+
+	attempts := 0
+	err := TransactionWith(ctx, opts, func(ctx context.Context) error {
+		attempts++
+		if attempts < 3 {
+			return fmt.Errorf("%w: conflict", ErrSerialization)
+		}
+		return nil
+	}, "tx_retry")
+	if err != nil || attempts != 3 {
+		t.Fatalf("attempts = %d, err = %v, want success on the third attempt", attempts, err)
+	}
+
+	// panic 风格 API 抛出的可重试错误同样会重试。
+	attempts = 0
+	err = TransactionWith(ctx, opts, func(ctx context.Context) error {
+		attempts++
+		if attempts < 3 {
+			panicOnError(ErrDeadlock)
+		}
+		return nil
+	}, "tx_retry")
+	if err != nil || attempts != 3 {
+		t.Fatalf("attempts = %d, err = %v, want success on the third attempt", attempts, err)
+	}
+
+	attempts = 0
+	err = TransactionWith(ctx, opts, func(ctx context.Context) error {
+		attempts++
+		return ErrDeadlock
+	}, "tx_retry")
+	if !errors.Is(err, ErrDeadlock) || attempts != 3 {
+		t.Fatalf("attempts = %d, err = %v, want the error after 1+Retries attempts", attempts, err)
+	}
+
+	attempts = 0
+	errPlain := errors.New("plain")
+	err = TransactionWith(ctx, opts, func(ctx context.Context) error {
+		attempts++
+		return errPlain
+	}, "tx_retry")
+	if !errors.Is(err, errPlain) || attempts != 1 {
+		t.Fatalf("attempts = %d, err = %v, want no retry for non-retryable errors", attempts, err)
+	}
+}
+
+func TestWaitTxBackoffGrowsAndStopsOnCancel(t *testing.T) {
+	start := time.Now()
+	if !waitTxBackoff(context.Background(), 5*time.Millisecond, 2) {
+		t.Fatal("backoff should complete")
+	}
+	if elapsed := time.Since(start); elapsed < 20*time.Millisecond {
+		t.Fatalf("attempt 2 waited %s, want at least 4x the base", elapsed)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if waitTxBackoff(ctx, time.Second, 0) {
+		t.Fatal("canceled context should stop the backoff")
+	}
+}
+
+func TestSQLiteBusyMapsToSerialization(t *testing.T) {
+	db := setupTxDatabase(t, "tx_busy")
+	err := Transaction(context.Background(), func(ctx context.Context) error {
+		if err := insertTxItem(ctx, "a"); err != nil {
+			return err
+		}
+		// 另一个连接在写锁被占用时写入，busy_timeout 为 0 立即返回 SQLITE_BUSY。
+		_, err := db.Exec(`INSERT INTO "tx_item" ("name") VALUES ('b')`)
+		err = normalizeError(err)
+		if !errors.Is(err, ErrSerialization) || !IsRetryable(err) {
+			return fmt.Errorf("busy err = %v, want ErrSerialization", err)
+		}
+		return nil
+	}, "tx_busy")
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBeginTxSkipsAfterCommit(t *testing.T) {
+	db := setupTxDatabase(t, "tx_begin")
+	ctx, tx, err := BeginTx(context.Background(), "tx_begin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	called := false
+	AfterCommit(ctx, func(context.Context) { called = true })
+	if err := insertTxItem(ctx, "a"); err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	if called {
+		t.Fatal("BeginTx commit should not run AfterCommit callbacks")
+	}
+	if got := txItemNames(t, db); got != "a" {
+		t.Fatalf("rows = %s", got)
+	}
+	if _, _, err := BeginTx(ctx, "tx_begin"); err == nil {
+		t.Fatal("BeginTx inside a transaction context should fail")
+	}
+}
+
+func TestNestedTransactionRejectsOtherDatabase(t *testing.T) {
+	setupTxDatabase(t, "tx_outer")
+	setupTxDatabase(t, "tx_other")
+	err := Transaction(context.Background(), func(ctx context.Context) error {
+		if err := Transaction(ctx, func(context.Context) error { return nil }, "tx_other"); err == nil {
+			return errors.New("nested transaction on another database should fail")
+		}
+		if err := Transaction(ctx, func(context.Context) error { return nil }, "tx_outer"); err != nil {
+			return err
+		}
+		return Transaction(ctx, func(context.Context) error { return nil })
+	}, "tx_outer")
+	if err != nil {
+		t.Fatal(err)
+	}
+}
@@ -473,6 +473,40 @@ func (c *Context) Query(key string, defaultValue ...string) string {
 	return ""
 }
 
+// Pagination 是从请求中解析出的分页参数，字段与 orm.PageRequest 对应。
+type Pagination struct {
+	Page   int
+	Size   int
+	Cursor string
+}
+
+// Pagination 读取 page、pageSize（兼容 size）与 cursor 参数；
+// 非法值回退为第 1 页和 defaultSize，maxSize > 0 时限制每页上限。
+func (c *Context) Pagination(defaultSize, maxSize int) Pagination {
+	if defaultSize <= 0 {
+		defaultSize = 20
+	}
+	result := Pagination{Page: 1, Size: defaultSize}
+	if c == nil {
+		return result
+	}
+	if page, err := strconv.Atoi(c.Input("page")); err == nil && page > 0 {
+		result.Page = page
+	}
+	size := c.Input("pageSize")
+	if size == "" {
+		size = c.Input("size")
+	}
+	if parsed, err := strconv.Atoi(size); err == nil && parsed > 0 {
+		result.Size = parsed
+	}
+	if maxSize > 0 && result.Size > maxSize {
+		result.Size = maxSize
+	}
+	result.Cursor = c.Input("cursor")
+	return result
+}
+
 // Method 返回请求的 HTTP 方法。
 func (c *Context) Method() string {
 	if c == nil || c.Raw == nil {